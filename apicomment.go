@@ -0,0 +1,305 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddComment adds a comment of author made at date on the whole paragraph,
+// the date is omitted if zero
+func (p *Paragraph) AddComment(author, initials string, date time.Time, text string) *Comment {
+	c := p.file.commentsPart().newComment(author, initials, date, text)
+	children := make([]interface{}, 0, len(p.Children)+3)
+	children = append(children, &CommentRangeStart{ID: c.ID})
+	children = append(children, p.Children...)
+	p.Children = append(children, &CommentRangeEnd{ID: c.ID}, newCommentReferenceRun(c.ID, p.file))
+	return c
+}
+
+// Comments returns all the comments of the document, replies included
+func (f *Docx) Comments() []*Comment {
+	f = f.root()
+	if f.comments == nil {
+		return nil
+	}
+	return f.comments.Comments
+}
+
+// Comment gets the comment by its id (or nil on notfound)
+func (f *Docx) Comment(id int) *Comment {
+	for _, c := range f.Comments() {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// Reply adds a reply of author made at date to the comment,
+// it is anchored with the comment in all the parts of the document
+func (c *Comment) Reply(author, initials string, date time.Time, text string) *Comment {
+	f := c.file.root()
+	part := f.commentsPart()
+	if c.paraID == "" {
+		c.paraID = part.newParaID()
+	}
+	r := part.newComment(author, initials, date, text)
+	r.parentParaID = c.paraID
+	f.walkPlaces(func(p *Paragraph, _ *cellPlace) bool {
+		children := make([]interface{}, 0, len(p.Children)+3)
+		for _, pc := range p.Children {
+			children = append(children, pc)
+			switch o := pc.(type) {
+			case *CommentRangeStart:
+				if o.ID == c.ID {
+					children = append(children, &CommentRangeStart{ID: r.ID})
+				}
+			case *CommentRangeEnd:
+				if o.ID == c.ID {
+					children = append(children, &CommentRangeEnd{ID: r.ID})
+				}
+			case *Run:
+				for _, rc := range o.Children {
+					if ref, ok := rc.(*CommentReference); ok && ref.ID == c.ID {
+						children = append(children, newCommentReferenceRun(r.ID, p.file))
+						break
+					}
+				}
+			}
+		}
+		p.Children = children
+		return false
+	})
+	return r
+}
+
+// Parent returns the comment replied by c (or nil if c is not a reply)
+func (c *Comment) Parent() *Comment {
+	if c.parentParaID == "" {
+		return nil
+	}
+	for _, o := range c.file.Comments() {
+		if o.paraID == c.parentParaID {
+			return o
+		}
+	}
+	return nil
+}
+
+// Resolved tells whether the comment is marked as done
+func (c *Comment) Resolved() bool {
+	return c.done
+}
+
+// Resolve marks the comment as done
+func (c *Comment) Resolve(val ...bool) *Comment {
+	c.done = len(val) == 0 || val[0]
+	if c.paraID == "" {
+		c.paraID = c.file.root().commentsPart().newParaID()
+	}
+	return c
+}
+
+// AddParagraph adds a new paragraph
+func (c *Comment) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     c.file,
+	}
+	c.Items = append(c.Items, p)
+	return p
+}
+
+// Paragraphs returns the paragraphs of the comment
+func (c *Comment) Paragraphs() []*Paragraph {
+	ps := make([]*Paragraph, 0, len(c.Items))
+	for _, item := range c.Items {
+		if p, ok := item.(*Paragraph); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// String returns the text of the paragraphs of the comment, one per line
+func (c *Comment) String() string {
+	sb := strings.Builder{}
+	for i, p := range c.Paragraphs() {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(p.String())
+	}
+	return sb.String()
+}
+
+func newCommentReferenceRun(id int, file *Docx) *Run {
+	return &Run{
+		Children: []interface{}{&CommentReference{ID: id}},
+		file:     file,
+	}
+}
+
+func (f *Docx) commentsPart() *Comments {
+	f = f.root()
+	if f.comments == nil {
+		f.comments = &Comments{
+			XMLW:   XMLNS_W,
+			XMLR:   XMLNS_R,
+			XMLWP:  XMLNS_WP,
+			XMLW14: XMLNS_W14,
+			name:   COMMENTS_PART,
+			file:   f.newPart(),
+		}
+		f.addRelation(REL_COMMENTS, COMMENTS_PART[len(WORD_FOLDER):])
+	}
+	return f.comments
+}
+
+// newComment appends a comment with text
+func (c *Comments) newComment(author, initials string, date time.Time, text string) *Comment {
+	nc := &Comment{
+		ID:       c.nextID(),
+		Author:   author,
+		Initials: initials,
+		Items:    make([]interface{}, 0, 4),
+		paraID:   c.newParaID(),
+		file:     c.file,
+	}
+	if !date.IsZero() {
+		nc.Date = date.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	p := nc.AddParagraph()
+	p.Children = append(p.Children, &Run{Children: []interface{}{&AnnotationRef{}}, file: c.file})
+	if text != "" {
+		p.AddText(text)
+	}
+	c.Comments = append(c.Comments, nc)
+	return nc
+}
+
+func (c *Comments) nextID() int {
+	id := 0
+	for _, o := range c.Comments {
+		if o.ID >= id {
+			id = o.ID + 1
+		}
+	}
+	return id
+}
+
+// newParaID returns an unused w14:paraId
+func (c *Comments) newParaID() string {
+	for i := len(c.Comments) + 1; ; i++ {
+		id := strings.ToUpper(strconv.FormatInt(int64(i), 16))
+		id = strings.Repeat("0", 8-len(id)) + id
+		if !c.hasParaID(id) {
+			return id
+		}
+	}
+}
+
+func (c *Comments) hasParaID(id string) bool {
+	for _, o := range c.Comments {
+		if strings.EqualFold(o.paraID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// extended returns the extended comments of the part (or nil if there is none)
+func (c *Comments) extended() *CommentsEx {
+	var ex *CommentsEx
+	for _, o := range c.Comments {
+		if o.paraID == "" {
+			continue
+		}
+		if ex == nil {
+			ex = &CommentsEx{XMLW15: XMLNS_W15}
+		}
+		done := "0"
+		if o.done {
+			done = "1"
+		}
+		ex.Items = append(ex.Items, &CommentEx{ParaID: o.paraID, ParaIDParent: o.parentParaID, Done: done})
+	}
+	return ex
+}
+
+// applyExtended sets the reply and resolved state of the comments
+func (c *Comments) applyExtended(ex *CommentsEx) {
+	for _, e := range ex.Items {
+		for _, o := range c.Comments {
+			if strings.EqualFold(o.paraID, e.ParaID) {
+				o.parentParaID = e.ParaIDParent
+				o.done = e.Done == "1" || e.Done == "true"
+				break
+			}
+		}
+	}
+}
+
+// copyComment copies the comment id of from to f, returns its new id
+//
+// A comment is only copied once even if it is referred several times.
+func (f *Docx) copyComment(from *Docx, id int) int {
+	if from == nil {
+		return id
+	}
+	c := from.Comment(id)
+	if c == nil {
+		return id
+	}
+	f = f.root()
+	if nc, ok := f.commentCopies[c]; ok {
+		return nc.ID
+	}
+	part := f.commentsPart()
+	nc := *c
+	nc.file = part.file
+	nc.Items = copyItems(c.Items, part.file)
+	if part.hasID(id) {
+		nc.ID = part.nextID()
+	}
+	if nc.paraID != "" && part.hasParaID(nc.paraID) {
+		nc.paraID = part.newParaID()
+	}
+	nc.parentParaID = ""
+	if pc, ok := f.commentCopies[c.Parent()]; ok {
+		nc.parentParaID = pc.paraID
+	}
+	part.Comments = append(part.Comments, &nc)
+	if f.commentCopies == nil {
+		f.commentCopies = make(map[*Comment]*Comment, 16)
+	}
+	f.commentCopies[c] = &nc
+	return nc.ID
+}
+
+func (c *Comments) hasID(id int) bool {
+	for _, o := range c.Comments {
+		if o.ID == id {
+			return true
+		}
+	}
+	return false
+}
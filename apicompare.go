@@ -0,0 +1,844 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type _change string
+
+const (
+	CHANGE_INSERTED  _change = "inserted"  // The item or the text only exists in the new document
+	CHANGE_DELETED   _change = "deleted"   // The item or the text only exists in the old document
+	CHANGE_MODIFIED  _change = "modified"  // The text of the item has been changed
+	CHANGE_FORMATTED _change = "formatted" // Only the formatting of the item has been changed
+)
+
+// maxDiffCells bounds the size of the table of the longest common subsequence,
+// beyond it the differing middle parts are entirely deleted and inserted
+const maxDiffCells = 1 << 22
+
+// maxPairing is the number of inserted items looked ahead
+// to find the new version of a deleted item
+const maxPairing = 16
+
+// CompareOptions are the options of Compare
+type CompareOptions struct {
+	Author string    // Author of the revisions, "Compare" if empty
+	Date   time.Time // Date of the revisions, now if zero
+
+	IgnoreFormatting bool // IgnoreFormatting disables the comparison of the run and paragraph properties
+}
+
+// Change is a difference between the body items of the compared documents
+type Change struct {
+	Kind _change
+	// OldIndex and NewIndex are the indexes of the item in the body
+	// of the old and of the new document, -1 if it is missing in one of them
+	OldIndex int
+	NewIndex int
+	Table    bool // Table tells whether the item is a table
+	OldText  string
+	NewText  string
+	// Words are the texts inserted and deleted in a modified paragraph
+	Words []WordChange
+}
+
+// WordChange is a text inserted or deleted in a modified paragraph
+type WordChange struct {
+	Kind _change // CHANGE_INSERTED or CHANGE_DELETED
+	Text string
+}
+
+// Compare compares the bodies of the documents old and new.
+//
+// The paragraphs and the tables are aligned, the paragraphs which are found
+// in both documents are compared word by word and the cells of the tables
+// with the same shape are compared paragraph by paragraph.
+//
+// It returns a new document, based on new, holding the differences as
+// tracked changes and the list of the differences.
+func Compare(oldDoc, newDoc *Docx, opts *CompareOptions) (*Docx, []Change) {
+	if opts == nil {
+		opts = &CompareOptions{}
+	}
+	author := opts.Author
+	if author == "" {
+		author = "Compare"
+	}
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	f := newDoc.derive()
+	// the new revisions follow the ones of both documents
+	var max int
+	for _, doc := range []*Docx{oldDoc, newDoc} {
+		doc.walkParagraphs(func(p *Paragraph) {
+			if id := maxRevisionID(p); id > max {
+				max = id
+			}
+		})
+		for _, item := range doc.Document.Body.Items {
+			if t, ok := item.(*Table); ok {
+				if id := maxRowRevisionID(t); id > max {
+					max = id
+				}
+			}
+		}
+	}
+	f.useIDs(map[string]int{ID_REVISION: max})
+	f.TrackChanges(author, date)
+	c := &comparer{f: f, old: oldDoc, new: newDoc, format: !opts.IgnoreFormatting}
+	changes := make([]Change, 0, 16)
+	f.Document.Body.Items = c.items(oldDoc.Document.Body.Items, newDoc.Document.Body.Items, &changes)
+	f.StopTrackingChanges()
+	return f, changes
+}
+
+// comparer builds the compared document f
+type comparer struct {
+	f      *Docx
+	old    *Docx
+	new    *Docx
+	format bool
+}
+
+// items compares the items and returns the compared ones,
+// the changes are recorded in changes if it is not nil
+func (c *comparer) items(olds, news []interface{}, changes *[]Change) []interface{} {
+	okeys := make([]string, len(olds))
+	for i, o := range olds {
+		okeys[i] = itemKey(o)
+	}
+	nkeys := make([]string, len(news))
+	for i, o := range news {
+		nkeys[i] = itemKey(o)
+	}
+	out := make([]interface{}, 0, len(news))
+	var dels, ins []int
+	for _, op := range diffKeys(okeys, nkeys) {
+		switch op.op {
+		case '-':
+			dels = append(dels, op.i)
+		case '+':
+			ins = append(ins, op.j)
+		default:
+			out = c.gap(out, olds, news, dels, ins, changes)
+			dels, ins = dels[:0], ins[:0]
+			out = c.same(out, olds[op.i], news[op.j], op.i, op.j, changes)
+		}
+	}
+	if len(dels) > 0 && len(ins) > 0 {
+		// the last paragraphs of the container are always paired
+		o, ok1 := olds[dels[len(dels)-1]].(*Paragraph)
+		n, ok2 := news[ins[len(ins)-1]].(*Paragraph)
+		if ok1 && ok2 {
+			out = c.gap(out, olds, news, dels[:len(dels)-1], ins[:len(ins)-1], changes)
+			out = c.modified(out, o, n, dels[len(dels)-1], ins[len(ins)-1], changes)
+			return out
+		}
+	}
+	out = c.gap(out, olds, news, dels, ins, changes)
+	shiftMarks(out)
+	return out
+}
+
+// shiftMarks moves the revisions of the marks of the trailing paragraphs
+// to their previous paragraph: the mark of the last paragraph can't be
+// removed as there is no following paragraph to merge into
+func shiftMarks(out []interface{}) {
+	k := len(out) - 1
+	for ; k >= 0; k-- {
+		if _, ok := out[k].(*Paragraph); ok {
+			break
+		}
+		if _, ok := out[k].(*Table); ok {
+			return
+		}
+	}
+	i := k
+	for ; i >= 0; i-- {
+		p, ok := out[i].(*Paragraph)
+		if !ok || p.Properties == nil || p.Properties.RunProperties == nil ||
+			(p.Properties.RunProperties.Ins == nil && p.Properties.RunProperties.Del == nil) {
+			break
+		}
+	}
+	if i < 0 || i == k {
+		return
+	}
+	if _, ok := out[i].(*Paragraph); !ok {
+		return
+	}
+	for ; i < k; i++ {
+		next := out[i+1].(*Paragraph).Properties.RunProperties
+		setMarks(out[i].(*Paragraph), next.Ins, next.Del)
+	}
+	setMarks(out[k].(*Paragraph), nil, nil)
+}
+
+// setMarks sets the revisions of the paragraph mark
+func setMarks(p *Paragraph, ins, del *RevisionMark) {
+	pp := ParagraphProperties{}
+	if p.Properties != nil {
+		pp = *p.Properties
+	}
+	rp := RunProperties{}
+	if pp.RunProperties != nil {
+		rp = *pp.RunProperties
+	}
+	rp.Ins, rp.Del = ins, del
+	pp.RunProperties = &rp
+	p.Properties = &pp
+}
+
+// gap appends the deleted and inserted items between two identical ones,
+// a deleted item is paired with the first similar inserted item
+func (c *comparer) gap(out, olds, news []interface{}, dels, ins []int, changes *[]Change) []interface{} {
+	j := 0
+	for _, i := range dels {
+		k := -1
+		for jj := j; jj < len(ins) && jj < j+maxPairing; jj++ {
+			if similar(olds[i], news[ins[jj]]) {
+				k = jj
+				break
+			}
+		}
+		if k < 0 {
+			out = c.deleted(out, olds[i], i, changes)
+			continue
+		}
+		for ; j < k; j++ {
+			out = c.inserted(out, news[ins[j]], ins[j], changes)
+		}
+		out = c.modified(out, olds[i], news[ins[k]], i, ins[k], changes)
+		j = k + 1
+	}
+	for ; j < len(ins); j++ {
+		out = c.inserted(out, news[ins[j]], ins[j], changes)
+	}
+	return out
+}
+
+// same appends the item which has the same text in both documents
+func (c *comparer) same(out []interface{}, o, n interface{}, i, j int, changes *[]Change) []interface{} {
+	switch x := n.(type) {
+	case *Paragraph:
+		if !c.format {
+			np := x.copymedia(c.f)
+			return append(out, &np)
+		}
+		np, _, changed := c.paragraph(o.(*Paragraph), x)
+		if changed {
+			record(changes, Change{Kind: CHANGE_FORMATTED, OldIndex: i, NewIndex: j, OldText: x.String(), NewText: x.String()})
+		}
+		return append(out, np)
+	case *Table:
+		if !c.format || !sameShape(o.(*Table), x) {
+			nt := x.copymedia(c.f)
+			return append(out, &nt)
+		}
+		nt, changed := c.table(o.(*Table), x)
+		if changed {
+			text := tableText(x)
+			record(changes, Change{Kind: CHANGE_FORMATTED, OldIndex: i, NewIndex: j, Table: true, OldText: text, NewText: text})
+		}
+		return append(out, nt)
+	case *SectPr:
+		return append(out, x.copymedia(c.f))
+	}
+	return append(out, n)
+}
+
+// modified appends the new version of a paragraph or of a table
+func (c *comparer) modified(out []interface{}, o, n interface{}, i, j int, changes *[]Change) []interface{} {
+	switch x := n.(type) {
+	case *Paragraph:
+		np, words, _ := c.paragraph(o.(*Paragraph), x)
+		record(changes, Change{Kind: CHANGE_MODIFIED, OldIndex: i, NewIndex: j, OldText: o.(*Paragraph).String(), NewText: x.String(), Words: words})
+		return append(out, np)
+	case *Table:
+		nt, _ := c.table(o.(*Table), x)
+		record(changes, Change{Kind: CHANGE_MODIFIED, OldIndex: i, NewIndex: j, Table: true, OldText: tableText(o.(*Table)), NewText: tableText(x)})
+		return append(out, nt)
+	}
+	return append(out, n)
+}
+
+// inserted appends the item of the new document as inserted
+func (c *comparer) inserted(out []interface{}, n interface{}, j int, changes *[]Change) []interface{} {
+	switch x := n.(type) {
+	case *Paragraph:
+		np := x.copymedia(c.f)
+		np.Children = c.insertChildren(np.Children)
+		c.mark(&np, REVISION_INS)
+		record(changes, Change{Kind: CHANGE_INSERTED, OldIndex: -1, NewIndex: j, NewText: x.String()})
+		return append(out, &np)
+	case *Table:
+		nt := x.copymedia(c.f)
+		for _, tr := range nt.Rows {
+			c.markRow(tr, REVISION_INS)
+		}
+		for _, p := range nt.paragraphs() {
+			p.Children = c.insertChildren(p.Children)
+			c.mark(p, REVISION_INS)
+		}
+		record(changes, Change{Kind: CHANGE_INSERTED, OldIndex: -1, NewIndex: j, Table: true, NewText: tableText(x)})
+		return append(out, &nt)
+	case *SectPr:
+		return append(out, x.copymedia(c.f))
+	}
+	return append(out, n)
+}
+
+// deleted appends the item of the old document as deleted
+func (c *comparer) deleted(out []interface{}, o interface{}, i int, changes *[]Change) []interface{} {
+	switch x := o.(type) {
+	case *Paragraph:
+		np := x.copymedia(c.f)
+		np.Children = c.f.deleteChildren(np.Children)
+		c.mark(&np, REVISION_DEL)
+		record(changes, Change{Kind: CHANGE_DELETED, OldIndex: i, NewIndex: -1, OldText: x.String()})
+		return append(out, &np)
+	case *Table:
+		nt := x.copymedia(c.f)
+		for _, tr := range nt.Rows {
+			c.markRow(tr, REVISION_DEL)
+		}
+		for _, p := range nt.paragraphs() {
+			p.Children = c.f.deleteChildren(p.Children)
+			c.mark(p, REVISION_DEL)
+		}
+		record(changes, Change{Kind: CHANGE_DELETED, OldIndex: i, NewIndex: -1, Table: true, OldText: tableText(x)})
+		return append(out, &nt)
+	}
+	// the other items of the old document are dropped
+	return out
+}
+
+// table compares the cells of two tables of the same shape
+func (c *comparer) table(o, n *Table) (*Table, bool) {
+	nt := *n
+	nt.Rows = make([]*WTableRow, 0, len(n.Rows))
+	nt.file = c.f
+	var changes []Change
+	for r, tr := range n.Rows {
+		ntr := *tr
+		ntr.Cells = make([]*WTableCell, 0, len(tr.Cells))
+		ntr.file = c.f
+		for k, tc := range tr.Cells {
+			ntc := *tc
+			ntc.file = c.f
+			olds := o.Rows[r].Cells[k].Paragraphs
+			oitems := make([]interface{}, len(olds))
+			for i, p := range olds {
+				oitems[i] = p
+			}
+			nitems := make([]interface{}, len(tc.Paragraphs))
+			for i, p := range tc.Paragraphs {
+				nitems[i] = p
+			}
+			items := c.items(oitems, nitems, &changes)
+			ntc.Paragraphs = make([]*Paragraph, 0, len(items))
+			for _, item := range items {
+				if p, ok := item.(*Paragraph); ok {
+					ntc.Paragraphs = append(ntc.Paragraphs, p)
+				}
+			}
+			ntr.Cells = append(ntr.Cells, &ntc)
+		}
+		nt.Rows = append(nt.Rows, &ntr)
+	}
+	return &nt, len(changes) > 0
+}
+
+// paragraph compares two versions of a paragraph word by word,
+// it returns the compared paragraph, the changed words and
+// whether anything has been changed
+func (c *comparer) paragraph(o, n *Paragraph) (*Paragraph, []WordChange, bool) {
+	otoks, ntoks := paragraphTokens(o), paragraphTokens(n)
+	okeys := make([]string, len(otoks))
+	for i, t := range otoks {
+		okeys[i] = t.key
+	}
+	nkeys := make([]string, len(ntoks))
+	for i, t := range ntoks {
+		nkeys[i] = t.key
+	}
+
+	np := &Paragraph{Properties: n.Properties, file: c.f}
+	b := paragraphBuilder{c: c, p: np}
+	var words []WordChange
+	for _, op := range diffKeys(okeys, nkeys) {
+		var kind _change
+		var t *diffToken
+		switch op.op {
+		case '-':
+			t, kind = &otoks[op.i], CHANGE_DELETED
+		case '+':
+			t, kind = &ntoks[op.j], CHANGE_INSERTED
+		default:
+			b.add(op.op, &ntoks[op.j], otoks[op.i].run)
+			continue
+		}
+		b.add(op.op, t, nil)
+		if t.text == "" {
+			continue
+		}
+		if len(words) > 0 && words[len(words)-1].Kind == kind {
+			words[len(words)-1].Text += t.text
+			continue
+		}
+		words = append(words, WordChange{Kind: kind, Text: t.text})
+	}
+	b.flush()
+
+	if c.format && !sameXML(plainParagraphProperties(o.Properties), plainParagraphProperties(n.Properties)) {
+		pp := ParagraphProperties{}
+		if n.Properties != nil {
+			pp = *n.Properties
+		}
+		old := plainParagraphProperties(o.Properties)
+		if old == nil {
+			old = &ParagraphProperties{}
+		}
+		t := c.f.tracking
+		pp.Change = &PPrChange{ID: c.f.newRevisionID(), Author: t.author, Date: t.date, ParagraphProperties: old}
+		np.Properties = &pp
+		b.changed = true
+	}
+	return np, words, b.changed
+}
+
+// mark marks the paragraph mark as inserted or deleted
+func (c *comparer) mark(p *Paragraph, kind _revision) {
+	if kind == REVISION_INS {
+		setMarks(p, c.f.newTrackedMark(), nil)
+	} else {
+		setMarks(p, nil, c.f.newTrackedMark())
+	}
+}
+
+// markRow marks the row as inserted or deleted,
+// its properties are copied as they are shared with the compared document
+func (c *comparer) markRow(tr *WTableRow, kind _revision) {
+	props := WTableRowProperties{}
+	if tr.Properties != nil {
+		props = *tr.Properties
+	}
+	if kind == REVISION_INS {
+		props.Ins = c.f.newTrackedMark()
+	} else {
+		props.Del = c.f.newTrackedMark()
+	}
+	tr.Properties = &props
+}
+
+// insertChildren wraps the runs of children into insertions
+func (c *comparer) insertChildren(children []interface{}) []interface{} {
+	nchildren := make([]interface{}, 0, len(children))
+	var ins *Revision
+	for _, ch := range children {
+		switch ch.(type) {
+		case *Run, *Hyperlink:
+			if ins == nil {
+				ins = c.f.newTrackedRevision(REVISION_INS)
+				nchildren = append(nchildren, ins)
+			}
+			ins.Children = append(ins.Children, ch)
+			continue
+		}
+		ins = nil
+		nchildren = append(nchildren, ch)
+	}
+	return nchildren
+}
+
+// paragraphBuilder gathers the compared tokens into the runs of p
+type paragraphBuilder struct {
+	c       *comparer
+	p       *Paragraph
+	changed bool
+
+	op  byte      // op is the operation of the pending run
+	src *Run      // src is the run of the pending tokens
+	old *Run      // old is the run of the identical tokens in the old document
+	run *Run      // run is the pending run
+	rev *Revision // rev is the last revision of p
+}
+
+// add adds the token, old is its run in the old document if it is identical
+func (b *paragraphBuilder) add(op byte, t *diffToken, old *Run) {
+	if t.run == nil {
+		b.flush()
+		tmp := Paragraph{Children: []interface{}{t.child}, file: t.file}
+		for _, ch := range tmp.copymedia(b.c.f).Children {
+			b.put(op, ch)
+		}
+		return
+	}
+	if b.run == nil || b.op != op || b.src != t.run || b.old != old {
+		b.flush()
+		b.op, b.src, b.old = op, t.run, old
+		r := *t.run
+		r.Children = make([]interface{}, 0, 4)
+		r.file = t.file
+		b.run = &r
+	}
+	if t.child != nil {
+		b.run.Children = append(b.run.Children, t.child)
+		return
+	}
+	if n := len(b.run.Children); n > 0 {
+		if x, ok := b.run.Children[n-1].(*Text); ok {
+			x.Text += t.text
+			return
+		}
+	}
+	b.run.Children = append(b.run.Children, &Text{XMLSpace: "preserve", Text: t.text})
+}
+
+// flush puts the pending run into the paragraph
+func (b *paragraphBuilder) flush() {
+	if b.run == nil {
+		return
+	}
+	r := b.run.copymedia(b.c.f)
+	if b.op == '=' && b.c.format && !sameXML(plainRunProperties(b.old.RunProperties), plainRunProperties(r.RunProperties)) {
+		rp := RunProperties{}
+		if r.RunProperties != nil {
+			rp = *r.RunProperties
+		}
+		old := plainRunProperties(b.old.RunProperties)
+		if old == nil {
+			old = &RunProperties{}
+		}
+		t := b.c.f.tracking
+		rp.Change = &RPrChange{ID: b.c.f.newRevisionID(), Author: t.author, Date: t.date, RunProperties: old}
+		r.RunProperties = &rp
+		b.changed = true
+	}
+	b.run, b.src, b.old = nil, nil, nil
+	b.put(b.op, r)
+}
+
+// put appends the child to the paragraph, within a revision if it is not identical
+func (b *paragraphBuilder) put(op byte, child interface{}) {
+	kind := REVISION_INS
+	switch o := child.(type) {
+	case *Run, *Hyperlink:
+		if op == '=' {
+			break
+		}
+		b.changed = true
+		if op == '-' {
+			kind = REVISION_DEL
+			deleteText(o)
+		}
+		if b.rev == nil || b.rev.Kind() != kind {
+			b.rev = b.c.f.newTrackedRevision(kind)
+			b.p.Children = append(b.p.Children, b.rev)
+		}
+		b.rev.Children = append(b.rev.Children, child)
+		return
+	case *Revision:
+		if k := o.Kind(); op == '-' && (k == REVISION_INS || k == REVISION_MOVE_TO) {
+			o.Children = b.c.f.deleteChildren(o.Children)
+		}
+	}
+	if op != '=' {
+		b.changed = true
+	}
+	b.rev = nil
+	b.p.Children = append(b.p.Children, child)
+}
+
+// diffToken is a word or a non textual child of a paragraph
+type diffToken struct {
+	key   string      // key is the compared value
+	text  string      // text is the word
+	child interface{} // child is the non textual child of the run or of the paragraph
+	run   *Run        // run is the run of the token, nil for a child of the paragraph
+	file  *Docx
+}
+
+// paragraphTokens splits the paragraph into tokens
+func paragraphTokens(p *Paragraph) []diffToken {
+	toks := make([]diffToken, 0, 64)
+	for _, c := range p.Children {
+		r, ok := c.(*Run)
+		if !ok {
+			toks = append(toks, diffToken{key: childKey(c, p.file), child: c, file: p.file})
+			continue
+		}
+		for _, rc := range r.Children {
+			if x, ok := rc.(*Text); ok {
+				for _, w := range splitWords(x.Text) {
+					toks = append(toks, diffToken{key: w, text: w, run: r, file: p.file})
+				}
+				continue
+			}
+			toks = append(toks, diffToken{key: childKey(rc, p.file), child: rc, run: r, file: p.file})
+		}
+	}
+	return toks
+}
+
+// childKey is the compared value of a non textual child
+func childKey(c interface{}, file *Docx) string {
+	switch o := c.(type) {
+	case *Tab:
+		return "\t"
+	case *BarterRabbet:
+		return "\n" + o.Type
+	case *Drawing:
+		if o.Inline != nil {
+			return o.Inline.String()
+		}
+		if o.Anchor != nil {
+			return o.Anchor.String()
+		}
+	case *Hyperlink, *Revision:
+		p := Paragraph{Children: []interface{}{c}, file: file}
+		return fmt.Sprintf("%T%s", c, p.String())
+	}
+	return fmt.Sprintf("%T", c)
+}
+
+// splitWords splits the text into words, spaces and punctuation marks,
+// each ideograph is a word
+func splitWords(s string) []string {
+	words := make([]string, 0, len(s)/4+1)
+	start := -1
+	class := 0
+	for i, r := range s {
+		var cl int
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			cl = 3
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			cl = 1
+		case unicode.IsSpace(r):
+			cl = 2
+		}
+		if start >= 0 && (cl != class || cl == 0 || cl == 3) {
+			words = append(words, s[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start, class = i, cl
+		}
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// itemKey is the compared value of a body item
+func itemKey(item interface{}) string {
+	switch o := item.(type) {
+	case *Paragraph:
+		return "p" + paragraphText(o)
+	case *Table:
+		return "t" + tableText(o)
+	}
+	return fmt.Sprintf("%T", item)
+}
+
+// tableText is the text of all the cells of the table
+func tableText(t *Table) string {
+	sb := strings.Builder{}
+	for i, r := range t.Rows {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for j, c := range r.Cells {
+			if j > 0 {
+				sb.WriteByte('\t')
+			}
+			for k, p := range c.Paragraphs {
+				if k > 0 {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(paragraphText(p))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// paragraphText is the text of the paragraph without its list label,
+// which changes when the previous items of the list are changed
+func paragraphText(p *Paragraph) string {
+	if p.label == "" {
+		return p.String()
+	}
+	np := *p
+	np.label = ""
+	return np.String()
+}
+
+// similar tells whether n can be considered as a new version of o
+func similar(o, n interface{}) bool {
+	switch x := o.(type) {
+	case *Paragraph:
+		y, ok := n.(*Paragraph)
+		if !ok {
+			return false
+		}
+		a, b := splitWords(paragraphText(x)), splitWords(paragraphText(y))
+		if len(a)+len(b) == 0 {
+			return true
+		}
+		same := 0
+		for _, op := range diffKeys(a, b) {
+			if op.op == '=' {
+				same++
+			}
+		}
+		return 2*same >= (len(a)+len(b))/2
+	case *Table:
+		y, ok := n.(*Table)
+		return ok && sameShape(x, y)
+	}
+	return false
+}
+
+// sameShape tells whether the tables have the same number of rows and of cells in each row
+func sameShape(a, b *Table) bool {
+	if len(a.Rows) != len(b.Rows) {
+		return false
+	}
+	for i := range a.Rows {
+		if len(a.Rows[i].Cells) != len(b.Rows[i].Cells) {
+			return false
+		}
+	}
+	return true
+}
+
+func plainRunProperties(rp *RunProperties) *RunProperties {
+	if rp == nil {
+		return nil
+	}
+	n := *rp
+	n.Ins, n.Del, n.Change = nil, nil, nil
+	return &n
+}
+
+func plainParagraphProperties(pp *ParagraphProperties) *ParagraphProperties {
+	if pp == nil {
+		return nil
+	}
+	n := *pp
+	n.Change = nil
+	n.RunProperties = plainRunProperties(pp.RunProperties)
+	return &n
+}
+
+// sameXML tells whether a and b are marshalled the same way
+func sameXML(a, b interface{}) bool {
+	xa, err := xml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	xb, err := xml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(xa) == string(xb)
+}
+
+func record(changes *[]Change, c Change) {
+	if changes != nil {
+		*changes = append(*changes, c)
+	}
+}
+
+// diffOp is an operation of the difference between two sequences:
+// '=' a[i] is b[j], '-' a[i] is deleted, '+' b[j] is inserted
+type diffOp struct {
+	op   byte
+	i, j int
+}
+
+// diffKeys returns the operations turning a into b
+// based on their longest common subsequence
+func diffKeys(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	p := 0
+	for p < len(a) && p < len(b) && a[p] == b[p] {
+		ops = append(ops, diffOp{op: '=', i: p, j: p})
+		p++
+	}
+	s := 0
+	for s < len(a)-p && s < len(b)-p && a[len(a)-1-s] == b[len(b)-1-s] {
+		s++
+	}
+	ma, mb := a[p:len(a)-s], b[p:len(b)-s]
+	n, m := len(ma), len(mb)
+	if n*m > maxDiffCells {
+		for i := 0; i < n; i++ {
+			ops = append(ops, diffOp{op: '-', i: p + i, j: -1})
+		}
+		for j := 0; j < m; j++ {
+			ops = append(ops, diffOp{op: '+', i: -1, j: p + j})
+		}
+	} else {
+		// l[i*(m+1)+j] is the length of the common subsequence of ma[i:] and mb[j:]
+		l := make([]int32, (n+1)*(m+1))
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				switch {
+				case ma[i] == mb[j]:
+					l[i*(m+1)+j] = l[(i+1)*(m+1)+j+1] + 1
+				case l[(i+1)*(m+1)+j] >= l[i*(m+1)+j+1]:
+					l[i*(m+1)+j] = l[(i+1)*(m+1)+j]
+				default:
+					l[i*(m+1)+j] = l[i*(m+1)+j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && ma[i] == mb[j]:
+				ops = append(ops, diffOp{op: '=', i: p + i, j: p + j})
+				i++
+				j++
+			case j == m || (i < n && l[(i+1)*(m+1)+j] >= l[i*(m+1)+j+1]):
+				ops = append(ops, diffOp{op: '-', i: p + i, j: -1})
+				i++
+			default:
+				ops = append(ops, diffOp{op: '+', i: -1, j: p + j})
+				j++
+			}
+		}
+	}
+	for k := s; k > 0; k-- {
+		ops = append(ops, diffOp{op: '=', i: len(a) - k, j: len(b) - k})
+	}
+	return ops
+}
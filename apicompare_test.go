@@ -0,0 +1,201 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func compareDocs() (*Docx, *Docx) {
+	old := New().WithDefaultTheme()
+	old.AddParagraph().AddText("The quick brown fox")
+	old.AddParagraph().AddText("jumps over")
+	old.AddParagraph().AddText("Removed paragraph")
+	old.AddParagraph().AddText("the lazy dog")
+	tbl := old.AddTable(1, 2, 2000)
+	tbl.Rows[0].Cells[0].AddParagraph().AddText("a1")
+	tbl.Rows[0].Cells[1].AddParagraph().AddText("b1")
+
+	cur := New().WithDefaultTheme()
+	cur.AddParagraph().AddText("The slow brown fox")
+	cur.AddParagraph().AddText("jumps over").Bold()
+	cur.AddParagraph().AddText("A brand new line")
+	cur.AddParagraph().AddText("the lazy dog")
+	tbl = cur.AddTable(1, 2, 2000)
+	tbl.Rows[0].Cells[0].AddParagraph().AddText("a1")
+	tbl.Rows[0].Cells[1].AddParagraph().AddText("b2")
+	return old, cur
+}
+
+func compareText(doc *Docx) string {
+	texts := make([]string, 0, len(doc.Document.Body.Items))
+	for _, item := range doc.Document.Body.Items {
+		switch o := item.(type) {
+		case *Paragraph:
+			texts = append(texts, o.String())
+		case *Table:
+			texts = append(texts, tableText(o))
+		}
+	}
+	return strings.Join(texts, "|")
+}
+
+func TestCompare(t *testing.T) {
+	old, cur := compareDocs()
+	doc, changes := Compare(old, cur, &CompareOptions{Author: "Reviewer", Date: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)})
+
+	kinds := make([]string, 0, len(changes))
+	for _, c := range changes {
+		kinds = append(kinds, string(c.Kind))
+	}
+	if k := strings.Join(kinds, ","); k != "modified,formatted,deleted,inserted,modified" {
+		t.Fatalf("unexpected changes %s", k)
+	}
+	c := changes[0]
+	if c.OldIndex != 0 || c.NewIndex != 0 || c.OldText != "The quick brown fox" || c.NewText != "The slow brown fox" {
+		t.Fatalf("unexpected change %+v", c)
+	}
+	if len(c.Words) != 2 || c.Words[0] != (WordChange{CHANGE_DELETED, "quick"}) || c.Words[1] != (WordChange{CHANGE_INSERTED, "slow"}) {
+		t.Fatalf("unexpected words %+v", c.Words)
+	}
+	if c := changes[2]; c.OldIndex != 2 || c.NewIndex != -1 || c.OldText != "Removed paragraph" {
+		t.Fatalf("unexpected deletion %+v", c)
+	}
+	if c := changes[3]; c.OldIndex != -1 || c.NewIndex != 2 {
+		t.Fatalf("unexpected insertion %+v", c)
+	}
+	if c := changes[4]; !c.Table || c.OldIndex != 4 || c.NewIndex != 4 || c.OldText != "a1\tb1" {
+		t.Fatalf("unexpected table change %+v", c)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := xml.NewEncoder(buf).Encode(&doc.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		`<w:del w:id="1" w:author="Reviewer" w:date="2025-01-02T03:04:05Z"><w:r><w:delText xml:space="preserve">quick</w:delText></w:r></w:del>`,
+		`<w:ins w:id="2" w:author="Reviewer" w:date="2025-01-02T03:04:05Z"><w:r><w:t xml:space="preserve">slow</w:t></w:r></w:ins>`,
+		`<w:rPrChange w:id="3" w:author="Reviewer" w:date="2025-01-02T03:04:05Z"><w:rPr></w:rPr></w:rPrChange>`,
+		`<w:delText xml:space="preserve">b1</w:delText>`,
+		`<w:delText xml:space="preserve">Removed paragraph</w:delText>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("%s not found in %s", s, out)
+		}
+	}
+
+	buf.Reset()
+	_, err = doc.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txt := compareText(parsed); txt != compareText(doc) {
+		t.Fatalf("saved text %q is not %q", txt, compareText(doc))
+	}
+
+	doc.AcceptAllRevisions()
+	if txt := compareText(doc); txt != compareText(cur) {
+		t.Fatalf("accepted text %q is not the new one %q", txt, compareText(cur))
+	}
+	doc, _ = Compare(old, cur, nil)
+	doc.RejectAllRevisions()
+	if txt := compareText(doc); txt != compareText(old) {
+		t.Fatalf("rejected text %q is not the old one %q", txt, compareText(old))
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	old, _ := compareDocs()
+	_, changes := Compare(old, old, nil)
+	if len(changes) != 0 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
+
+func TestCompareTables(t *testing.T) {
+	old := New().WithDefaultTheme()
+	old.AddParagraph().AddText("before")
+	tbl := old.AddTable(2, 2, 2000)
+	for i, s := range []string{"k1", "k2", "k3", "k4"} {
+		tbl.Rows[i/2].Cells[i%2].AddParagraph().AddText(s)
+	}
+	old.AddParagraph().AddText("after")
+
+	cur := New().WithDefaultTheme()
+	cur.AddParagraph().AddText("before")
+	cur.AddParagraph().AddText("after")
+	tbl = cur.AddTable(1, 3, 2000)
+	for i, s := range []string{"n1", "n2", "n3"} {
+		tbl.Rows[0].Cells[i].AddParagraph().AddText(s)
+	}
+
+	doc, changes := Compare(old, cur, &CompareOptions{Author: "Reviewer"})
+	kinds := make([]string, 0, len(changes))
+	for _, c := range changes {
+		kinds = append(kinds, string(c.Kind))
+	}
+	if k := strings.Join(kinds, ","); k != "deleted,inserted" {
+		t.Fatalf("unexpected changes %s", k)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	_, err := doc.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ins, del int
+	for _, item := range parsed.Document.Body.Items {
+		if t, ok := item.(*Table); ok {
+			for _, tr := range t.Rows {
+				if tr.Properties != nil && tr.Properties.Ins != nil {
+					ins++
+				}
+				if tr.Properties != nil && tr.Properties.Del != nil {
+					del++
+				}
+			}
+		}
+	}
+	if ins != 1 || del != 2 {
+		t.Fatalf("expected 1 inserted and 2 deleted rows, got %d and %d", ins, del)
+	}
+
+	parsed.AcceptAllRevisions()
+	if txt := compareText(parsed); txt != compareText(cur) {
+		t.Fatalf("accepted text %q is not the new one %q", txt, compareText(cur))
+	}
+	doc, _ = Compare(old, cur, nil)
+	doc.RejectAllRevisions()
+	if txt := compareText(doc); txt != compareText(old) {
+		t.Fatalf("rejected text %q is not the old one %q", txt, compareText(old))
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"bytes"
 	"os"
 	"strconv"
-	"sync/atomic"
 
 	"github.com/fumiama/imgsz"
 )
@@ -35,9 +34,8 @@ func (p *Paragraph) AddInlineDrawing(pic []byte) (*Run, error) {
 	if err != nil {
 		return nil, err
 	}
-	idn := int(atomic.AddUintptr(&p.file.docID, 1))
-	id := int(p.file.IncreaseID("图片"))
-	ids := strconv.Itoa(id)
+	idn := p.file.NewID(ID_DRAWING)
+	ids := strconv.Itoa(idn)
 	rid := p.file.addImage(format, pic)
 	w, h := int64(sz.Width), int64(sz.Height)
 	if float64(w)/float64(h) > 1.2 {
@@ -75,7 +73,7 @@ func (p *Paragraph) AddInlineDrawing(pic []byte) (*Run, error) {
 						XMLPIC: XMLNS_DRAWINGML_PICTURE,
 						NonVisualPicProperties: &PICNonVisualPicProperties{
 							NonVisualDrawingProperties: NonVisualProperties{
-								ID:   id,
+								ID:   idn,
 								Name: "图片 " + ids,
 							},
 						},
@@ -99,7 +97,9 @@ func (p *Paragraph) AddInlineDrawing(pic []byte) (*Run, error) {
 					},
 				},
 			},
+			file: p.file,
 		},
+		file: p.file,
 	}
 	c := make([]interface{}, 1, 64)
 	c[0] = d
@@ -138,9 +138,8 @@ func (p *Paragraph) AddAnchorDrawing(pic []byte) (*Run, error) {
 	if err != nil {
 		return nil, err
 	}
-	idn := int(atomic.AddUintptr(&p.file.docID, 1))
-	id := int(p.file.IncreaseID("图片"))
-	ids := strconv.Itoa(id)
+	idn := p.file.NewID(ID_DRAWING)
+	ids := strconv.Itoa(idn)
 	rid := p.file.addImage(format, pic)
 	w, h := int64(sz.Width), int64(sz.Height)
 	if float64(w)/float64(h) > 1.2 {
@@ -187,7 +186,7 @@ func (p *Paragraph) AddAnchorDrawing(pic []byte) (*Run, error) {
 						XMLPIC: XMLNS_DRAWINGML_PICTURE,
 						NonVisualPicProperties: &PICNonVisualPicProperties{
 							NonVisualDrawingProperties: NonVisualProperties{
-								ID:   id,
+								ID:   idn,
 								Name: "图片 " + ids,
 							},
 						},
@@ -211,7 +210,9 @@ func (p *Paragraph) AddAnchorDrawing(pic []byte) (*Run, error) {
 					},
 				},
 			},
+			file: p.file,
 		},
+		file: p.file,
 	}
 	c := make([]interface{}, 1, 64)
 	c[0] = d
@@ -0,0 +1,712 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// tableConditions are the types of conditional table formatting <w:tblStylePr>
+// in the order they are applied, the last one wins
+var tableConditions = []string{
+	"wholeTable", "band1Vert", "band2Vert", "band1Horz", "band2Horz", "firstCol", "lastCol",
+	"firstRow", "lastRow", "neCell", "nwCell", "seCell", "swCell",
+}
+
+// cellPlace is the position of a paragraph in a table
+type cellPlace struct {
+	table    *Table
+	row, col int
+}
+
+// EffectiveProperties returns the formatting of the run resolved from
+// the document defaults, the table style, the paragraph style, the
+// character style and the direct formatting of the run.
+//
+// The result is a copy, bold and italic are set only when they are on.
+// The document is walked to find the run, use Docx.Formatting for many runs
+func (r *Run) EffectiveProperties() *RunProperties {
+	var p *Paragraph
+	var place *cellPlace
+	if r.file != nil {
+		p, place = r.file.placeOf(func(p *Paragraph) bool { return p.holds(r) })
+	}
+	return r.effectiveProperties(p, place)
+}
+
+// effectiveProperties is EffectiveProperties of the run in p at place
+func (r *Run) effectiveProperties(p *Paragraph, place *cellPlace) *RunProperties {
+	fr := newFormatResolver(r.file, p, place)
+	if fr.styles != nil && r.RunProperties != nil && r.RunProperties.RunStyle != nil {
+		for _, st := range fr.chain(r.RunProperties.RunStyle.Val, STYLE_CHARACTER) {
+			fr.run(st.RunProperties)
+		}
+		fr.group()
+	}
+	fr.direct(r.RunProperties)
+	return fr.rpr
+}
+
+// EffectiveProperties returns the formatting of the paragraph resolved from
+// the document defaults, the table style, the paragraph style and its direct
+// formatting. Its RunProperties are the ones of the paragraph mark.
+//
+// The result is a copy, bold and italic are set only when they are on.
+// The document is walked to find the paragraph, use Docx.Formatting for many paragraphs
+func (p *Paragraph) EffectiveProperties() *ParagraphProperties {
+	var place *cellPlace
+	if p.file != nil {
+		_, place = p.file.placeOf(func(o *Paragraph) bool { return o == p })
+	}
+	return p.effectiveProperties(place)
+}
+
+// effectiveProperties is EffectiveProperties of the paragraph at place
+func (p *Paragraph) effectiveProperties(place *cellPlace) *ParagraphProperties {
+	fr := newFormatResolver(p.file, p, place)
+	if p.Properties != nil {
+		mergeParagraphProperties(fr.ppr, p.Properties)
+		fr.direct(p.Properties.RunProperties)
+	} else {
+		fr.direct(nil)
+	}
+	fr.ppr.RunProperties = fr.rpr
+	return fr.ppr
+}
+
+// Formatting resolves the effective properties of the runs and the
+// paragraphs of a document which is walked once, when it is created.
+// It is created again once paragraphs or tables are added or moved
+type Formatting struct {
+	paragraphs map[*Run]*Paragraph
+	places     map[*Paragraph]*cellPlace
+}
+
+// Formatting walks the document to resolve the effective properties
+// of its runs and paragraphs
+func (f *Docx) Formatting() *Formatting {
+	fm := &Formatting{
+		paragraphs: make(map[*Run]*Paragraph, 1024),
+		places:     make(map[*Paragraph]*cellPlace, 256),
+	}
+	f.root().walkPlaces(func(p *Paragraph, place *cellPlace) bool {
+		fm.places[p] = place
+		p.eachRun(func(r *Run) bool {
+			fm.paragraphs[r] = p
+			return false
+		})
+		return false
+	})
+	return fm
+}
+
+// Run returns the effective properties of r as Run.EffectiveProperties
+func (fm *Formatting) Run(r *Run) *RunProperties {
+	p := fm.paragraphs[r]
+	return r.effectiveProperties(p, fm.places[p])
+}
+
+// Paragraph returns the effective properties of p as Paragraph.EffectiveProperties
+func (fm *Formatting) Paragraph(p *Paragraph) *ParagraphProperties {
+	return p.effectiveProperties(fm.places[p])
+}
+
+// formatResolver applies the layers of formatting from the lowest
+// priority (the document defaults) to the highest one
+type formatResolver struct {
+	styles *Styles
+	rpr    *RunProperties
+	ppr    *ParagraphProperties
+
+	// the toggle properties: set and on in the current style,
+	// on in all the styles (xor) and defined in any style
+	bold, italic           toggle
+	styleBold, styleItalic toggle
+	defBold, defItalic     bool
+}
+
+type toggle struct {
+	set, on bool
+}
+
+// newFormatResolver returns a resolver with the defaults, the table
+// style and the paragraph style of p (if any) already applied
+func newFormatResolver(f *Docx, p *Paragraph, place *cellPlace) *formatResolver {
+	fr := &formatResolver{rpr: &RunProperties{}, ppr: &ParagraphProperties{}}
+	if f == nil {
+		return fr
+	}
+	fr.styles = f.readStyles()
+	if dd := fr.styles.DocDefaults; dd != nil {
+		fr.run(dd.RunProperties)
+		if dd.ParagraphProperties != nil {
+			mergeParagraphProperties(fr.ppr, dd.ParagraphProperties)
+		}
+		fr.defBold, fr.defItalic = fr.bold.on, fr.italic.on
+		fr.bold, fr.italic = toggle{}, toggle{}
+	}
+	if place != nil {
+		fr.table(p, place)
+	}
+	id := ""
+	if p != nil && p.Properties != nil && p.Properties.Style != nil {
+		id = p.Properties.Style.Val
+	}
+	for _, st := range fr.chain(id, STYLE_PARAGRAPH) {
+		fr.paragraph(st.ParagraphProperties)
+		fr.run(st.RunProperties)
+	}
+	fr.group()
+	return fr
+}
+
+// chain returns the style id and its bases, from the root to the style,
+// the default style of typ is used when id is not found
+func (fr *formatResolver) chain(id string, typ _styleType) []*StyleDefinition {
+	st := fr.styles.Style(id)
+	if st == nil {
+		if typ == STYLE_CHARACTER {
+			return nil
+		}
+		st = fr.styles.Default(typ)
+		if st == nil {
+			return nil
+		}
+	}
+	chain := fr.styles.Inheritance(st.StyleID)
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// table applies the table style of the table holding p with
+// the conditional formatting of the cell
+func (fr *formatResolver) table(p *Paragraph, place *cellPlace) {
+	id := ""
+	if place.table.Properties != nil && place.table.Properties.Style != nil {
+		id = place.table.Properties.Style.Val
+	}
+	chain := fr.chain(id, STYLE_TABLE)
+	if len(chain) == 0 {
+		return
+	}
+	conds := place.conditions(p)
+	for _, st := range chain {
+		fr.paragraph(st.ParagraphProperties)
+		fr.run(st.RunProperties)
+	}
+	for _, typ := range tableConditions {
+		if !conds[typ] {
+			continue
+		}
+		for _, st := range chain {
+			for _, ts := range st.TableStyles {
+				if ts.Type == typ {
+					fr.paragraph(ts.ParagraphProperties)
+					fr.run(ts.RunProperties)
+				}
+			}
+		}
+	}
+	fr.group()
+}
+
+// paragraph applies the paragraph properties of a style
+func (fr *formatResolver) paragraph(ppr *ParagraphProperties) {
+	if ppr == nil {
+		return
+	}
+	mergeParagraphProperties(fr.ppr, ppr)
+}
+
+// run applies the run properties of a style,
+// the toggle properties override the ones of the same kind of style
+func (fr *formatResolver) run(rpr *RunProperties) {
+	if rpr == nil {
+		return
+	}
+	mergeRunProperties(fr.rpr, rpr)
+	if rpr.Bold != nil {
+		fr.bold = toggle{set: true, on: isOn(rpr.Bold.Val, true)}
+	}
+	if rpr.Italic != nil {
+		fr.italic = toggle{set: true, on: isOn(rpr.Italic.Val, true)}
+	}
+}
+
+// group ends a kind of style (table, paragraph or character),
+// the toggle properties of the kinds of style are combined with xor
+func (fr *formatResolver) group() {
+	for _, t := range []struct{ cur, all *toggle }{{&fr.bold, &fr.styleBold}, {&fr.italic, &fr.styleItalic}} {
+		if t.cur.set {
+			t.all.set = true
+			t.all.on = t.all.on != t.cur.on
+		}
+		*t.cur = toggle{}
+	}
+}
+
+// direct applies the direct formatting which sets the toggle properties
+func (fr *formatResolver) direct(rpr *RunProperties) {
+	bold, italic := fr.defBold, fr.defItalic
+	if fr.styleBold.set {
+		bold = fr.styleBold.on
+	}
+	if fr.styleItalic.set {
+		italic = fr.styleItalic.on
+	}
+	if rpr != nil {
+		mergeRunProperties(fr.rpr, rpr)
+		if rpr.Bold != nil {
+			bold = isOn(rpr.Bold.Val, true)
+		}
+		if rpr.Italic != nil {
+			italic = isOn(rpr.Italic.Val, true)
+		}
+	}
+	fr.rpr.Bold, fr.rpr.Italic = nil, nil
+	if bold {
+		fr.rpr.Bold = &Bold{}
+	}
+	if italic {
+		fr.rpr.Italic = &Italic{}
+	}
+}
+
+// mergeRunProperties copies the formatting set in src over dst,
+// the styles and the revisions are not copied
+func mergeRunProperties(dst, src *RunProperties) {
+	if src.Fonts != nil {
+		fonts := RunFonts{}
+		if dst.Fonts != nil {
+			fonts = *dst.Fonts
+		}
+		if src.Fonts.ASCII != "" {
+			fonts.ASCII = src.Fonts.ASCII
+		}
+		if src.Fonts.EastAsia != "" {
+			fonts.EastAsia = src.Fonts.EastAsia
+		}
+		if src.Fonts.HAnsi != "" {
+			fonts.HAnsi = src.Fonts.HAnsi
+		}
+		if src.Fonts.Hint != "" {
+			fonts.Hint = src.Fonts.Hint
+		}
+		dst.Fonts = &fonts
+	}
+	if src.ICs != nil {
+		dst.ICs = &struct{}{}
+	}
+	if src.Highlight != nil {
+		v := *src.Highlight
+		dst.Highlight = &v
+	}
+	if src.Color != nil {
+		v := *src.Color
+		dst.Color = &v
+	}
+	if src.Size != nil {
+		v := *src.Size
+		dst.Size = &v
+	}
+	if src.SizeCs != nil {
+		v := *src.SizeCs
+		dst.SizeCs = &v
+	}
+	if src.Spacing != nil {
+		v := *src.Spacing
+		if dst.Spacing != nil {
+			v.attrs = overrideAttrs(dst.Spacing.attrs, src.Spacing.attrs)
+		}
+		dst.Spacing = &v
+	}
+	if src.Shade != nil {
+		v := *src.Shade
+		dst.Shade = &v
+	}
+	if src.Kern != nil {
+		v := *src.Kern
+		dst.Kern = &v
+	}
+	if src.Underline != nil {
+		v := *src.Underline
+		dst.Underline = &v
+	}
+	if src.VertAlign != nil {
+		v := *src.VertAlign
+		dst.VertAlign = &v
+	}
+	if src.Strike != nil {
+		v := *src.Strike
+		dst.Strike = &v
+	}
+	if src.Lang != nil {
+		v := *src.Lang
+		dst.Lang = &v
+	}
+	if src.NoProof != nil {
+		dst.NoProof = &NoProof{}
+	}
+	dst.items = overrideItems(dst.items, src.items)
+}
+
+// mergeParagraphProperties copies the formatting set in src over dst,
+// the style, the run properties and the revisions are not copied
+func mergeParagraphProperties(dst, src *ParagraphProperties) {
+	if src.Tabs != nil {
+		v := Tabs{Tabs: append([]*Tab(nil), src.Tabs.Tabs...)}
+		dst.Tabs = &v
+	}
+	if src.Spacing != nil {
+		v := Spacing{}
+		if dst.Spacing != nil {
+			v = *dst.Spacing
+		}
+		if src.Spacing.Val != 0 {
+			v.Val = src.Spacing.Val
+		}
+		if src.Spacing.BeforeLines != 0 {
+			v.BeforeLines = src.Spacing.BeforeLines
+		}
+		if src.Spacing.Before != 0 {
+			v.Before = src.Spacing.Before
+		}
+		if src.Spacing.Line != 0 {
+			v.Line = src.Spacing.Line
+		}
+		if src.Spacing.LineRule != "" {
+			v.LineRule = src.Spacing.LineRule
+		}
+		v.attrs = overrideAttrs(v.attrs, src.Spacing.attrs)
+		dst.Spacing = &v
+	}
+	if src.NumProperties != nil {
+		v := NumProperties{}
+		if dst.NumProperties != nil {
+			v = *dst.NumProperties
+		}
+		if src.NumProperties.NumID != nil {
+			v.NumID = &NumID{Val: src.NumProperties.NumID.Val}
+		}
+		if src.NumProperties.Ilvl != nil {
+			v.Ilvl = &Ilevel{Val: src.NumProperties.Ilvl.Val}
+		}
+		dst.NumProperties = &v
+	}
+	if src.Ind != nil {
+		v := Ind{}
+		if dst.Ind != nil {
+			v = *dst.Ind
+		}
+		if src.Ind.LeftChars != 0 {
+			v.LeftChars = src.Ind.LeftChars
+		}
+		if src.Ind.Left != 0 {
+			v.Left = src.Ind.Left
+		}
+		if src.Ind.FirstLineChars != 0 || src.Ind.FirstLine != 0 {
+			v.FirstLineChars, v.FirstLine = src.Ind.FirstLineChars, src.Ind.FirstLine
+			v.HangingChars, v.Hanging = 0, 0
+		}
+		if src.Ind.HangingChars != 0 || src.Ind.Hanging != 0 {
+			v.HangingChars, v.Hanging = src.Ind.HangingChars, src.Ind.Hanging
+			v.FirstLineChars, v.FirstLine = 0, 0
+		}
+		v.attrs = overrideAttrs(v.attrs, src.Ind.attrs)
+		dst.Ind = &v
+	}
+	if src.Justification != nil {
+		v := *src.Justification
+		dst.Justification = &v
+	}
+	if src.Shade != nil {
+		v := *src.Shade
+		dst.Shade = &v
+	}
+	if src.Kern != nil {
+		v := *src.Kern
+		dst.Kern = &v
+	}
+	if src.TextAlignment != nil {
+		v := *src.TextAlignment
+		dst.TextAlignment = &v
+	}
+	if src.AdjustRightInd != nil {
+		v := *src.AdjustRightInd
+		dst.AdjustRightInd = &v
+	}
+	if src.SnapToGrid != nil {
+		v := *src.SnapToGrid
+		dst.SnapToGrid = &v
+	}
+	if src.Kinsoku != nil {
+		v := *src.Kinsoku
+		dst.Kinsoku = &v
+	}
+	if src.OverflowPunct != nil {
+		v := *src.OverflowPunct
+		dst.OverflowPunct = &v
+	}
+	if src.KeepNext != nil {
+		dst.KeepNext = &KeepNext{}
+	}
+	if src.KeepLines != nil {
+		dst.KeepLines = &KeepLines{}
+	}
+	if src.PageBreakBefore != nil {
+		dst.PageBreakBefore = &PageBreakBefore{}
+	}
+	if src.SuppressAutoHyphens != nil {
+		dst.SuppressAutoHyphens = &SuppressAutoHyphens{}
+	}
+	dst.items = overrideItems(dst.items, src.items)
+}
+
+// overrideAttrs returns the kept attributes of dst overridden
+// by the ones of src of the same name
+func overrideAttrs(dst, src []xml.Attr) []xml.Attr {
+	return mergeAttrs(append([]xml.Attr(nil), src...), dst)
+}
+
+// overrideItems returns the kept properties of dst overridden
+// by the ones of src of the same name
+func overrideItems(dst, src []*rawElement) []*rawElement {
+	if len(src) == 0 {
+		return dst
+	}
+	items := make([]*rawElement, 0, len(dst)+len(src))
+next:
+	for _, it := range dst {
+		for _, o := range src {
+			if it.name() == o.name() {
+				continue next
+			}
+		}
+		items = append(items, it)
+	}
+	for _, o := range src {
+		items = append(items, &rawElement{tokens: append([]xml.Token(nil), o.tokens...)})
+	}
+	return items
+}
+
+// holds tells whether r is one of the runs of the paragraph
+func (p *Paragraph) holds(r *Run) bool {
+	return p.eachRun(func(o *Run) bool { return o == r })
+}
+
+// eachRun calls fn on the runs of the paragraph, those of its
+// hyperlinks and revisions included, until it returns true
+func (p *Paragraph) eachRun(fn func(r *Run) bool) bool {
+	var in func(children []interface{}) bool
+	in = func(children []interface{}) bool {
+		for _, c := range children {
+			switch o := c.(type) {
+			case *Run:
+				if fn(o) {
+					return true
+				}
+			case *Hyperlink:
+				if fn(&o.Run) {
+					return true
+				}
+			case *Revision:
+				if in(o.Children) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return in(p.Children)
+}
+
+// placeOf returns the first paragraph of the document matching
+// and the innermost table cell holding it (nil out of tables)
+func (f *Docx) placeOf(match func(p *Paragraph) bool) (found *Paragraph, place *cellPlace) {
+	f.root().walkPlaces(func(p *Paragraph, cell *cellPlace) bool {
+		if match(p) {
+			found, place = p, cell
+			return true
+		}
+		return false
+	})
+	return
+}
+
+// walkPlaces calls fn on the paragraphs of the body, the headers,
+// the footers, the notes and the comments with the innermost table cell
+// holding them (nil out of tables) until it returns true
+func (f *Docx) walkPlaces(fn func(p *Paragraph, place *cellPlace) bool) {
+	var walk func(items []interface{}, cell *cellPlace) bool
+	walkTable := func(t *Table) bool {
+		for i, tr := range t.Rows {
+			for j, tc := range tr.Cells {
+				cell := &cellPlace{table: t, row: i, col: j}
+				for _, p := range tc.Paragraphs {
+					if fn(p, cell) {
+						return true
+					}
+				}
+				for _, nt := range tc.Tables {
+					if walk([]interface{}{nt}, cell) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	walk = func(items []interface{}, cell *cellPlace) bool {
+		for _, item := range items {
+			switch o := item.(type) {
+			case *Paragraph:
+				if fn(o, cell) {
+					return true
+				}
+			case *Table:
+				if walkTable(o) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if walk(f.Document.Body.Items, nil) {
+		return
+	}
+	for _, h := range f.headers {
+		if walk(h.Items, nil) {
+			return
+		}
+	}
+	for _, h := range f.footers {
+		if walk(h.Items, nil) {
+			return
+		}
+	}
+	for _, notes := range []*Notes{f.footnotes, f.endnotes} {
+		if notes == nil {
+			continue
+		}
+		for _, n := range notes.Notes {
+			if walk(n.Items, nil) {
+				return
+			}
+		}
+	}
+	if f.comments != nil {
+		for _, c := range f.comments.Comments {
+			if walk(c.Items, nil) {
+				return
+			}
+		}
+	}
+}
+
+// conditions returns the conditional formatting applying to p in the cell,
+// they are read from the <w:cnfStyle> of the row, the cell and p or
+// computed from the position of the cell and the table look
+func (c *cellPlace) conditions(p *Paragraph) map[string]bool {
+	conds := map[string]bool{"wholeTable": true}
+	row := c.table.Rows[c.row]
+	cell := row.Cells[c.col]
+	found := false
+	for _, cs := range []*WTableConfStyle{confStyleOfRow(row), confStyleOfCell(cell), confStyleOfParagraph(p)} {
+		if cs != nil {
+			cs.conditions(conds)
+			found = true
+		}
+	}
+	if found {
+		return conds
+	}
+
+	var look WTableLook
+	if c.table.Properties != nil && c.table.Properties.Look != nil {
+		look = *c.table.Properties.Look
+		if v, err := strconv.ParseInt(look.Val, 16, 64); err == nil && look.FirstRow|look.LastRow|look.FirstCol|look.LastCol|look.NoHBand|look.NoVBand == 0 {
+			// only w:val="04A0" (ECMA-376 1st edition)
+			bit := func(b int64) int {
+				if v&b != 0 {
+					return 1
+				}
+				return 0
+			}
+			look.FirstRow, look.LastRow, look.FirstCol = bit(0x20), bit(0x40), bit(0x80)
+			look.LastCol, look.NoHBand, look.NoVBand = bit(0x100), bit(0x200), bit(0x400)
+		}
+	}
+	firstRow := look.FirstRow == 1 && c.row == 0
+	lastRow := look.LastRow == 1 && c.row == len(c.table.Rows)-1
+	firstCol := look.FirstCol == 1 && c.col == 0
+	lastCol := look.LastCol == 1 && c.col == len(row.Cells)-1
+	conds["firstRow"], conds["lastRow"] = firstRow, lastRow
+	conds["firstCol"], conds["lastCol"] = firstCol, lastCol
+	conds["nwCell"], conds["neCell"] = firstRow && firstCol, firstRow && lastCol
+	conds["swCell"], conds["seCell"] = lastRow && firstCol, lastRow && lastCol
+	if look.NoHBand == 0 && !firstRow && !lastRow {
+		band := c.row - look.FirstRow
+		conds["band1Horz"], conds["band2Horz"] = band%2 == 0, band%2 == 1
+	}
+	if look.NoVBand == 0 && !firstCol && !lastCol {
+		band := c.col - look.FirstCol
+		conds["band1Vert"], conds["band2Vert"] = band%2 == 0, band%2 == 1
+	}
+	return conds
+}
+
+// conditions adds the conditional formatting flagged in c to conds
+func (c *WTableConfStyle) conditions(conds map[string]bool) {
+	flags := []int{
+		c.FirstRow, c.LastRow, c.FirstCol, c.LastCol, c.OddVBand, c.EvenVBand,
+		c.OddHBand, c.EvenHBand, c.FirstRowFirstCol, c.FirstRowLastCol, c.LastRowFirstCol, c.LastRowLastCol,
+	}
+	for i, typ := range []string{
+		"firstRow", "lastRow", "firstCol", "lastCol", "band1Vert", "band2Vert",
+		"band1Horz", "band2Horz", "nwCell", "neCell", "swCell", "seCell",
+	} {
+		if flags[i] == 1 || (i < len(c.Val) && c.Val[i] == '1') {
+			conds[typ] = true
+		}
+	}
+}
+
+func confStyleOfRow(r *WTableRow) *WTableConfStyle {
+	if r.Properties == nil {
+		return nil
+	}
+	return r.Properties.ConfStyle
+}
+
+func confStyleOfCell(c *WTableCell) *WTableConfStyle {
+	if c.Properties == nil {
+		return nil
+	}
+	return c.Properties.ConfStyle
+}
+
+func confStyleOfParagraph(p *Paragraph) *WTableConfStyle {
+	if p == nil || p.Properties == nil {
+		return nil
+	}
+	return p.Properties.ConfStyle
+}
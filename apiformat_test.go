@@ -0,0 +1,161 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestEffectiveProperties(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.Styles().DocDefaults = &DocDefaults{RunProperties: &RunProperties{Size: &Size{Val: "20"}, Color: &Color{Val: "111111"}}}
+	strong := w.AddParagraphStyle("Strong", "Strong")
+	strong.RunProperties.Bold = &Bold{}
+	strong.RunProperties.Color = &Color{Val: "FF0000"}
+	strong.ParagraphProperties.Justification = &Justification{Val: "center"}
+	emph := w.AddCharacterStyle("Emph", "Emph")
+	emph.RunProperties.Bold = &Bold{}
+	emph.RunProperties.Italic = &Italic{}
+	grid := w.AddTableStyle("Grid", "Grid")
+	grid.RunProperties = &RunProperties{Size: &Size{Val: "18"}}
+	grid.TableStyles = []*TableStyleProperties{
+		{Type: "firstRow", RunProperties: &RunProperties{Bold: &Bold{}, Color: &Color{Val: "0000FF"}}},
+		{Type: "band2Horz", RunProperties: &RunProperties{Color: &Color{Val: "00FF00"}}},
+	}
+
+	p := w.AddParagraph().Style("Strong")
+	p.AddText("plain")
+	p.AddText("emphasized").Style("Emph")
+	p.AddText("direct").Size("30").Bold(true)
+	w.AddParagraph().AddText("normal")
+	table := w.AddTable(3, 1, 1000).Style("Grid", TABLE_STYLE_OPTION_FIRST_ROW|TABLE_STYLE_OPTION_HORIZONTAL_BAND)
+	for _, tr := range table.Rows {
+		tr.Cells[0].AddParagraph().AddText("cell")
+	}
+
+	check := func(doc *Docx) {
+		t.Helper()
+		ps := paragraphsOf(doc.Document.Body.Items)
+		runs := func(p *Paragraph) []*Run {
+			rs := make([]*Run, 0, 4)
+			for _, c := range p.Children {
+				if r, ok := c.(*Run); ok {
+					rs = append(rs, r)
+				}
+			}
+			return rs
+		}
+		for i, it := range []struct {
+			r            *Run
+			bold, italic bool
+			color, size  string
+		}{
+			{runs(ps[0])[0], true, false, "FF0000", "20"},
+			{runs(ps[0])[1], false, true, "FF0000", "20"}, // bold toggled by the character style
+			{runs(ps[0])[2], true, false, "FF0000", "30"},
+			{runs(ps[1])[0], false, false, "111111", "20"},
+			{runs(ps[2])[0], true, false, "0000FF", "18"},
+			{runs(ps[3])[0], false, false, "111111", "18"},
+			{runs(ps[4])[0], false, false, "00FF00", "18"},
+		} {
+			rp := it.r.EffectiveProperties()
+			if (rp.Bold != nil) != it.bold || (rp.Italic != nil) != it.italic || rp.Color.Val != it.color || rp.Size.Val != it.size {
+				t.Fatalf("run %d: unexpected properties %+v", i, rp)
+			}
+		}
+		fm := doc.Formatting()
+		for i, p := range ps {
+			for _, r := range runs(p) {
+				if !fm.Run(r).equal(r.EffectiveProperties()) {
+					t.Fatalf("the formatting of a run of paragraph %d differs", i)
+				}
+			}
+			if want, got := p.EffectiveProperties(), fm.Paragraph(p); !got.RunProperties.equal(want.RunProperties) ||
+				(got.Justification == nil) != (want.Justification == nil) {
+				t.Fatalf("the formatting of paragraph %d differs", i)
+			}
+		}
+		pp := ps[0].EffectiveProperties()
+		if pp.Justification == nil || pp.Justification.Val != "center" || pp.RunProperties.Bold == nil {
+			t.Fatalf("unexpected paragraph properties %+v", pp)
+		}
+		// the default paragraph style of the template is justified
+		if pp := ps[1].EffectiveProperties(); pp.Justification.Val != "both" || pp.RunProperties.Size.Val != "20" {
+			t.Fatalf("unexpected paragraph properties %+v", pp)
+		}
+	}
+	check(w)
+
+	// the conditional formatting is read from the cnfStyle once written
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(doc)
+	if doc.styles != nil {
+		t.Fatal("styles loaded for writing")
+	}
+}
+
+func TestEffectivePropertiesKept(t *testing.T) {
+	ppr := func(s string) *ParagraphProperties {
+		t.Helper()
+		v := &ParagraphProperties{}
+		err := xml.Unmarshal(StringToBytes(`<w:pPr xmlns:w="`+XMLNS_W+`">`+s+`</w:pPr>`), v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	w := New().WithDefaultTheme()
+	dd := ppr(`<w:spacing w:after="160" w:line="259" w:lineRule="auto"/><w:ind w:right="720"/><w:rPr><w:caps/></w:rPr>`)
+	w.Styles().DocDefaults = &DocDefaults{RunProperties: dd.RunProperties, ParagraphProperties: dd}
+	st := w.AddParagraphStyle("Tight", "Tight")
+	st.ParagraphProperties = ppr(`<w:spacing w:after="80"/><w:ind w:left="360"/>`)
+	p := w.AddParagraph().Style("Tight")
+	r := p.AddText("kept")
+
+	out, err := xml.Marshal(p.EffectiveProperties())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	for _, want := range []string{`w:after="80"`, `w:line="259"`, `w:right="720"`, `w:left="360"`} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("%s is not in %s", want, s)
+		}
+	}
+	if strings.Contains(s, `w:after="160"`) {
+		t.Fatalf("the spacing of the defaults is not overridden in %s", s)
+	}
+	out, err = xml.Marshal(r.EffectiveProperties())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "<w:caps>") && !strings.Contains(string(out), "<w:caps/>") {
+		t.Fatalf("w:caps is not in %s", out)
+	}
+}
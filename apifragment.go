@@ -0,0 +1,85 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+// Fragment is a part of a body built apart from any document, with its own
+// media, links and ids, so that the fragments of a document can be built in
+// parallel goroutines. One fragment must not be used by several goroutines.
+type Fragment struct {
+	doc *Docx
+}
+
+// NewFragment returns an empty fragment
+func NewFragment() *Fragment {
+	return &Fragment{doc: New()}
+}
+
+// AddParagraph adds a new paragraph
+func (fr *Fragment) AddParagraph() *Paragraph {
+	return fr.doc.AddParagraph()
+}
+
+// AddTable add a new table to the fragment by col*row
+//
+// unit: twips (1/20 point)
+func (fr *Fragment) AddTable(row, col, tableWidth int) *Table {
+	return fr.doc.AddTable(row, col, tableWidth)
+}
+
+// AddTableTwips add a new table to the fragment by height and width
+//
+// unit: twips (1/20 point)
+func (fr *Fragment) AddTableTwips(rowHeights, colWidths []int, tableWidth int) *Table {
+	return fr.doc.AddTableTwips(rowHeights, colWidths, tableWidth)
+}
+
+// AddTableEmpty adds a new table without rows
+func (fr *Fragment) AddTableEmpty() *Table {
+	return fr.doc.AddTableEmpty()
+}
+
+// Items returns the paragraphs and tables of the fragment
+func (fr *Fragment) Items() []interface{} {
+	return fr.doc.Document.Body.Items
+}
+
+// InsertFragment inserts a copy of the items of frag before the body item at,
+// they are appended when at is out of the body. The media, links, lists,
+// notes, comments and ids of the fragment are moved to the document.
+// The copies are returned, frag is not changed and may be inserted again.
+func (f *Docx) InsertFragment(at int, frag *Fragment) []interface{} {
+	items := f.copyItems(frag.doc, frag.doc.Document.Body.Items)
+	// the next insertion of frag gets new ids
+	root := f.root()
+	for key := range root.idCopies {
+		if key.from == frag.doc {
+			delete(root.idCopies, key)
+		}
+	}
+
+	body := f.Document.Body.Items
+	if at < 0 || at >= len(body) {
+		f.Document.Body.Items = append(body, items...)
+		return items
+	}
+	nbody := make([]interface{}, 0, len(body)+len(items))
+	nbody = append(nbody, body[:at]...)
+	nbody = append(nbody, items...)
+	f.Document.Body.Items = append(nbody, body[at:]...)
+	return items
+}
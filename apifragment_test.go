@@ -0,0 +1,105 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestFragments(t *testing.T) {
+	const n = 8
+	frags := make([]*Fragment, n)
+	var wg sync.WaitGroup
+	for i := range frags {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var pic bytes.Buffer
+			err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, i+1)), nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			frag := NewFragment()
+			frag.AddParagraph().AddText("section " + strconv.Itoa(i))
+			_, err = frag.AddParagraph().AddInlineDrawing(pic.Bytes())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			frag.AddParagraph().AddLink("link", "https://example.com/"+strconv.Itoa(i))
+			frag.AddTable(2, 2, 9000).Rows[0].Cells[0].AddParagraph().AddText("cell")
+			frags[i] = frag
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	w := New().WithDefaultTheme()
+	w.AddParagraph().AddText("end")
+	for i := n - 1; i >= 0; i-- {
+		if items := w.InsertFragment(0, frags[i]); len(items) != 4 {
+			t.Fatalf("%d items inserted", len(items))
+		}
+	}
+	w.InsertFragment(-1, frags[0])
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := doc.Document.Body.Items
+	if len(items) != 4*(n+1)+1 {
+		t.Fatalf("%d body items", len(items))
+	}
+	if p := items[4*n].(*Paragraph); p.String() != "end" {
+		t.Fatalf("unexpected paragraph %q before the appended fragment", p.String())
+	}
+	if len(doc.media) != n+1 {
+		t.Fatalf("%d media", len(doc.media))
+	}
+	seen := make(map[int]bool)
+	for _, id := range idsOf(t, doc, "docPr") {
+		if seen[id] {
+			t.Fatalf("drawing id %d is used twice", id)
+		}
+		seen[id] = true
+	}
+	for i := 0; i <= n; i++ {
+		at, want := 4*i+2, "https://example.com/"+strconv.Itoa(i)
+		if i == n {
+			at, want = 4*n+3, "https://example.com/0"
+		}
+		link := items[at].(*Paragraph).Children[0].(*Hyperlink)
+		if target, err := doc.ReferTarget(link.ID); err != nil || target != want {
+			t.Fatalf("link %d targets %q, %v", i, target, err)
+		}
+	}
+}
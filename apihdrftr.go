@@ -0,0 +1,356 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strconv"
+	"strings"
+)
+
+type _hdrftr string
+
+const (
+	HEADER_FOOTER_DEFAULT _hdrftr = "default" // Header/footer of the pages without a more specific one
+	HEADER_FOOTER_FIRST   _hdrftr = "first"   // Header/footer of the first page of the section
+	HEADER_FOOTER_EVEN    _hdrftr = "even"    // Header/footer of the even pages
+)
+
+// SectPr returns the section properties at the end of the body,
+// they are appended if the body has none
+func (f *Docx) SectPr() *SectPr {
+	items := f.Document.Body.Items
+	for i := len(items) - 1; i >= 0; i-- {
+		if s, ok := items[i].(*SectPr); ok {
+			if s.file == nil {
+				s.file = f
+			}
+			return s
+		}
+	}
+	s := &SectPr{file: f}
+	f.Document.Body.Items = append(f.Document.Body.Items, s)
+	return s
+}
+
+// Headers returns all the header parts of the document
+func (f *Docx) Headers() []*Header {
+	return f.root().headers
+}
+
+// Footers returns all the footer parts of the document
+func (f *Docx) Footers() []*Footer {
+	return f.root().footers
+}
+
+// Settings returns the settings part (word/settings.xml),
+// it is loaded from the template or created on first use
+func (f *Docx) Settings() *Settings {
+	f = f.root()
+	if f.settings != nil {
+		return f.settings
+	}
+	f.settings = &Settings{}
+	if f.hasTemplateFile(SETTINGS_PART) {
+		_ = f.loadTemplatePart(SETTINGS_PART, f.settings)
+	}
+	return f.settings
+}
+
+// EvenAndOddHeaders allows to use different headers and footers on even pages
+func (f *Docx) EvenAndOddHeaders(val ...bool) *Docx {
+	f.Settings().EvenAndOddHeaders = len(val) == 0 || val[0]
+	return f
+}
+
+// TitlePage allows to use a different header and footer on the first page of the section
+func (s *SectPr) TitlePage(val ...bool) *SectPr {
+	if len(val) == 0 || val[0] {
+		s.TitlePg = &TitlePg{}
+	} else {
+		s.TitlePg = nil
+	}
+	return s
+}
+
+// AddHeader adds the header of kind to the section,
+// the existing header is returned if there is already one.
+//
+// The first page or even pages options are enabled accordingly.
+// nil is returned if the section is not in a document (see Docx.SectPr).
+func (s *SectPr) AddHeader(kind _hdrftr) *Header {
+	if s.file == nil {
+		return nil
+	}
+	if h := s.Header(kind); h != nil {
+		return h
+	}
+	f := s.file.root()
+	h := f.newHeader()
+	s.HeaderReference = append(s.HeaderReference, &HeaderReference{
+		Type: string(kind),
+		ID:   f.addRelation(REL_HEADER, h.name[len(WORD_FOLDER):]),
+	})
+	s.enableKind(kind)
+	return h
+}
+
+// AddFooter adds the footer of kind to the section,
+// the existing footer is returned if there is already one.
+//
+// The first page or even pages options are enabled accordingly.
+// nil is returned if the section is not in a document (see Docx.SectPr).
+func (s *SectPr) AddFooter(kind _hdrftr) *Footer {
+	if s.file == nil {
+		return nil
+	}
+	if h := s.Footer(kind); h != nil {
+		return h
+	}
+	f := s.file.root()
+	h := f.newFooter()
+	s.FooterReference = append(s.FooterReference, &FooterReference{
+		Type: string(kind),
+		ID:   f.addRelation(REL_FOOTER, h.name[len(WORD_FOLDER):]),
+	})
+	s.enableKind(kind)
+	return h
+}
+
+// Header gets the header of kind of the section (or nil on notfound)
+func (s *SectPr) Header(kind _hdrftr) *Header {
+	if s.file == nil {
+		return nil
+	}
+	for _, r := range s.HeaderReference {
+		if r.Type == string(kind) || (r.Type == "" && kind == HEADER_FOOTER_DEFAULT) {
+			return s.file.root().headerByID(r.ID)
+		}
+	}
+	return nil
+}
+
+// Footer gets the footer of kind of the section (or nil on notfound)
+func (s *SectPr) Footer(kind _hdrftr) *Footer {
+	if s.file == nil {
+		return nil
+	}
+	for _, r := range s.FooterReference {
+		if r.Type == string(kind) || (r.Type == "" && kind == HEADER_FOOTER_DEFAULT) {
+			return s.file.root().footerByID(r.ID)
+		}
+	}
+	return nil
+}
+
+func (s *SectPr) enableKind(kind _hdrftr) {
+	switch kind {
+	case HEADER_FOOTER_FIRST:
+		s.TitlePg = &TitlePg{}
+	case HEADER_FOOTER_EVEN:
+		s.file.EvenAndOddHeaders()
+	}
+}
+
+// AddParagraph adds a new paragraph
+func (h *Header) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     h.file,
+	}
+	h.Items = append(h.Items, p)
+	return p
+}
+
+// AddTable add a new table by col*row
+//
+// unit: twips (1/20 point)
+func (h *Header) AddTable(row, col, tableWidth int) *Table {
+	tbl := h.file.newTable(row, col, tableWidth)
+	h.Items = append(h.Items, tbl)
+	return tbl
+}
+
+// AddParagraph adds a new paragraph
+func (h *Footer) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     h.file,
+	}
+	h.Items = append(h.Items, p)
+	return p
+}
+
+// AddTable add a new table by col*row
+//
+// unit: twips (1/20 point)
+func (h *Footer) AddTable(row, col, tableWidth int) *Table {
+	tbl := h.file.newTable(row, col, tableWidth)
+	h.Items = append(h.Items, tbl)
+	return tbl
+}
+
+func (f *Docx) newHeader() *Header {
+	h := &Header{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		Items:  make([]interface{}, 0, 8),
+		name:   f.newPartName(WORD_FOLDER+"header", ".xml"),
+		file:   f.newPart(),
+	}
+	f.headers = append(f.headers, h)
+	return h
+}
+
+func (f *Docx) newFooter() *Footer {
+	h := &Footer{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		Items:  make([]interface{}, 0, 8),
+		name:   f.newPartName(WORD_FOLDER+"footer", ".xml"),
+		file:   f.newPart(),
+	}
+	f.footers = append(f.footers, h)
+	return h
+}
+
+func (f *Docx) headerByID(id string) *Header {
+	tgt, err := f.ReferTarget(id)
+	if err != nil {
+		return nil
+	}
+	name := relTargetPart(DOCUMENT_PART, tgt)
+	for _, h := range f.headers {
+		if h.name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+func (f *Docx) footerByID(id string) *Footer {
+	tgt, err := f.ReferTarget(id)
+	if err != nil {
+		return nil
+	}
+	name := relTargetPart(DOCUMENT_PART, tgt)
+	for _, h := range f.footers {
+		if h.name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// newPartName returns the first unused part name prefix + N + ext
+func (f *Docx) newPartName(prefix, ext string) string {
+	for i := 1; ; i++ {
+		name := prefix + strconv.Itoa(i) + ext
+		if !f.hasPart(name) {
+			return name
+		}
+	}
+}
+
+// hasPart tells whether the part name is used in the package
+func (f *Docx) hasPart(name string) bool {
+	for _, h := range f.headers {
+		if strings.EqualFold(h.name, name) {
+			return true
+		}
+	}
+	for _, h := range f.footers {
+		if strings.EqualFold(h.name, name) {
+			return true
+		}
+	}
+	for n := range f.parts {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return f.hasTemplateFile(name)
+}
+
+// copymedia copies the section to the document to,
+// the header and footer parts are copied along
+func (s *SectPr) copymedia(to *Docx) *SectPr {
+	ns := *s
+	ns.file = to
+	ns.HeaderReference = nil
+	ns.FooterReference = nil
+	if s.file == nil {
+		return &ns
+	}
+	to = to.root()
+	for _, r := range s.HeaderReference {
+		h := s.file.root().headerByID(r.ID)
+		if h == nil {
+			continue
+		}
+		nh := to.newHeader()
+		nh.Items = copyItems(h.Items, nh.file)
+		nh.namespaces = h.namespaces
+		ns.HeaderReference = append(ns.HeaderReference, &HeaderReference{
+			Type: r.Type,
+			ID:   to.addRelation(REL_HEADER, nh.name[len(WORD_FOLDER):]),
+		})
+	}
+	for _, r := range s.FooterReference {
+		h := s.file.root().footerByID(r.ID)
+		if h == nil {
+			continue
+		}
+		nh := to.newFooter()
+		nh.Items = copyItems(h.Items, nh.file)
+		nh.namespaces = h.namespaces
+		ns.FooterReference = append(ns.FooterReference, &FooterReference{
+			Type: r.Type,
+			ID:   to.addRelation(REL_FOOTER, nh.name[len(WORD_FOLDER):]),
+		})
+	}
+	if s.file.root().settings != nil && s.file.root().settings.EvenAndOddHeaders {
+		to.EvenAndOddHeaders()
+	}
+	return &ns
+}
+
+// copyItems copies the paragraphs and tables of items to the document to
+func copyItems(items []interface{}, to *Docx) []interface{} {
+	nitems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		switch o := item.(type) {
+		case *Paragraph:
+			np := o.copymedia(to)
+			nitems = append(nitems, &np)
+		case *Table:
+			nt := o.copymedia(to)
+			nitems = append(nitems, &nt)
+		default:
+			nitems = append(nitems, o)
+		}
+	}
+	return nitems
+}
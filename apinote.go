@@ -0,0 +1,251 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// AddFootnote adds a footnote with text and its reference at the end of the paragraph
+func (p *Paragraph) AddFootnote(text string) *Note {
+	n := p.file.footnotesPart().newNote(text)
+	p.Children = append(p.Children, &Run{
+		RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+		Children:      []interface{}{&FootnoteReference{ID: n.ID}},
+		file:          p.file,
+	})
+	return n
+}
+
+// AddEndnote adds an endnote with text and its reference at the end of the paragraph
+func (p *Paragraph) AddEndnote(text string) *Note {
+	n := p.file.endnotesPart().newNote(text)
+	p.Children = append(p.Children, &Run{
+		RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+		Children:      []interface{}{&EndnoteReference{ID: n.ID}},
+		file:          p.file,
+	})
+	return n
+}
+
+// Footnotes returns the footnotes of the document, separators excluded
+func (f *Docx) Footnotes() []*Note {
+	return f.root().footnotes.userNotes()
+}
+
+// Endnotes returns the endnotes of the document, separators excluded
+func (f *Docx) Endnotes() []*Note {
+	return f.root().endnotes.userNotes()
+}
+
+// Footnote gets the footnote by its id (or nil on notfound)
+func (f *Docx) Footnote(id int) *Note {
+	return f.root().footnotes.note(id)
+}
+
+// Endnote gets the endnote by its id (or nil on notfound)
+func (f *Docx) Endnote(id int) *Note {
+	return f.root().endnotes.note(id)
+}
+
+// AddParagraph adds a new paragraph
+func (n *Note) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     n.file,
+	}
+	n.Items = append(n.Items, p)
+	return p
+}
+
+// Paragraphs returns the paragraphs of the note
+func (n *Note) Paragraphs() []*Paragraph {
+	ps := make([]*Paragraph, 0, len(n.Items))
+	for _, item := range n.Items {
+		if p, ok := item.(*Paragraph); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// String returns the text of the paragraphs of the note, one per line
+func (n *Note) String() string {
+	sb := strings.Builder{}
+	for i, p := range n.Paragraphs() {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strings.TrimPrefix(p.String(), " "))
+	}
+	return sb.String()
+}
+
+func (f *Docx) footnotesPart() *Notes {
+	f = f.root()
+	if f.footnotes == nil {
+		f.footnotes = f.newNotes("w:footnotes", FOOTNOTES_PART, REL_FOOTNOTES)
+	}
+	return f.footnotes
+}
+
+func (f *Docx) endnotesPart() *Notes {
+	f = f.root()
+	if f.endnotes == nil {
+		f.endnotes = f.newNotes("w:endnotes", ENDNOTES_PART, REL_ENDNOTES)
+	}
+	return f.endnotes
+}
+
+// newNotes creates the notes part with its separators
+func (f *Docx) newNotes(tag, name, rel string) *Notes {
+	n := &Notes{
+		XMLName: xml.Name{Local: tag},
+		XMLW:    XMLNS_W,
+		XMLR:    XMLNS_R,
+		XMLWP:   XMLNS_WP,
+		XMLWPS:  XMLNS_WPS,
+		XMLWPC:  XMLNS_WPC,
+		XMLWPG:  XMLNS_WPG,
+		name:    name,
+		file:    f.newPart(),
+	}
+	n.Notes = []*Note{
+		n.newSeparator(NOTE_SEPARATOR, -1, &Separator{}),
+		n.newSeparator(NOTE_CONTINUATION_SEPARATOR, 0, &ContinuationSeparator{}),
+	}
+	f.addRelation(rel, name[len(WORD_FOLDER):])
+	return n
+}
+
+func (n *Notes) newSeparator(typ string, id int, sep interface{}) *Note {
+	note := &Note{
+		XMLName: xml.Name{Local: n.noteTag()},
+		Type:    typ,
+		ID:      id,
+		file:    n.file,
+	}
+	p := note.AddParagraph()
+	p.Properties = &ParagraphProperties{Spacing: &Spacing{Line: 240, LineRule: "auto"}}
+	p.Children = append(p.Children, &Run{Children: []interface{}{sep}, file: n.file})
+	return note
+}
+
+// newNote appends a note with text
+func (n *Notes) newNote(text string) *Note {
+	note := n.appendNote()
+	var ref interface{} = &FootnoteRef{}
+	if note.XMLName.Local == "w:endnote" {
+		ref = &EndnoteRef{}
+	}
+	p := note.AddParagraph()
+	p.Children = append(p.Children,
+		&Run{
+			RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+			Children:      []interface{}{ref},
+			file:          n.file,
+		},
+		&Run{
+			Children: []interface{}{&Text{Text: " ", XMLSpace: "preserve"}},
+			file:     n.file,
+		},
+	)
+	if text != "" {
+		p.AddText(text)
+	}
+	return note
+}
+
+// appendNote appends an empty note with the next free id
+func (n *Notes) appendNote() *Note {
+	id := 1
+	for _, o := range n.Notes {
+		if o.ID >= id {
+			id = o.ID + 1
+		}
+	}
+	note := &Note{
+		XMLName: xml.Name{Local: n.noteTag()},
+		ID:      id,
+		Items:   make([]interface{}, 0, 4),
+		file:    n.file,
+	}
+	n.Notes = append(n.Notes, note)
+	return note
+}
+
+// noteTag is w:footnote or w:endnote
+func (n *Notes) noteTag() string {
+	return strings.TrimSuffix(n.XMLName.Local, "s")
+}
+
+func (n *Notes) userNotes() []*Note {
+	if n == nil {
+		return nil
+	}
+	notes := make([]*Note, 0, len(n.Notes))
+	for _, o := range n.Notes {
+		if o.Type == "" || o.Type == "normal" {
+			notes = append(notes, o)
+		}
+	}
+	return notes
+}
+
+func (n *Notes) note(id int) *Note {
+	if n == nil {
+		return nil
+	}
+	for _, o := range n.Notes {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// copyNote appends a copy of note to the notes
+func (n *Notes) copyNote(note *Note) *Note {
+	nn := n.appendNote()
+	nn.Type = note.Type
+	nn.Items = copyItems(note.Items, n.file)
+	return nn
+}
+
+func (r *FootnoteReference) copymedia(from, to *Docx) *FootnoteReference {
+	nr := *r
+	if from == nil {
+		return &nr
+	}
+	if n := from.Footnote(r.ID); n != nil {
+		nr.ID = to.footnotesPart().copyNote(n).ID
+	}
+	return &nr
+}
+
+func (r *EndnoteReference) copymedia(from, to *Docx) *EndnoteReference {
+	nr := *r
+	if from == nil {
+		return &nr
+	}
+	if n := from.Endnote(r.ID); n != nil {
+		nr.ID = to.endnotesPart().copyNote(n).ID
+	}
+	return &nr
+}
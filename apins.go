@@ -0,0 +1,62 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import "strings"
+
+// Namespaces returns the namespaces declared on the root of the document (prefix -> URL)
+func (f *Docx) Namespaces() map[string]string {
+	doc := &f.Document
+	ns := make(map[string]string, 8+len(doc.namespaces))
+	for prefix, url := range map[string]string{
+		"w": doc.XMLW, "r": doc.XMLR, "wp": doc.XMLWP, "wps": doc.XMLWPS,
+		"wpc": doc.XMLWPC, "wpg": doc.XMLWPG, "mc": doc.XMLMC,
+	} {
+		if url != "" {
+			ns[prefix] = url
+		}
+	}
+	for _, a := range doc.namespaces {
+		ns[strings.TrimPrefix(a.Name.Local, "xmlns:")] = a.Value
+	}
+	return ns
+}
+
+// RegisterNamespace declares url with prefix on the root of the document,
+// so that the elements and attributes added with this prefix can be read.
+// An ignorable prefix is listed in mc:Ignorable: the applications which
+// do not understand it can still open the document.
+func (f *Docx) RegisterNamespace(prefix, url string, ignorable bool) *Docx {
+	doc := &f.Document
+	doc.declare(prefix, url)
+	if !ignorable {
+		return f
+	}
+	doc.XMLMC = XMLNS_MC
+	for _, p := range strings.Fields(doc.MCIgnorable) {
+		if p == prefix {
+			return f
+		}
+	}
+	if doc.MCIgnorable == "" {
+		doc.MCIgnorable = prefix
+	} else {
+		doc.MCIgnorable += " " + prefix
+	}
+	return f
+}
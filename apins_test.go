@@ -0,0 +1,77 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const decoded_namespaces = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" ` +
+	`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" ` +
+	`xmlns:w16se="http://schemas.microsoft.com/office/word/2015/wordml/symex" ` +
+	`mc:Ignorable="w14 w16se"><w:body><w:p w14:paraId="0A1B2C3D"><w:r><w:t>x</w:t></w:r></w:p></w:body></w:document>`
+
+func TestRootNamespaces(t *testing.T) {
+	var doc Document
+	err := xml.Unmarshal(StringToBytes(decoded_namespaces), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, frag := range []string{
+		`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"`,
+		`mc:Ignorable="w14 w16se"`,
+		`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"`,
+		`xmlns:w16se="http://schemas.microsoft.com/office/word/2015/wordml/symex"`,
+		`<w:p w14:paraId="0A1B2C3D">`,
+	} {
+		if !strings.Contains(string(out), frag) {
+			t.Errorf("%s not found in %s", frag, out)
+		}
+	}
+}
+
+func TestRegisterNamespace(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.RegisterNamespace("w14", XMLNS_W14, true).RegisterNamespace("w15", XMLNS_W15, true).RegisterNamespace("w14", XMLNS_W14, true)
+	w.AddParagraph().AddText("body")
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := doc.Namespaces()
+	if ns["w14"] != XMLNS_W14 || ns["w15"] != XMLNS_W15 || ns["mc"] != XMLNS_MC || ns["w"] != XMLNS_W {
+		t.Fatalf("unexpected namespaces %v", ns)
+	}
+	if doc.Document.MCIgnorable != "w14 w15" {
+		t.Fatalf("unexpected ignorable prefixes %q", doc.Document.MCIgnorable)
+	}
+}
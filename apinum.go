@@ -0,0 +1,619 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strconv"
+	"strings"
+)
+
+type _numFmt string
+
+const (
+	NUMFMT_DECIMAL          _numFmt = "decimal"         // 1, 2, 3...
+	NUMFMT_DECIMAL_ZERO     _numFmt = "decimalZero"     // 01, 02, 03...
+	NUMFMT_UPPER_ROMAN      _numFmt = "upperRoman"      // I, II, III...
+	NUMFMT_LOWER_ROMAN      _numFmt = "lowerRoman"      // i, ii, iii...
+	NUMFMT_UPPER_LETTER     _numFmt = "upperLetter"     // A, B, C...
+	NUMFMT_LOWER_LETTER     _numFmt = "lowerLetter"     // a, b, c...
+	NUMFMT_CHINESE_COUNTING _numFmt = "chineseCounting" // 一, 二, 三...
+	NUMFMT_BULLET           _numFmt = "bullet"          // The level text is displayed as is
+	NUMFMT_NONE             _numFmt = "none"            // No number
+)
+
+// listLevels is the number of levels of the lists
+const listLevels = 9
+
+// bulletTexts are the bullets of the levels of the bullet lists
+var bulletTexts = []string{"•", "◦", "▪"}
+
+// List is a numbering instance of the document which paragraphs can be attached to
+type List struct {
+	num  *Num
+	file *Docx
+}
+
+// Numbering returns the numbering part (word/numbering.xml),
+// it is loaded from the template or created on first use
+func (f *Docx) Numbering() *Numbering {
+	f = f.root()
+	if f.numbering != nil {
+		return f.numbering
+	}
+	f.numbering = &Numbering{name: NUMBERING_PART}
+	if f.hasTemplateFile(NUMBERING_PART) {
+		_ = f.loadTemplatePart(NUMBERING_PART, f.numbering)
+	}
+	return f.numbering
+}
+
+// AddBulletList adds a new bullet list definition and returns its list
+func (f *Docx) AddBulletList() *List {
+	return f.addList(func(ilvl int) (_numFmt, string) {
+		return NUMFMT_BULLET, bulletTexts[ilvl%len(bulletTexts)]
+	})
+}
+
+// AddNumberedList adds a new numbered list definition using format
+// at all levels and returns its list, the levels are labelled 1., 2.,...
+func (f *Docx) AddNumberedList(format _numFmt) *List {
+	return f.addList(func(ilvl int) (_numFmt, string) {
+		return format, "%" + strconv.Itoa(ilvl+1) + "."
+	})
+}
+
+// List gets the list by its numbering id (or nil on notfound)
+func (f *Docx) List(numID int) *List {
+	f = f.root()
+	if f.numbering == nil {
+		return nil
+	}
+	num := f.numbering.NumByID(numID)
+	if num == nil {
+		return nil
+	}
+	return &List{num: num, file: f}
+}
+
+// ID is the numbering id of the list
+func (l *List) ID() int {
+	return l.num.ID
+}
+
+// Num is the numbering instance of the list
+func (l *List) Num() *Num {
+	return l.num
+}
+
+// AbstractNum is the list definition of the list
+func (l *List) AbstractNum() *AbstractNum {
+	return l.num.AbstractNum(l.file.Numbering())
+}
+
+// AddParagraph adds a new paragraph of the list at level to the body
+func (l *List) AddParagraph(level int) *Paragraph {
+	return l.Attach(l.file.AddParagraph(), level)
+}
+
+// Attach makes the paragraph an item of the list at level
+func (l *List) Attach(p *Paragraph, level int) *Paragraph {
+	return p.NumPr(strconv.Itoa(l.num.ID), strconv.Itoa(level))
+}
+
+// Restart returns a new list sharing the definition of l
+// whose numbering starts again
+func (l *List) Restart() *List {
+	a := l.AbstractNum()
+	if a == nil {
+		return l
+	}
+	nl := l.file.newList(a.ID)
+	for _, lvl := range a.Levels {
+		start := 1
+		if lvl.Start != nil {
+			if v, err := strconv.Atoi(lvl.Start.Val); err == nil {
+				start = v
+			}
+		}
+		nl.StartAt(lvl.Ilvl, start)
+	}
+	return nl
+}
+
+// StartAt sets the first number of the level of the list
+func (l *List) StartAt(level, start int) *List {
+	o := l.num.LvlOverride(level)
+	if o == nil {
+		o = &LvlOverride{Ilvl: level}
+		l.num.LvlOverrides = append(l.num.LvlOverrides, o)
+	}
+	o.StartOverride = &NumberingVal{Val: strconv.Itoa(start)}
+	return l
+}
+
+// addList adds a list definition whose levels format and text are given by fmtOf
+func (f *Docx) addList(fmtOf func(ilvl int) (_numFmt, string)) *List {
+	n := f.Numbering()
+	a := &AbstractNum{
+		ID:             n.nextAbstractNumID(),
+		Nsid:           &NumberingVal{Val: n.newNsid()},
+		MultiLevelType: &NumberingVal{Val: "hybridMultilevel"},
+		Levels:         make([]*Level, 0, listLevels),
+	}
+	for i := 0; i < listLevels; i++ {
+		format, text := fmtOf(i)
+		a.Levels = append(a.Levels, &Level{
+			Ilvl:       i,
+			Start:      &NumberingVal{Val: "1"},
+			NumFmt:     &NumberingVal{Val: string(format)},
+			LvlText:    &NumberingVal{Val: text},
+			LvlJc:      &NumberingVal{Val: "left"},
+			Properties: &ParagraphProperties{Ind: &Ind{Left: 720 * (i + 1), Hanging: 360}},
+		})
+	}
+	n.AbstractNums = append(n.AbstractNums, a)
+	return f.newList(a.ID)
+}
+
+// newList adds a numbering instance of the list definition abstractID
+func (f *Docx) newList(abstractID int) *List {
+	n := f.Numbering()
+	num := &Num{
+		ID:            n.nextNumID(),
+		AbstractNumID: &NumberingVal{Val: strconv.Itoa(abstractID)},
+	}
+	n.Nums = append(n.Nums, num)
+	return &List{num: num, file: f.root()}
+}
+
+func (n *Numbering) nextAbstractNumID() int {
+	id := 0
+	for _, a := range n.AbstractNums {
+		if a.ID >= id {
+			id = a.ID + 1
+		}
+	}
+	return id
+}
+
+func (n *Numbering) nextNumID() int {
+	id := 1
+	for _, num := range n.Nums {
+		if num.ID >= id {
+			id = num.ID + 1
+		}
+	}
+	return id
+}
+
+// newNsid returns an unused list identifier
+func (n *Numbering) newNsid() string {
+	for i := len(n.AbstractNums) + 1; ; i++ {
+		id := strings.ToUpper(strconv.FormatInt(int64(i), 16))
+		id = strings.Repeat("0", 8-len(id)) + id
+		if !n.hasNsid(id) {
+			return id
+		}
+	}
+}
+
+func (n *Numbering) hasNsid(id string) bool {
+	for _, a := range n.AbstractNums {
+		if a.Nsid != nil && strings.EqualFold(a.Nsid.Val, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyNum copies the numbering instance id of from and its list definition to f,
+// returns its new id
+//
+// An instance or a definition is only copied once even if it is referred several times.
+func (f *Docx) copyNum(from *Docx, id string) string {
+	if from == nil || from.root() == f.root() || from.root().numbering == nil {
+		return id
+	}
+	numID, err := strconv.Atoi(id)
+	if err != nil || numID == 0 {
+		return id
+	}
+	src := from.root().numbering
+	num := src.NumByID(numID)
+	if num == nil {
+		return id
+	}
+	f = f.root()
+	if nid, ok := f.numCopies[num]; ok {
+		return strconv.Itoa(nid)
+	}
+	if f.numCopies == nil {
+		f.numCopies = make(map[interface{}]int, 16)
+	}
+	n := f.Numbering()
+	nn := *num
+	nn.LvlOverrides = append([]*LvlOverride(nil), num.LvlOverrides...)
+	if n.NumByID(nn.ID) != nil {
+		nn.ID = n.nextNumID()
+	}
+	if a := num.AbstractNum(src); a != nil {
+		aid, ok := f.numCopies[a]
+		if !ok {
+			na := *a
+			na.Levels = append([]*Level(nil), a.Levels...)
+			if n.AbstractNumByID(na.ID) != nil {
+				na.ID = n.nextAbstractNumID()
+			}
+			n.AbstractNums = append(n.AbstractNums, &na)
+			aid = na.ID
+			f.numCopies[a] = aid
+		}
+		nn.AbstractNumID = &NumberingVal{Val: strconv.Itoa(aid)}
+	}
+	n.Nums = append(n.Nums, &nn)
+	f.numCopies[num] = nn.ID
+	return strconv.Itoa(nn.ID)
+}
+
+// Label returns the list label of the paragraph, e.g. "1.2.a)",
+// as computed by the last call to EvaluateNumbering
+func (p *Paragraph) Label() string {
+	return p.label
+}
+
+// EvaluateNumbering computes the list labels of the paragraphs of the body
+// (tables included) in reading order. They are returned by Paragraph.Label
+// and included in the text of the paragraphs.
+//
+// It is called when a document is parsed and must be called again
+// once the lists or the paragraphs are changed.
+func (f *Docx) EvaluateNumbering() {
+	ev := newNumberingEvaluator(f.root())
+	for _, p := range paragraphsOf(f.Document.Body.Items) {
+		p.label, p.labelSuffix = ev.label(p)
+	}
+}
+
+// listCounters are the counters of the levels of a list definition
+type listCounters struct {
+	count [listLevels]int
+	set   [listLevels]bool
+}
+
+// numberingEvaluator numbers the paragraphs in reading order,
+// the instances of a list definition share its counters
+type numberingEvaluator struct {
+	n         *Numbering
+	styles    *Styles
+	lists     map[int]*listCounters     // lists are the counters by list definition id
+	started   map[int]bool              // started are the instances already used
+	styleNums map[string]*NumProperties // styleNums are the numbering of the paragraph styles
+}
+
+// newNumberingEvaluator returns an evaluator of the lists of f
+func newNumberingEvaluator(f *Docx) numberingEvaluator {
+	ev := numberingEvaluator{
+		n:         f.numbering,
+		lists:     make(map[int]*listCounters, 8),
+		started:   make(map[int]bool, 8),
+		styleNums: make(map[string]*NumProperties, 8),
+	}
+	if ev.n != nil {
+		ev.styles = f.readStyles()
+	}
+	return ev
+}
+
+// numProperties returns the numbering of the paragraph resolved from
+// its paragraph style (and the styles it is based on) and its direct formatting
+func (ev *numberingEvaluator) numProperties(p *Paragraph) *NumProperties {
+	id := ""
+	if p.Properties != nil && p.Properties.Style != nil {
+		id = p.Properties.Style.Val
+	}
+	np, ok := ev.styleNums[id]
+	if !ok {
+		np = ev.styleNum(id)
+		ev.styleNums[id] = np
+	}
+	if p.Properties == nil || p.Properties.NumProperties == nil {
+		return np
+	}
+	ppr := ParagraphProperties{NumProperties: np}
+	mergeParagraphProperties(&ppr, &ParagraphProperties{NumProperties: p.Properties.NumProperties})
+	return ppr.NumProperties
+}
+
+// styleNum returns the numbering of the paragraph style id, the level
+// is the one of the list definition linked to the style when it is not set
+func (ev *numberingEvaluator) styleNum(id string) *NumProperties {
+	fr := formatResolver{styles: ev.styles}
+	chain := fr.chain(id, STYLE_PARAGRAPH)
+	ppr := ParagraphProperties{}
+	for _, st := range chain {
+		if st.ParagraphProperties != nil {
+			mergeParagraphProperties(&ppr, st.ParagraphProperties)
+		}
+	}
+	np := ppr.NumProperties
+	if np == nil || np.NumID == nil || np.Ilvl != nil {
+		return np
+	}
+	numID, err := strconv.Atoi(np.NumID.Val)
+	if err != nil {
+		return np
+	}
+	num := ev.n.NumByID(numID)
+	if num == nil {
+		return np
+	}
+	a := num.AbstractNum(ev.n)
+	if a == nil {
+		return np
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, l := range a.Levels {
+			if l.PStyle != nil && l.PStyle.Val == chain[i].StyleID {
+				np.Ilvl = &Ilevel{Val: strconv.Itoa(l.Ilvl)}
+				return np
+			}
+		}
+	}
+	return np
+}
+
+// label returns the label of the paragraph and its suffix
+func (ev *numberingEvaluator) label(p *Paragraph) (string, string) {
+	if ev.n == nil {
+		return "", ""
+	}
+	np := ev.numProperties(p)
+	if np == nil || np.NumID == nil {
+		return "", ""
+	}
+	numID, err := strconv.Atoi(np.NumID.Val)
+	if err != nil || numID == 0 {
+		return "", ""
+	}
+	ilvl := 0
+	if np.Ilvl != nil {
+		ilvl, err = strconv.Atoi(np.Ilvl.Val)
+		if err != nil || ilvl < 0 || ilvl >= listLevels {
+			return "", ""
+		}
+	}
+	num := ev.n.NumByID(numID)
+	if num == nil {
+		return "", ""
+	}
+	a := num.AbstractNum(ev.n)
+	if a == nil {
+		return "", ""
+	}
+	lc, ok := ev.lists[a.ID]
+	if !ok {
+		lc = &listCounters{}
+		ev.lists[a.ID] = lc
+	}
+	if !ev.started[numID] {
+		// the overridden levels start again on the first use of the instance
+		ev.started[numID] = true
+		for _, o := range num.LvlOverrides {
+			if o.Ilvl >= 0 && o.Ilvl < listLevels && (o.StartOverride != nil || o.Level != nil) {
+				lc.set[o.Ilvl] = false
+			}
+		}
+	}
+
+	lvl, start := levelOf(num, a, ilvl)
+	if lc.set[ilvl] {
+		lc.count[ilvl]++
+	} else {
+		lc.count[ilvl], lc.set[ilvl] = start, true
+	}
+	for k := ilvl + 1; k < listLevels; k++ {
+		kl, _ := levelOf(num, a, k)
+		if kl == nil || kl.LvlRestart == nil {
+			lc.set[k] = false
+			continue
+		}
+		if r, err := strconv.Atoi(kl.LvlRestart.Val); err == nil && r != 0 && ilvl < r {
+			lc.set[k] = false
+		}
+	}
+	if lvl == nil || lvl.LvlText == nil {
+		return "", ""
+	}
+
+	suffix := "\t"
+	if lvl.Suff != nil {
+		switch lvl.Suff.Val {
+		case "space":
+			suffix = " "
+		case "nothing":
+			suffix = ""
+		}
+	}
+	format := NUMFMT_DECIMAL
+	if lvl.NumFmt != nil {
+		format = _numFmt(lvl.NumFmt.Val)
+	}
+	switch format {
+	case NUMFMT_NONE:
+		return "", ""
+	case NUMFMT_BULLET:
+		return bulletText(lvl.LvlText.Val), suffix
+	}
+	legal := lvl.IsLgl != nil && lvl.IsLgl.Val != "0" && lvl.IsLgl.Val != "false"
+
+	sb := strings.Builder{}
+	text := lvl.LvlText.Val
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' || i+1 >= len(text) || text[i+1] < '1' || text[i+1] > '9' {
+			sb.WriteByte(text[i])
+			continue
+		}
+		i++
+		k := int(text[i] - '1')
+		kl, kstart := levelOf(num, a, k)
+		n := kstart
+		if lc.set[k] {
+			n = lc.count[k]
+		}
+		kformat := NUMFMT_DECIMAL
+		if kl != nil && kl.NumFmt != nil && !legal {
+			kformat = _numFmt(kl.NumFmt.Val)
+		}
+		sb.WriteString(FormatNumber(n, kformat))
+	}
+	return sb.String(), suffix
+}
+
+// levelOf returns the definition of the level ilvl of the instance and its start
+func levelOf(num *Num, a *AbstractNum, ilvl int) (*Level, int) {
+	lvl := a.Level(ilvl)
+	o := num.LvlOverride(ilvl)
+	if o != nil && o.Level != nil {
+		lvl = o.Level
+	}
+	start := 0
+	if lvl != nil && lvl.Start != nil {
+		if v, err := strconv.Atoi(lvl.Start.Val); err == nil {
+			start = v
+		}
+	}
+	if o != nil && o.StartOverride != nil {
+		if v, err := strconv.Atoi(o.StartOverride.Val); err == nil {
+			start = v
+		}
+	}
+	return lvl, start
+}
+
+// bulletText replaces the symbol font bullets by their unicode equivalent
+func bulletText(s string) string {
+	return strings.NewReplacer("\uf0b7", "•", "\uf0a7", "▪", "\uf0d8", "➢", "\uf0fc", "✓", "\uf076", "❖").Replace(s)
+}
+
+// FormatNumber renders n in the number format
+//
+// The unsupported formats are rendered as decimal numbers.
+func FormatNumber(n int, format _numFmt) string {
+	switch format {
+	case NUMFMT_NONE, NUMFMT_BULLET:
+		return ""
+	case NUMFMT_DECIMAL_ZERO:
+		if n >= 0 && n < 10 {
+			return "0" + strconv.Itoa(n)
+		}
+	case NUMFMT_UPPER_ROMAN:
+		return romanNumber(n)
+	case NUMFMT_LOWER_ROMAN:
+		return strings.ToLower(romanNumber(n))
+	case NUMFMT_UPPER_LETTER:
+		return letterNumber(n, 'A')
+	case NUMFMT_LOWER_LETTER:
+		return letterNumber(n, 'a')
+	case NUMFMT_CHINESE_COUNTING, "chineseCountingThousand", "chineseLegalSimplified":
+		return chineseNumber(n)
+	}
+	return strconv.Itoa(n)
+}
+
+var romanDigits = []struct {
+	value int
+	text  string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+	{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func romanNumber(n int) string {
+	if n <= 0 {
+		return strconv.Itoa(n)
+	}
+	sb := strings.Builder{}
+	for _, d := range romanDigits {
+		for n >= d.value {
+			sb.WriteString(d.text)
+			n -= d.value
+		}
+	}
+	return sb.String()
+}
+
+// letterNumber renders n as a, b,... z, aa, bb,...
+func letterNumber(n int, first byte) string {
+	if n <= 0 {
+		return strconv.Itoa(n)
+	}
+	return strings.Repeat(string(rune(first)+rune((n-1)%26)), (n-1)/26+1)
+}
+
+var chineseDigits = []string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+var chineseUnits = []string{"", "十", "百", "千"}
+
+// chineseNumber renders n in chinese counting, e.g. 十一, 二十, 一百〇五
+func chineseNumber(n int) string {
+	if n < 0 {
+		return strconv.Itoa(n)
+	}
+	if n == 0 {
+		return chineseDigits[0]
+	}
+	if n >= 10000 {
+		high, low := n/10000, n%10000
+		s := chineseNumber(high) + "万"
+		if low == 0 {
+			return s
+		}
+		if low < 1000 {
+			s += chineseDigits[0]
+		}
+		return s + chineseNumber(low)
+	}
+	if n >= 10 && n < 20 {
+		// 十, 十一... without the leading 一
+		s := chineseUnits[1]
+		if n > 10 {
+			s += chineseDigits[n-10]
+		}
+		return s
+	}
+	sb := strings.Builder{}
+	zero := false
+	for u := 3; u >= 0; u-- {
+		p := 1
+		for i := 0; i < u; i++ {
+			p *= 10
+		}
+		d := n / p % 10
+		if d == 0 {
+			zero = sb.Len() > 0
+			continue
+		}
+		if zero {
+			sb.WriteString(chineseDigits[0])
+			zero = false
+		}
+		sb.WriteString(chineseDigits[d])
+		sb.WriteString(chineseUnits[u])
+	}
+	return sb.String()
+}
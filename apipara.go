@@ -20,6 +20,11 @@
 
 package docx
 
+import (
+	"encoding/xml"
+	"strconv"
+)
+
 // AddParagraph adds a new paragraph
 func (f *Docx) AddParagraph() *Paragraph {
 	p := &Paragraph{
@@ -58,8 +63,9 @@ func (p *Paragraph) AddPageBreaks() *Run {
 	run := &Run{
 		RunProperties: &RunProperties{},
 		Children:      c,
+		file:          p.file,
 	}
-	p.Children = append(p.Children, run)
+	p.appendRun(run)
 	return run
 }
 
@@ -218,3 +224,44 @@ func (p *Paragraph) SuppressAutoHyphens(val ...bool) *Paragraph {
 	}
 	return p
 }
+
+// Bookmark marks the content of the paragraph as the bookmark name,
+// its id is allocated among the bookmarks of the document
+func (p *Paragraph) Bookmark(name string) *Paragraph {
+	id := xml.Attr{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(p.file.NewID(ID_BOOKMARK))}
+	start := xml.StartElement{Name: xml.Name{Local: "w:bookmarkStart"}, Attr: []xml.Attr{id, {Name: xml.Name{Local: "w:name"}, Value: name}}}
+	end := xml.StartElement{Name: xml.Name{Local: "w:bookmarkEnd"}, Attr: []xml.Attr{id}}
+	children := make([]interface{}, 0, len(p.Children)+2)
+	children = append(children, &rawElement{tokens: []xml.Token{start, start.End()}})
+	children = append(children, p.Children...)
+	p.Children = append(children, &rawElement{tokens: []xml.Token{end, end.End()}})
+	return p
+}
+
+// paragraphsOf returns the paragraphs of items, including the ones in tables
+func paragraphsOf(items []interface{}) []*Paragraph {
+	ps := make([]*Paragraph, 0, len(items))
+	for _, item := range items {
+		switch o := item.(type) {
+		case *Paragraph:
+			ps = append(ps, o)
+		case *Table:
+			ps = append(ps, o.paragraphs()...)
+		}
+	}
+	return ps
+}
+
+// paragraphs returns the paragraphs of the cells of the table, nested tables included
+func (t *Table) paragraphs() []*Paragraph {
+	ps := make([]*Paragraph, 0, 16)
+	for _, tr := range t.Rows {
+		for _, tc := range tr.Cells {
+			ps = append(ps, tc.Paragraphs...)
+			for _, nt := range tc.Tables {
+				ps = append(ps, nt.paragraphs()...)
+			}
+		}
+	}
+	return ps
+}
@@ -0,0 +1,464 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrManagedPart is returned when changing a part which is written
+// from the document model (e.g. word/document.xml or a header)
+var ErrManagedPart = errors.New("part managed by the document")
+
+// Parts returns the names of the parts of the package
+// in the order they are written, the document is not changed
+func (f *Docx) Parts() ([]string, error) {
+	files, _, err := f.root().listParts()
+	if err != nil {
+		return nil, err
+	}
+	return sortedParts(files), nil
+}
+
+// Part returns the content of the part name as it is written,
+// only this part is rendered and the document is not changed
+func (f *Docx) Part(name string) ([]byte, error) {
+	data, err := f.root().renderPart(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "part", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// renderPart returns the content of the part name
+func (f *Docx) renderPart(name string) ([]byte, error) {
+	files, _, err := f.listParts()
+	if err != nil {
+		return nil, err
+	}
+	w, ok := files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SetPart adds or replaces the part name with data,
+// the content type is overridden when contentType is not empty.
+//
+// The settings, the numbering and the styles are parsed into the document,
+// the parts written from the document model cannot be set
+func (f *Docx) SetPart(name, contentType string, data []byte) error {
+	f = f.root()
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "setpart", Path: name, Err: fs.ErrInvalid}
+	}
+	ct, err := f.loadContentTypes()
+	if err != nil {
+		return err
+	}
+	switch {
+	case name == SETTINGS_PART:
+		s := new(Settings)
+		err = xml.Unmarshal(data, s)
+		if err != nil {
+			return err
+		}
+		f.settings = s
+	case name == f.numberingName():
+		n := &Numbering{name: name}
+		err = xml.Unmarshal(data, n)
+		if err != nil {
+			return err
+		}
+		f.numbering = n
+		f.removeTemplateFile(name)
+		f.EvaluateNumbering()
+	case name == f.readStyles().name:
+		s := &Styles{name: name}
+		err = xml.Unmarshal(data, s)
+		if err != nil {
+			return err
+		}
+		f.styles = s
+		f.stylesRead = nil
+	case f.managedPart(name):
+		return &fs.PathError{Op: "setpart", Path: name, Err: ErrManagedPart}
+	case strings.HasPrefix(name, MEDIA_FOLDER):
+		if m := f.Media(name[len(MEDIA_FOLDER):]); m != nil {
+			m.Data = data
+			m.file = nil
+		} else {
+			f.addMedia(Media{Name: name[len(MEDIA_FOLDER):], Data: data})
+		}
+	default:
+		if f.parts == nil {
+			f.parts = make(map[string][]byte, 8)
+		}
+		f.parts[name] = data
+		f.removeTemplateFile(name)
+		delete(f.partRels, name)
+	}
+	if contentType != "" {
+		ct.Override(name, contentType)
+	}
+	return nil
+}
+
+// DeletePart removes the part name, its relationships and
+// the relationships targeting it
+func (f *Docx) DeletePart(name string) error {
+	f = f.root()
+	found := false
+	switch {
+	case name == SETTINGS_PART && f.settings != nil:
+		f.settings = nil
+		found = true
+	case f.numbering != nil && name == f.numbering.name:
+		f.numbering = nil
+		found = true
+	case f.styles != nil && name == f.styles.name:
+		f.styles = nil
+		found = true
+	case f.managedPart(name):
+		return &fs.PathError{Op: "deletepart", Path: name, Err: ErrManagedPart}
+	}
+	if f.stylesRead != nil && name == f.stylesRead.name {
+		f.stylesRead = nil
+	}
+	if i, ok := f.mediaNameIdx[strings.TrimPrefix(name, MEDIA_FOLDER)]; ok && strings.HasPrefix(name, MEDIA_FOLDER) {
+		f.media = append(f.media[:i:i], f.media[i+1:]...)
+		f.mediaNameIdx = make(map[string]int, len(f.media))
+		for j, m := range f.media {
+			f.mediaNameIdx[m.Name] = j
+		}
+		found = true
+	}
+	if _, ok := f.parts[name]; ok {
+		delete(f.parts, name)
+		found = true
+	}
+	if f.removeTemplateFile(name) {
+		found = true
+	}
+	if !found {
+		return &fs.PathError{Op: "deletepart", Path: name, Err: fs.ErrNotExist}
+	}
+
+	rels := relsPartName(name)
+	delete(f.partRels, rels)
+	delete(f.parts, rels)
+	f.removeTemplateFile(rels)
+
+	// the package relationships are loaded to be cleaned too
+	_, err := f.PartRelationships("")
+	if err != nil {
+		return err
+	}
+	for source, rels := range f.relationshipSets() {
+		kept := rels.Relationship[:0]
+		for _, r := range rels.Relationship {
+			if r.TargetMode == REL_TARGETMODE || relTargetPart(source, r.Target) != name {
+				kept = append(kept, r)
+			}
+		}
+		rels.Relationship = kept
+	}
+	return nil
+}
+
+// PartRelationships returns the relationships of the part name,
+// the package relationships (_rels/.rels) when name is empty.
+//
+// The relationships of the parts written from the document model
+// are the ones used by the model, the others are written along the package
+func (f *Docx) PartRelationships(name string) (*Relationships, error) {
+	f = f.root()
+	if d, ok := f.modelParts()[name]; ok {
+		return &d.docRelation, nil
+	}
+	relsName := "_rels/.rels"
+	if name != "" {
+		relsName = relsPartName(name)
+	}
+	if rels, ok := f.partRels[relsName]; ok {
+		return rels, nil
+	}
+	rels := &Relationships{}
+	if data, ok := f.parts[relsName]; ok {
+		err := xml.Unmarshal(data, rels)
+		if err != nil {
+			return nil, err
+		}
+		delete(f.parts, relsName)
+	} else if f.hasTemplateFile(relsName) {
+		err := f.loadTemplatePart(relsName, rels)
+		if err != nil {
+			return nil, err
+		}
+		f.removeTemplateFile(relsName)
+	}
+	rels.Xmlns = XMLNS_REL
+	if f.partRels == nil {
+		f.partRels = make(map[string]*Relationships, 8)
+	}
+	f.partRels[relsName] = rels
+	return rels, nil
+}
+
+// modelParts returns the files holding the relationships of the parts
+// written from the document model by part name
+func (f *Docx) modelParts() map[string]*Docx {
+	parts := make(map[string]*Docx, 8+len(f.headers)+len(f.footers))
+	parts[DOCUMENT_PART] = f
+	for _, h := range f.headers {
+		parts[h.name] = h.file
+	}
+	for _, h := range f.footers {
+		parts[h.name] = h.file
+	}
+	for _, n := range []*Notes{f.footnotes, f.endnotes} {
+		if n != nil {
+			parts[n.name] = n.file
+		}
+	}
+	if f.comments != nil {
+		parts[f.comments.name] = f.comments.file
+	}
+	return parts
+}
+
+// relationshipSets returns all the relationships loaded by source part,
+// the package relationships are under the empty name
+func (f *Docx) relationshipSets() map[string]*Relationships {
+	parts := f.modelParts()
+	sets := make(map[string]*Relationships, len(parts)+len(f.partRels))
+	for name, d := range parts {
+		sets[name] = &d.docRelation
+	}
+	for name, rels := range f.partRels {
+		sets[relsSourcePart(name)] = rels
+	}
+	return sets
+}
+
+// loadRelationships loads the relationships of all the parts
+func (f *Docx) loadRelationships() error {
+	names := make([]string, 0, len(f.tmpfslst)+len(f.parts))
+	names = append(names, f.tmpfslst...)
+	for name := range f.parts {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if !isRelsPart(name) {
+			continue
+		}
+		_, err := f.PartRelationships(relsSourcePart(name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// managedPart tells whether name is written from the document model
+func (f *Docx) managedPart(name string) bool {
+	if name == CONTENT_TYPES || (f.comments != nil && name == f.comments.exName) {
+		return true
+	}
+	for source := range f.modelParts() {
+		if name == source || name == relsPartName(source) {
+			return true
+		}
+	}
+	return false
+}
+
+// numberingName returns the name of the numbering part
+func (f *Docx) numberingName() string {
+	if f.numbering != nil {
+		return f.numbering.name
+	}
+	for _, r := range f.docRelation.Relationship {
+		if r.Type == REL_NUMBERING {
+			return relTargetPart(DOCUMENT_PART, r.Target)
+		}
+	}
+	return NUMBERING_PART
+}
+
+// relsSourcePart returns the name of the part of the relationships part name
+//
+//	e.g. word/_rels/document.xml.rels => word/document.xml
+func relsSourcePart(name string) string {
+	dir, file := path.Split(name)
+	return strings.TrimSuffix(dir, "_rels/") + strings.TrimSuffix(file, ".rels")
+}
+
+// isRelsPart tells whether name is a relationships part
+func isRelsPart(name string) bool {
+	return strings.HasSuffix(name, ".rels") && path.Base(path.Dir(name)) == "_rels"
+}
+
+// Open opens the part name for reading so that Docx is a fs.FS,
+// the folders of the package are its directories
+func (f *Docx) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	f = f.root()
+	files, _, err := f.listParts()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if w, ok := files[name]; ok {
+		var buf bytes.Buffer
+		_, err = w.WriteTo(&buf)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &partFile{
+			Reader: bytes.NewReader(buf.Bytes()),
+			info:   partInfo{name: path.Base(name), size: int64(buf.Len())},
+		}, nil
+	}
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	children := make(map[string]bool, 16)
+	for n := range files {
+		if !strings.HasPrefix(n, prefix) {
+			continue
+		}
+		n = n[len(prefix):]
+		if i := strings.IndexByte(n, '/'); i >= 0 {
+			children[n[:i]] = true
+		} else if !children[n] {
+			children[n] = false
+		}
+	}
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	d := &partDir{path: name, info: partInfo{name: path.Base(name), dir: true}}
+	for n, dir := range children {
+		d.entries = append(d.entries, partEntry{f: f, path: path.Join(name, n), dir: dir})
+	}
+	sort.Slice(d.entries, func(i, j int) bool {
+		return d.entries[i].Name() < d.entries[j].Name()
+	})
+	return d, nil
+}
+
+// partInfo describes a part or a folder of the package
+type partInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i partInfo) Name() string       { return i.name }
+func (i partInfo) Size() int64        { return i.size }
+func (i partInfo) ModTime() time.Time { return time.Time{} }
+func (i partInfo) IsDir() bool        { return i.dir }
+func (i partInfo) Sys() interface{}   { return nil }
+
+func (i partInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// partFile is an opened part
+type partFile struct {
+	*bytes.Reader
+	info partInfo
+}
+
+func (p *partFile) Stat() (fs.FileInfo, error) { return p.info, nil }
+func (p *partFile) Close() error               { return nil }
+
+// partDir is an opened folder
+type partDir struct {
+	path    string
+	info    partInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *partDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *partDir) Close() error               { return nil }
+
+func (d *partDir) Read(_ []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next n entries of the folder, all when n <= 0
+func (d *partDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.entries[d.off:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+	d.off += len(entries)
+	return entries, nil
+}
+
+// partEntry is an entry of a folder
+type partEntry struct {
+	f    *Docx
+	path string
+	dir  bool
+}
+
+func (e partEntry) Name() string { return path.Base(e.path) }
+func (e partEntry) IsDir() bool  { return e.dir }
+
+func (e partEntry) Type() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e partEntry) Info() (fs.FileInfo, error) {
+	if e.dir {
+		return partInfo{name: e.Name(), dir: true}, nil
+	}
+	return fs.Stat(e.f, e.path)
+}
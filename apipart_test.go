@@ -0,0 +1,165 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPartAPI(t *testing.T) {
+	const item = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><items><item>1</item></items>`
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddText("body")
+	err := w.SetPart("customXml/item1.xml", "application/xml", []byte(item))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, err := w.PartRelationships("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels.Relationship) == 0 {
+		t.Fatal("the package relationships are not loaded")
+	}
+	rels.Relationship = append(rels.Relationship, Relationship{
+		ID:     "rIdCustom",
+		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml",
+		Target: "customXml/item1.xml",
+	})
+	if err = w.SetPart(DOCUMENT_PART, "", nil); !errors.Is(err, ErrManagedPart) {
+		t.Fatalf("setting the document gives %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := doc.Part("customXml/item1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != item {
+		t.Fatalf("unexpected part %s", data)
+	}
+	ct, err := doc.Part(CONTENT_TYPES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ct), `PartName="/customXml/item1.xml" ContentType="application/xml"`) {
+		t.Fatalf("missing content type in %s", ct)
+	}
+	data, err = fs.ReadFile(doc, "_rels/.rels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `Target="customXml/item1.xml"`) {
+		t.Fatalf("missing relationship in %s", data)
+	}
+	err = fstest.TestFS(doc, "customXml/item1.xml", DOCUMENT_PART, "word/theme/theme1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = doc.DeletePart("customXml/item1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = doc.Part("customXml/item1.xml"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("deleted part gives %v", err)
+	}
+	data, err = doc.Part("_rels/.rels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "customXml") {
+		t.Fatalf("relationship to the deleted part in %s", data)
+	}
+	parts, err := doc.Parts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range parts {
+		if strings.HasPrefix(name, "customXml") {
+			t.Fatalf("deleted part %s is listed", name)
+		}
+	}
+	if _, err = fs.Stat(doc, "customXml"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("deleted folder gives %v", err)
+	}
+}
+
+func TestPartsKeepDocument(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddBulletList()
+	types, err := w.loadContentTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, overrides := len(w.docRelation.Relationship), len(types.Overrides)
+	names, err := w.Parts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, name := range names {
+		found = found || name == NUMBERING_PART
+	}
+	if !found {
+		t.Fatalf("no numbering part in %v", names)
+	}
+	data, err := w.Part("word/_rels/document.xml.rels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), REL_NUMBERING) {
+		t.Fatal("the numbering relationship is not rendered")
+	}
+	ct, err := fs.ReadFile(w, CONTENT_TYPES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ct), CT_NUMBERING) {
+		t.Fatal("the numbering content type is not rendered")
+	}
+	if len(w.docRelation.Relationship) != rels || len(types.Overrides) != overrides {
+		t.Fatal("the relationships or the content types are changed")
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := w.Part("word/_rels/document.xml.rels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Fatal("the relationships rendered before writing differ")
+	}
+}
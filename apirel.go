@@ -20,6 +20,17 @@
 
 package docx
 
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
 // RangeRelationships goes through each doc relation
 func (f *Docx) RangeRelationships(iter func(*Relationship) error) error {
 	for _, r := range f.docRelation.Relationship {
@@ -30,3 +41,178 @@ func (f *Docx) RangeRelationships(iter func(*Relationship) error) error {
 	}
 	return nil
 }
+
+// PartRelationship is a relationship of the part Part,
+// Part is empty for the package relationships
+type PartRelationship struct {
+	Part string
+	Relationship
+}
+
+// AddRelationship adds a relationship of type typ from the part
+// (the package when empty) to target and returns its new id,
+// target is relative to the part unless it is external
+func (f *Docx) AddRelationship(part, typ, target string, external bool) (string, error) {
+	f = f.root()
+	rels, err := f.PartRelationships(part)
+	if err != nil {
+		return "", err
+	}
+	n := &f.rID
+	if d, ok := f.modelParts()[part]; ok {
+		n = &d.rID
+	} else {
+		last := uintptr(len(rels.Relationship))
+		n = &last
+	}
+	rel := Relationship{
+		ID:     rels.newID(n),
+		Type:   typ,
+		Target: target,
+	}
+	if external {
+		rel.TargetMode = REL_TARGETMODE
+	}
+	rels.Relationship = append(rels.Relationship, rel)
+	return rel.ID, nil
+}
+
+// RemoveRelationship removes the relationship id of the part
+// (the package when empty)
+func (f *Docx) RemoveRelationship(part, id string) error {
+	rels, err := f.root().PartRelationships(part)
+	if err != nil {
+		return err
+	}
+	for i, r := range rels.Relationship {
+		if r.ID == id {
+			rels.Relationship = append(rels.Relationship[:i], rels.Relationship[i+1:]...)
+			return nil
+		}
+	}
+	return ErrRefIDNotFound
+}
+
+// DanglingRelationships returns the internal relationships
+// whose target part is not in the package
+func (f *Docx) DanglingRelationships() ([]PartRelationship, error) {
+	f = f.root()
+	err := f.loadRelationships()
+	if err != nil {
+		return nil, err
+	}
+	files, _, err := f.listParts()
+	if err != nil {
+		return nil, err
+	}
+	written := make(map[string]bool, len(files))
+	for name := range files {
+		written[strings.ToLower(name)] = true
+	}
+	var dangling []PartRelationship
+	for _, s := range f.sortedRelationshipSets() {
+		for _, r := range s.rels.Relationship {
+			if r.TargetMode != REL_TARGETMODE && !written[strings.ToLower(relTargetPart(s.part, r.Target))] {
+				dangling = append(dangling, PartRelationship{Part: s.part, Relationship: r})
+			}
+		}
+	}
+	return dangling, nil
+}
+
+// UnusedRelationships returns the relationships which are referred by id
+// (e.g. images, hyperlinks, headers) but are not used by their part
+func (f *Docx) UnusedRelationships() ([]PartRelationship, error) {
+	f = f.root()
+	err := f.loadRelationships()
+	if err != nil {
+		return nil, err
+	}
+	var unused []PartRelationship
+	for _, s := range f.sortedRelationshipSets() {
+		if s.part == "" {
+			continue
+		}
+		var ids map[string]bool
+		for _, r := range s.rels.Relationship {
+			if implicitRelationship(r.Type) {
+				continue
+			}
+			if ids == nil {
+				ids, err = f.referredIDs(s.part)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !ids[r.ID] {
+				unused = append(unused, PartRelationship{Part: s.part, Relationship: r})
+			}
+		}
+	}
+	return unused, nil
+}
+
+// implicitRelationships are the types of relationship found by type
+// rather than referred by id
+var implicitRelationships = map[string]bool{
+	"styles": true, "stylesWithEffects": true, "settings": true, "webSettings": true,
+	"numbering": true, "fontTable": true, "theme": true, "footnotes": true, "endnotes": true,
+	"comments": true, "commentsExtended": true, "commentsIds": true, "commentsExtensible": true,
+	"people": true, "customXml": true, "customXmlProps": true, "glossaryDocument": true,
+	"officeDocument": true, "core-properties": true, "extended-properties": true,
+	"custom-properties": true, "thumbnail": true,
+}
+
+// implicitRelationship tells whether the relationship type typ is found by type
+func implicitRelationship(typ string) bool {
+	return implicitRelationships[path.Base(typ)]
+}
+
+// referredIDs returns the relationship ids referred in the part name,
+// nil if there is no such part
+func (f *Docx) referredIDs(name string) (map[string]bool, error) {
+	data, err := f.renderPart(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, 16)
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			for _, a := range tt.Attr {
+				if strings.HasSuffix(a.Name.Space, "/relationships") || a.Name.Local == "relid" {
+					ids[a.Value] = true
+				}
+			}
+		}
+	}
+}
+
+// partRelationships are the relationships of a part
+type partRelationships struct {
+	part string
+	rels *Relationships
+}
+
+// sortedRelationshipSets returns the relationships loaded by part name
+func (f *Docx) sortedRelationshipSets() []partRelationships {
+	sets := f.relationshipSets()
+	sorted := make([]partRelationships, 0, len(sets))
+	for part, rels := range sets {
+		sorted = append(sorted, partRelationships{part: part, rels: rels})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].part < sorted[j].part
+	})
+	return sorted
+}
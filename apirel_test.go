@@ -0,0 +1,130 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestForeignRelationshipIDs(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddLink("link", "https://example.com")
+	w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddLink("header", "https://example.org")
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the ids are renamed the way other editors name them
+	out := rewriteZip(t, buf.Bytes(), func(_, content string) string {
+		return strings.ReplaceAll(content, `"rId`, `"relId`)
+	})
+	doc, err := Parse(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, err := doc.PartRelationships("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels.Relationship) == 0 || !strings.HasPrefix(rels.Relationship[0].ID, "relId") {
+		t.Fatal("the package relationships are not parsed")
+	}
+	rels, err = doc.PartRelationships("word/header1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels.Relationship) != 1 || rels.Relationship[0].Target != "https://example.org" {
+		t.Fatalf("unexpected header relationships %v", rels.Relationship)
+	}
+
+	id, err := doc.AddRelationship(DOCUMENT_PART, REL_IMAGE, "media/missing.png", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, err := doc.ReferTarget(id); err != nil || target != "media/missing.png" {
+		t.Fatalf("added relationship %s gives %s, %v", id, target, err)
+	}
+	dangling, err := doc.DanglingRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 1 || dangling[0].ID != id || dangling[0].Part != DOCUMENT_PART {
+		t.Fatalf("unexpected dangling relationships %v", dangling)
+	}
+	unused, err := doc.UnusedRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unused) != 1 || unused[0].ID != id {
+		t.Fatalf("unexpected unused relationships %v", unused)
+	}
+
+	err = doc.RemoveRelationship(DOCUMENT_PART, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = doc.RemoveRelationship(DOCUMENT_PART, id); err != ErrRefIDNotFound {
+		t.Fatalf("removing twice gives %v", err)
+	}
+	dangling, err = doc.DanglingRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 0 {
+		t.Fatalf("unexpected dangling relationships %v", dangling)
+	}
+	buf.Reset()
+	_, err = doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDanglingRelationshipsKeepDocument(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddBulletList()
+	ct, err := w.loadContentTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, overrides := len(w.docRelation.Relationship), len(ct.Overrides)
+	dangling, err := w.DanglingRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 0 {
+		t.Fatalf("unexpected dangling relationships %v", dangling)
+	}
+	if len(w.docRelation.Relationship) != rels || w.contentTypes != ct || len(ct.Overrides) != overrides {
+		t.Fatal("the relationships or the content types are changed")
+	}
+
+	// the numbering relationship is only added when writing
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.hasRelation(REL_NUMBERING) || len(w.docRelation.Relationship) != rels+1 {
+		t.Fatal("the numbering relationship is not added")
+	}
+}
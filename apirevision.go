@@ -0,0 +1,624 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// revisionTracking is the author and date of the tracked changes
+type revisionTracking struct {
+	author string
+	date   string
+}
+
+// TrackChanges records the following edits as tracked changes of author made at date:
+// the added texts are inserted, the formatting of runs keeps the previous one
+// and the removed paragraphs are deleted
+func (f *Docx) TrackChanges(author string, date time.Time) *Docx {
+	f.root().tracking = &revisionTracking{
+		author: author,
+		date:   date.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	return f
+}
+
+// StopTrackingChanges makes the following edits plain ones
+func (f *Docx) StopTrackingChanges() *Docx {
+	f.root().tracking = nil
+	return f
+}
+
+// IsTrackingChanges tells whether the edits are recorded as tracked changes
+func (f *Docx) IsTrackingChanges() bool {
+	return f.root().tracking != nil
+}
+
+// AcceptAllRevisions accepts all the tracked changes of the document
+func (f *Docx) AcceptAllRevisions() {
+	f.resolveRevisions(&revisionResolver{accept: true})
+}
+
+// RejectAllRevisions rejects all the tracked changes of the document
+func (f *Docx) RejectAllRevisions() {
+	f.resolveRevisions(&revisionResolver{})
+}
+
+// AcceptRevisionsOf accepts the tracked changes made by the authors
+func (f *Docx) AcceptRevisionsOf(author ...string) {
+	f.resolveRevisions(&revisionResolver{accept: true, authors: author})
+}
+
+// RejectRevisionsOf rejects the tracked changes made by the authors
+func (f *Docx) RejectRevisionsOf(author ...string) {
+	f.resolveRevisions(&revisionResolver{authors: author})
+}
+
+func (f *Docx) resolveRevisions(rv *revisionResolver) {
+	f = f.root()
+	rv.moves = make(map[int]struct{}, 8)
+	f.Document.Body.Items = rv.items(f.Document.Body.Items)
+	for _, h := range f.headers {
+		h.Items = rv.items(h.Items)
+	}
+	for _, h := range f.footers {
+		h.Items = rv.items(h.Items)
+	}
+	for _, notes := range []*Notes{f.footnotes, f.endnotes} {
+		if notes == nil {
+			continue
+		}
+		for _, n := range notes.Notes {
+			n.Items = rv.items(n.Items)
+		}
+	}
+	for _, c := range f.Comments() {
+		c.Items = rv.items(c.Items)
+	}
+}
+
+// revisionResolver accepts or rejects the tracked changes
+type revisionResolver struct {
+	accept  bool
+	authors []string         // authors to resolve, all if empty
+	moves   map[int]struct{} // ids of the resolved move ranges
+}
+
+func (rv *revisionResolver) match(author string) bool {
+	if len(rv.authors) == 0 {
+		return true
+	}
+	for _, a := range rv.authors {
+		if a == author {
+			return true
+		}
+	}
+	return false
+}
+
+// items resolves the paragraphs and tables of items, the content
+// of a paragraph whose mark is removed is merged into the next paragraph
+func (rv *revisionResolver) items(items []interface{}) []interface{} {
+	nitems := make([]interface{}, 0, len(items))
+	var pending *Paragraph
+	for _, item := range items {
+		p, ok := item.(*Paragraph)
+		if !ok {
+			if pending != nil {
+				nitems = append(nitems, pending)
+				pending = nil
+			}
+			if t, ok := item.(*Table); ok && !rv.table(t) {
+				continue // all the rows are removed
+			}
+			nitems = append(nitems, item)
+			continue
+		}
+		rv.paragraph(p)
+		if pending != nil {
+			children := make([]interface{}, 0, len(pending.Children)+len(p.Children))
+			children = append(children, pending.Children...)
+			p.Children = append(children, p.Children...)
+			pending = nil
+		}
+		if rv.removeMark(p) {
+			pending = p
+			continue
+		}
+		nitems = append(nitems, p)
+	}
+	if pending != nil {
+		nitems = append(nitems, pending)
+	}
+	return nitems
+}
+
+// table resolves the rows and the cells of t, it tells whether rows remain
+func (rv *revisionResolver) table(t *Table) bool {
+	rows := t.Rows[:0]
+	for _, tr := range t.Rows {
+		if rv.removeRow(tr) {
+			continue
+		}
+		rows = append(rows, tr)
+		for _, tc := range tr.Cells {
+			items := make([]interface{}, len(tc.Paragraphs))
+			for i, p := range tc.Paragraphs {
+				items[i] = p
+			}
+			items = rv.items(items)
+			tc.Paragraphs = tc.Paragraphs[:0]
+			for _, item := range items {
+				tc.Paragraphs = append(tc.Paragraphs, item.(*Paragraph))
+			}
+			tables := tc.Tables[:0]
+			for _, nt := range tc.Tables {
+				if rv.table(nt) {
+					tables = append(tables, nt)
+				}
+			}
+			tc.Tables = tables
+		}
+	}
+	t.Rows = rows
+	return len(rows) > 0
+}
+
+// removeRow tells whether the row is removed, the row revisions are dropped
+func (rv *revisionResolver) removeRow(tr *WTableRow) bool {
+	if tr.Properties == nil {
+		return false
+	}
+	removed := false
+	if tr.Properties.Ins != nil && rv.match(tr.Properties.Ins.Author) {
+		removed = !rv.accept
+		tr.Properties.Ins = nil
+	}
+	if tr.Properties.Del != nil && rv.match(tr.Properties.Del.Author) {
+		removed = removed || rv.accept
+		tr.Properties.Del = nil
+	}
+	return removed
+}
+
+func (rv *revisionResolver) paragraph(p *Paragraph) {
+	p.Children = rv.children(p.Children)
+	if p.Properties == nil {
+		return
+	}
+	if p.Properties.RunProperties != nil {
+		p.Properties.RunProperties = rv.runProperties(p.Properties.RunProperties)
+	}
+	c := p.Properties.Change
+	if c == nil || !rv.match(c.Author) {
+		return
+	}
+	if rv.accept {
+		p.Properties.Change = nil
+		return
+	}
+	old := &ParagraphProperties{}
+	if c.ParagraphProperties != nil {
+		*old = *c.ParagraphProperties
+	}
+	old.RunProperties = p.Properties.RunProperties
+	old.Change = nil
+	p.Properties = old
+}
+
+// removeMark tells whether the paragraph mark is removed, the mark revisions are dropped
+func (rv *revisionResolver) removeMark(p *Paragraph) bool {
+	if p.Properties == nil || p.Properties.RunProperties == nil {
+		return false
+	}
+	rp := p.Properties.RunProperties
+	removed := false
+	if rp.Ins != nil && rv.match(rp.Ins.Author) {
+		removed = !rv.accept
+		rp.Ins = nil
+	}
+	if rp.Del != nil && rv.match(rp.Del.Author) {
+		removed = removed || rv.accept
+		rp.Del = nil
+	}
+	return removed
+}
+
+func (rv *revisionResolver) children(children []interface{}) []interface{} {
+	nchildren := make([]interface{}, 0, len(children))
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Revision:
+			if !rv.match(o.Author) {
+				o.Children = rv.children(o.Children)
+				nchildren = append(nchildren, o)
+				continue
+			}
+			k := o.Kind()
+			if (k == REVISION_INS || k == REVISION_MOVE_TO) != rv.accept {
+				continue
+			}
+			for _, oc := range rv.children(o.Children) {
+				if k == REVISION_DEL || k == REVISION_MOVE_FROM {
+					undelete(oc)
+				}
+				nchildren = append(nchildren, oc)
+			}
+		case *MoveRangeStart:
+			if rv.match(o.Author) {
+				rv.moves[o.ID] = struct{}{}
+				continue
+			}
+			nchildren = append(nchildren, o)
+		case *MoveRangeEnd:
+			if _, ok := rv.moves[o.ID]; ok {
+				continue
+			}
+			nchildren = append(nchildren, o)
+		case *Run:
+			rv.run(o)
+			nchildren = append(nchildren, o)
+		case *Hyperlink:
+			rv.run(&o.Run)
+			nchildren = append(nchildren, o)
+		default:
+			nchildren = append(nchildren, c)
+		}
+	}
+	return nchildren
+}
+
+func (rv *revisionResolver) run(r *Run) {
+	if r.RunProperties != nil {
+		r.RunProperties = rv.runProperties(r.RunProperties)
+	}
+}
+
+// runProperties resolves the formatting change of rp
+func (rv *revisionResolver) runProperties(rp *RunProperties) *RunProperties {
+	c := rp.Change
+	if c == nil || !rv.match(c.Author) {
+		return rp
+	}
+	if rv.accept {
+		rp.Change = nil
+		return rp
+	}
+	old := &RunProperties{}
+	if c.RunProperties != nil {
+		*old = *c.RunProperties
+	}
+	old.Ins, old.Del = rp.Ins, rp.Del
+	old.Change = nil
+	return old
+}
+
+// undelete turns the deleted texts of a run back to texts
+func undelete(c interface{}) {
+	var r *Run
+	switch o := c.(type) {
+	case *Run:
+		r = o
+	case *Hyperlink:
+		r = &o.Run
+	default:
+		return
+	}
+	for i, rc := range r.Children {
+		if t, ok := rc.(*DelText); ok {
+			r.Children[i] = &Text{XMLSpace: t.XMLSpace, Text: t.Text}
+		}
+	}
+}
+
+// newRevisionID returns an unused revision id
+func (f *Docx) newRevisionID() int {
+	return f.NewID(ID_REVISION)
+}
+
+// newTrackedRevision returns a new revision of kind
+// if the changes are tracked (or nil)
+func (f *Docx) newTrackedRevision(kind _revision) *Revision {
+	if f == nil {
+		return nil
+	}
+	t := f.root().tracking
+	if t == nil {
+		return nil
+	}
+	return &Revision{
+		XMLName: xml.Name{Local: "w:" + string(kind)},
+		ID:      f.newRevisionID(),
+		Author:  t.author,
+		Date:    t.date,
+		file:    f,
+	}
+}
+
+// newTrackedMark returns a new paragraph mark revision
+// if the changes are tracked (or nil)
+func (f *Docx) newTrackedMark() *RevisionMark {
+	if f == nil {
+		return nil
+	}
+	t := f.root().tracking
+	if t == nil {
+		return nil
+	}
+	return &RevisionMark{ID: f.newRevisionID(), Author: t.author, Date: t.date}
+}
+
+// walkParagraphs calls fn on all the paragraphs of the document
+func (f *Docx) walkParagraphs(fn func(p *Paragraph)) {
+	f = f.root()
+	items := make([]interface{}, 0, len(f.Document.Body.Items)+16)
+	items = append(items, f.Document.Body.Items...)
+	for _, h := range f.headers {
+		items = append(items, h.Items...)
+	}
+	for _, h := range f.footers {
+		items = append(items, h.Items...)
+	}
+	for _, notes := range []*Notes{f.footnotes, f.endnotes} {
+		if notes == nil {
+			continue
+		}
+		for _, n := range notes.Notes {
+			items = append(items, n.Items...)
+		}
+	}
+	for _, c := range f.Comments() {
+		items = append(items, c.Items...)
+	}
+	for _, p := range paragraphsOf(items) {
+		fn(p)
+	}
+}
+
+// maxRowRevisionID returns the greatest revision id used in the rows of the table
+func maxRowRevisionID(t *Table) int {
+	max := 0
+	for _, tr := range t.Rows {
+		if tr.Properties != nil {
+			for _, m := range []*RevisionMark{tr.Properties.Ins, tr.Properties.Del} {
+				if m != nil && m.ID > max {
+					max = m.ID
+				}
+			}
+		}
+		for _, tc := range tr.Cells {
+			for _, nt := range tc.Tables {
+				if id := maxRowRevisionID(nt); id > max {
+					max = id
+				}
+			}
+		}
+	}
+	return max
+}
+
+// maxRevisionID returns the greatest revision id used in the paragraph
+func maxRevisionID(p *Paragraph) int {
+	max := 0
+	upd := func(id int) {
+		if id > max {
+			max = id
+		}
+	}
+	rpr := func(rp *RunProperties) {
+		if rp == nil {
+			return
+		}
+		if rp.Ins != nil {
+			upd(rp.Ins.ID)
+		}
+		if rp.Del != nil {
+			upd(rp.Del.ID)
+		}
+		if rp.Change != nil {
+			upd(rp.Change.ID)
+		}
+	}
+	if p.Properties != nil {
+		rpr(p.Properties.RunProperties)
+		if p.Properties.Change != nil {
+			upd(p.Properties.Change.ID)
+		}
+	}
+	var children func(cs []interface{})
+	children = func(cs []interface{}) {
+		for _, c := range cs {
+			switch o := c.(type) {
+			case *Revision:
+				upd(o.ID)
+				children(o.Children)
+			case *MoveRangeStart:
+				upd(o.ID)
+			case *MoveRangeEnd:
+				upd(o.ID)
+			case *Run:
+				rpr(o.RunProperties)
+			case *Hyperlink:
+				rpr(o.Run.RunProperties)
+			}
+		}
+	}
+	children(p.Children)
+	return max
+}
+
+// appendRun appends the run, within an insertion if the changes are tracked
+func (p *Paragraph) appendRun(r *Run) {
+	if p.file == nil || p.file.root().tracking == nil {
+		p.Children = append(p.Children, r)
+		return
+	}
+	r.inserted = true
+	t := p.file.root().tracking
+	if len(p.Children) > 0 {
+		// extend the previous insertion made by the same author
+		last, ok := p.Children[len(p.Children)-1].(*Revision)
+		if ok && last.Kind() == REVISION_INS && last.Author == t.author && last.Date == t.date {
+			last.Children = append(last.Children, r)
+			return
+		}
+	}
+	rv := p.file.newTrackedRevision(REVISION_INS)
+	rv.Children = append(rv.Children, r)
+	p.Children = append(p.Children, rv)
+}
+
+// trackFormat records the current properties of the run
+// before they are changed if the changes are tracked
+func (r *Run) trackFormat() {
+	if r.RunProperties == nil {
+		r.RunProperties = &RunProperties{}
+	}
+	if r.inserted || r.file == nil || r.RunProperties.Change != nil {
+		return
+	}
+	t := r.file.root().tracking
+	if t == nil {
+		return
+	}
+	old := *r.RunProperties
+	old.Ins, old.Del, old.Change = nil, nil, nil
+	r.RunProperties.Change = &RPrChange{
+		ID:            r.file.newRevisionID(),
+		Author:        t.author,
+		Date:          t.date,
+		RunProperties: &old,
+	}
+}
+
+// RemoveParagraph removes the paragraph from the body (tables included),
+// if the changes are tracked its content and its mark are deleted instead.
+//
+// It returns false if the paragraph is not found.
+func (f *Docx) RemoveParagraph(p *Paragraph) bool {
+	var found bool
+	if f.root().tracking == nil {
+		f.Document.Body.Items, found = removeParagraph(f.Document.Body.Items, p)
+		return found
+	}
+	for _, o := range paragraphsOf(f.Document.Body.Items) {
+		if o == p {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	p.Children = f.deleteChildren(p.Children)
+	if p.Properties == nil {
+		p.Properties = &ParagraphProperties{}
+	}
+	if p.Properties.RunProperties == nil {
+		p.Properties.RunProperties = &RunProperties{}
+	}
+	if p.Properties.RunProperties.Del == nil {
+		p.Properties.RunProperties.Del = f.newTrackedMark()
+	}
+	return true
+}
+
+// deleteChildren wraps the runs of children into deletions
+func (f *Docx) deleteChildren(children []interface{}) []interface{} {
+	nchildren := make([]interface{}, 0, len(children))
+	var del *Revision
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run, *Hyperlink:
+			deleteText(o)
+			if del == nil {
+				del = f.newTrackedRevision(REVISION_DEL)
+				nchildren = append(nchildren, del)
+			}
+			del.Children = append(del.Children, c)
+			continue
+		case *Revision:
+			if k := o.Kind(); k == REVISION_INS || k == REVISION_MOVE_TO {
+				o.Children = f.deleteChildren(o.Children)
+			}
+		}
+		del = nil
+		nchildren = append(nchildren, c)
+	}
+	return nchildren
+}
+
+// deleteText turns the texts of a run to deleted texts
+func deleteText(c interface{}) {
+	var r *Run
+	switch o := c.(type) {
+	case *Run:
+		r = o
+	case *Hyperlink:
+		r = &o.Run
+	default:
+		return
+	}
+	for i, rc := range r.Children {
+		if t, ok := rc.(*Text); ok {
+			r.Children[i] = &DelText{XMLSpace: "preserve", Text: t.Text}
+		}
+	}
+}
+
+func removeParagraph(items []interface{}, p *Paragraph) ([]interface{}, bool) {
+	for i, item := range items {
+		switch o := item.(type) {
+		case *Paragraph:
+			if o == p {
+				return append(items[:i:i], items[i+1:]...), true
+			}
+		case *Table:
+			if o.removeParagraph(p) {
+				return items, true
+			}
+		}
+	}
+	return items, false
+}
+
+func (t *Table) removeParagraph(p *Paragraph) bool {
+	for _, tr := range t.Rows {
+		for _, tc := range tr.Cells {
+			for i, o := range tc.Paragraphs {
+				if o != p {
+					continue
+				}
+				if len(tc.Paragraphs) == 1 {
+					// a cell must end with a paragraph
+					p.Children = p.Children[:0]
+					return true
+				}
+				tc.Paragraphs = append(tc.Paragraphs[:i:i], tc.Paragraphs[i+1:]...)
+				return true
+			}
+			for _, nt := range tc.Tables {
+				if nt.removeParagraph(p) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
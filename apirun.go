@@ -22,6 +22,7 @@ package docx
 
 // Color allows to set run color
 func (r *Run) Color(color string) *Run {
+	r.trackFormat()
 	r.RunProperties.Color = &Color{
 		Val: color,
 	}
@@ -30,6 +31,7 @@ func (r *Run) Color(color string) *Run {
 
 // Size allows to set run size
 func (r *Run) Size(size string) *Run {
+	r.trackFormat()
 	r.RunProperties.Size = &Size{
 		Val: size,
 	}
@@ -38,14 +40,25 @@ func (r *Run) Size(size string) *Run {
 
 // SizeCs allows to set run sizecs
 func (r *Run) SizeCs(size string) *Run {
+	r.trackFormat()
 	r.RunProperties.SizeCs = &SizeCs{
 		Val: size,
 	}
 	return r
 }
 
+// Style allows to set the character style of the run
+func (r *Run) Style(val string) *Run {
+	r.trackFormat()
+	r.RunProperties.RunStyle = &RunStyle{
+		Val: val,
+	}
+	return r
+}
+
 // Shade allows to set run shade
 func (r *Run) Shade(val, color, fill string) *Run {
+	r.trackFormat()
 	r.RunProperties.Shade = &Shade{
 		Val:   val,
 		Color: color,
@@ -56,6 +69,7 @@ func (r *Run) Shade(val, color, fill string) *Run {
 
 // Spacing allows to set run spacing
 func (r *Run) Spacing(line int) *Run {
+	r.trackFormat()
 	r.RunProperties.Spacing = &Spacing{
 		Line: line,
 	}
@@ -64,6 +78,7 @@ func (r *Run) Spacing(line int) *Run {
 
 // Bold ...
 func (r *Run) Bold(val ...bool) *Run {
+	r.trackFormat()
 	if len(val) == 0 || val[0] {
 		r.RunProperties.Bold = &Bold{}
 	} else {
@@ -74,6 +89,7 @@ func (r *Run) Bold(val ...bool) *Run {
 
 // Italic ...
 func (r *Run) Italic(val ...bool) *Run {
+	r.trackFormat()
 	if len(val) == 0 || val[0] {
 		r.RunProperties.Italic = &Italic{}
 	} else {
@@ -102,11 +118,13 @@ const (
 
 // Underline has several possible values including
 func (r *Run) Underline(val _underline) *Run {
+	r.trackFormat()
 	r.RunProperties.Underline = &Underline{Val: (string)(val)}
 	return r
 }
 
 func (r *Run) UnderlineSingle(val ...bool) *Run {
+	r.trackFormat()
 	if len(val) == 0 || val[0] {
 		r.RunProperties.Underline = &Underline{Val: "single"}
 	} else {
@@ -117,12 +135,14 @@ func (r *Run) UnderlineSingle(val ...bool) *Run {
 
 // Highlight ...
 func (r *Run) Highlight(val string) *Run {
+	r.trackFormat()
 	r.RunProperties.Highlight = &Highlight{Val: val}
 	return r
 }
 
 // Strike ...
 func (r *Run) Strike(val ...bool) *Run {
+	r.trackFormat()
 	trueFalseStr := "false"
 	if len(val) == 0 || val[0] {
 		trueFalseStr = "true"
@@ -139,6 +159,7 @@ func (r *Run) AddTab() *Run {
 
 // Font sets the font of the run
 func (r *Run) Font(ascii, eastAsia, hansi, hint string) *Run {
+	r.trackFormat()
 	r.RunProperties.Fonts = &RunFonts{
 		ASCII:    ascii,
 		EastAsia: eastAsia,
@@ -153,6 +174,7 @@ func (r *Run) Font(ascii, eastAsia, hansi, hint string) *Run {
 // if a parameter is a boolean it will be used to set the check (true) or nocheck (false)
 // the two parameter can be provide together
 func (r *Run) LangCheck(check ...any) {
+	r.trackFormat()
 	proof := true
 	lang := ""
 	for _, c := range check {
@@ -20,15 +20,12 @@
 
 package docx
 
-import (
-	"strconv"
-	"sync/atomic"
-)
+import "strconv"
 
 // AddInlineShape adds wsp named drawing to paragraph
 func (p *Paragraph) AddInlineShape(w, h int64, name, bwMode, prst string, ln *ALine) *Run {
-	idn := int(atomic.AddUintptr(&p.file.docID, 1))
-	id := strconv.Itoa(int(p.file.IncreaseID(name)))
+	idn := p.file.NewID(ID_DRAWING)
+	id := strconv.Itoa(idn)
 	d := &Drawing{
 		Inline: &WPInline{
 			Extent: &WPExtent{
@@ -84,8 +81,8 @@ func (p *Paragraph) AddInlineShape(w, h int64, name, bwMode, prst string, ln *AL
 
 // AddAnchorShape adds wsp named drawing to paragraph
 func (p *Paragraph) AddAnchorShape(w, h int64, name, bwMode, prst string, ln *ALine) *Run {
-	idn := int(atomic.AddUintptr(&p.file.docID, 1))
-	id := strconv.Itoa(int(p.file.IncreaseID(name)))
+	idn := p.file.NewID(ID_DRAWING)
+	id := strconv.Itoa(idn)
 	d := &Drawing{
 		Anchor: &WPAnchor{
 			LayoutInCell: 1,
@@ -0,0 +1,578 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// ErrStreamClosed is returned when a closed StreamWriter is used
+var ErrStreamClosed = errors.New("stream writer closed")
+
+// StreamWriter writes a document whose body items are flushed to
+// word/document.xml as they are appended and then dropped, so that
+// the memory does not grow with the body.
+//
+// The content types and the package relationships are written before
+// the first item of the body: the parts but the media (headers, footers,
+// notes, comments, numbering...) must exist by then, Close fails on the
+// others. The media are written when the items using them are flushed,
+// to a temporary file while the document part is open, and their data
+// is dropped. The other parts and the relationships are written on Close.
+type StreamWriter struct {
+	doc   *Docx
+	opts  *WriteOptions
+	zw    *zip.Writer
+	head  []byte        // head opens the document and the body
+	types *ContentTypes // types are the content types written, nil until then
+	w     io.Writer     // w is the document part, nil until it is open
+	enc   *xml.Encoder
+	end   string // end closes the body and the document
+
+	media int         // media is the number of media of doc already written
+	spill *os.File    // spill keeps the media written while the document part is open
+	sw    *zip.Writer // sw writes the media to spill
+
+	table *Table // table is the table being written, its last row is held back
+	rows  int    // rows is the number of rows of table already written
+	next  int    // next is the first kept item of table not written yet
+
+	err error
+}
+
+// NewStreamWriter starts a document written to w, the body items of
+// template are written first and its parts are used for the others.
+// The template is owned by the writer, New().WithDefaultTheme() is used if it is nil
+func NewStreamWriter(w io.Writer, template *Docx) (*StreamWriter, error) {
+	return NewStreamWriterWithOptions(w, template, nil)
+}
+
+// NewStreamWriterWithOptions is NewStreamWriter with the options opts of the package
+func NewStreamWriterWithOptions(w io.Writer, template *Docx, opts *WriteOptions) (*StreamWriter, error) {
+	if template == nil {
+		template = New().WithDefaultTheme()
+	}
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+
+	head := template.Document
+	head.Body.Items = nil
+	data, err := xml.Marshal(&head)
+	if err != nil {
+		return nil, err
+	}
+	body := []byte("<w:body>")
+	i := bytes.Index(data, append(body, "</w:body>"...))
+	if i < 0 {
+		return nil, errors.New("docx: no body in the document")
+	}
+	i += len(body)
+
+	return &StreamWriter{
+		doc:  template,
+		opts: opts,
+		zw:   opts.newZipWriter(w),
+		head: data[:i],
+		end:  string(data[i:]),
+	}, nil
+}
+
+// Docx returns the document being written to reach its styles, numbering,
+// headers, footers... Its body items are written and dropped on each flush,
+// but the section properties of the body which are written on Close
+func (s *StreamWriter) Docx() *Docx {
+	return s.doc
+}
+
+// AddParagraph writes the items appended so far and adds a new paragraph,
+// the errors are returned by Flush and Close
+func (s *StreamWriter) AddParagraph() *Paragraph {
+	_ = s.flush(false)
+	return s.doc.AddParagraph()
+}
+
+// AddTable writes the items appended so far and adds a new table by col*row.
+// The rows added later by AddRow are written by Flush while the table is
+// the last item of the body
+//
+// unit: twips (1/20 point)
+func (s *StreamWriter) AddTable(row, col, tableWidth int) *Table {
+	_ = s.flush(false)
+	return s.doc.AddTable(row, col, tableWidth)
+}
+
+// Flush writes the items appended so far. If the last one is a table,
+// all its rows but the last are written and it stays open for more rows
+func (s *StreamWriter) Flush() error {
+	return s.flush(true)
+}
+
+// Close writes the end of the document and the other parts of the package,
+// it does not close the underlying writer
+func (s *StreamWriter) Close() error {
+	err := s.flush(false)
+	if err != nil {
+		return err
+	}
+	if s.w == nil {
+		err = s.open()
+		if err != nil {
+			return s.fail(err)
+		}
+	}
+	// only the section properties are left
+	for _, item := range s.doc.Document.Body.Items {
+		err = s.enc.Encode(item)
+		if err != nil {
+			return s.fail(err)
+		}
+	}
+	s.doc.Document.Body.Items = nil
+	err = s.enc.Flush()
+	if err != nil {
+		return s.fail(err)
+	}
+	_, err = io.WriteString(s.w, s.end)
+	if err != nil {
+		return s.fail(err)
+	}
+
+	files, err := s.doc.packParts()
+	if err != nil {
+		return s.fail(err)
+	}
+	delete(files, CONTENT_TYPES)
+	delete(files, PACKAGE_RELS_PART)
+	delete(files, DOCUMENT_PART)
+	for i := range s.doc.media {
+		delete(files, s.doc.media[i].String())
+	}
+	for name := range files {
+		if s.types.typeOf(name) != s.doc.contentTypes.typeOf(name) {
+			return s.fail(fmt.Errorf("docx: %s is added after the content types are written", name))
+		}
+	}
+	err = writeParts(s.zw, files, s.opts)
+	if err != nil {
+		return s.fail(err)
+	}
+	err = s.copySpill()
+	if err != nil {
+		return s.fail(err)
+	}
+	err = s.zw.Close()
+	if err != nil {
+		return s.fail(err)
+	}
+	s.err = ErrStreamClosed
+	return nil
+}
+
+// open writes the content types, the package relationships
+// and the media added so far, and then opens the document part
+func (s *StreamWriter) open() error {
+	files, err := s.doc.packParts()
+	if err != nil {
+		return err
+	}
+	// the media added later get the content type of their extension
+	ct := s.doc.contentTypes
+	exts := make([]string, 0, len(mediaContentTypes))
+	for ext := range mediaContentTypes {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	for _, ext := range exts {
+		if ct.typeOf("media."+ext) == "" {
+			ct.Default(ext, mediaContentTypes[ext])
+		}
+	}
+	s.types = &ContentTypes{
+		Defaults:  append([]ContentTypeDefault(nil), ct.Defaults...),
+		Overrides: append([]ContentTypeOverride(nil), ct.Overrides...),
+	}
+
+	first := make(map[string]io.WriterTo, 2)
+	for _, name := range []string{CONTENT_TYPES, PACKAGE_RELS_PART} {
+		if files[name] != nil {
+			first[name] = files[name]
+		}
+	}
+	err = writeParts(s.zw, first, s.opts)
+	if err != nil {
+		return err
+	}
+	err = s.writeMedia()
+	if err != nil {
+		return err
+	}
+
+	dw, err := s.zw.CreateHeader(&zip.FileHeader{
+		Name:     DOCUMENT_PART,
+		Method:   s.opts.method(),
+		Modified: s.opts.modTime(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(dw, xml.Header)
+	if err != nil {
+		return err
+	}
+	_, err = dw.Write(s.head)
+	if err != nil {
+		return err
+	}
+	s.w, s.enc = dw, xml.NewEncoder(dw)
+	return nil
+}
+
+// writeMedia writes the media added since the last call and drops their data,
+// they go to the spill file while the document part is open
+func (s *StreamWriter) writeMedia() (err error) {
+	for ; s.media < len(s.doc.media); s.media++ {
+		m := &s.doc.media[s.media]
+		zw := s.zw
+		if s.w != nil {
+			if s.types.typeOf(m.String()) == "" {
+				return fmt.Errorf("docx: no content type for %s", m.String())
+			}
+			if s.spill == nil {
+				s.spill, err = os.CreateTemp("", "docx-media-*")
+				if err != nil {
+					return
+				}
+				s.sw = s.opts.newZipWriter(s.spill)
+			}
+			zw = s.sw
+		}
+		err = writePart(zw, m.String(), m, s.opts)
+		if err != nil {
+			return
+		}
+		m.Data, m.file = nil, nil
+	}
+	return
+}
+
+// copySpill copies the media of the spill file as they are compressed
+func (s *StreamWriter) copySpill() error {
+	if s.spill == nil {
+		return nil
+	}
+	defer s.removeSpill()
+	err := s.sw.Close()
+	if err != nil {
+		return err
+	}
+	size, err := s.spill.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(s.spill, size)
+	if err != nil {
+		return err
+	}
+	modTime := s.opts.modTime()
+	for _, file := range zr.File {
+		err = copyRaw(s.zw, file, file.Name, modTime)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeSpill closes and removes the spill file
+func (s *StreamWriter) removeSpill() {
+	if s.spill == nil {
+		return
+	}
+	_ = s.spill.Close()
+	_ = os.Remove(s.spill.Name())
+	s.spill, s.sw = nil, nil
+}
+
+// fail makes err the error of all the next calls
+func (s *StreamWriter) fail(err error) error {
+	s.removeSpill()
+	s.err = err
+	return err
+}
+
+// flush writes the body items but the section properties,
+// the last table stays open if open is set
+func (s *StreamWriter) flush(open bool) error {
+	if s.err != nil {
+		return s.err
+	}
+	items := s.doc.Document.Body.Items
+	if s.w == nil {
+		// the document part is opened by the first item which is not the section properties
+		i := 0
+		for i < len(items) {
+			if _, ok := items[i].(*SectPr); !ok {
+				break
+			}
+			i++
+		}
+		if i == len(items) {
+			return nil
+		}
+		err := s.open()
+		if err != nil {
+			return s.fail(err)
+		}
+	}
+	left := make([]interface{}, 0, 4)
+	for i, item := range items {
+		switch o := item.(type) {
+		case *SectPr:
+			left = append(left, o)
+		case *Table:
+			last := open && i == len(items)-1
+			err := s.writeTable(o, last)
+			if err != nil {
+				return s.fail(err)
+			}
+			if last {
+				left = append(left, o)
+			}
+		default:
+			err := s.enc.Encode(o)
+			if err != nil {
+				return s.fail(err)
+			}
+		}
+	}
+	s.doc.Document.Body.Items = left
+	err := s.enc.Flush()
+	if err != nil {
+		return s.fail(err)
+	}
+	err = s.writeMedia()
+	if err != nil {
+		return s.fail(err)
+	}
+	return nil
+}
+
+// writeTable writes the rows of t, all but the last one if open is set,
+// the end of the table is written when open is not set
+func (s *StreamWriter) writeTable(t *Table, open bool) (err error) {
+	rows := t.Rows
+	if open && len(rows) > 0 {
+		rows = rows[:len(rows)-1]
+	}
+	if t != s.table {
+		// an empty table is not written
+		if len(rows) == 0 {
+			return nil
+		}
+		err = t.encodeHead(s.enc)
+		if err != nil {
+			return
+		}
+		s.table, s.rows, s.next = t, 0, 0
+	}
+	cs := t.confStyles()
+	for i, r := range rows {
+		s.next, err = encodeKept(s.enc, t.items, s.next, s.rows)
+		if err != nil {
+			return
+		}
+		err = s.enc.Encode(cs.styledRow(s.rows, r, !open && i == len(rows)-1))
+		if err != nil {
+			return
+		}
+		s.rows++
+	}
+	t.Rows = append([]*WTableRow(nil), t.Rows[len(rows):]...)
+	if open {
+		return
+	}
+	s.next, err = encodeKept(s.enc, t.items, s.next, s.rows)
+	if err != nil {
+		return
+	}
+	s.table = nil
+	return s.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "w:tbl"}})
+}
+
+// StreamReader reads the body items of a document one at a time,
+// the other parts are parsed when it is opened and the media are
+// read when they are used
+type StreamReader struct {
+	doc  *Docx
+	zf   io.ReadCloser
+	d    *xml.Decoder
+	free func() // free gives back the buffer of d
+	p    *parsing
+	ev   numberingEvaluator
+	body bool // body is set once the body is started
+
+	err error
+}
+
+// OpenStream opens the document of the reader to read its body with Next
+func OpenStream(reader io.ReaderAt, size int64) (*StreamReader, error) {
+	return OpenStreamWithOptions(reader, size, nil)
+}
+
+// OpenStreamWithOptions is OpenStream with the options opts of ParseWithOptions,
+// the media are always lazy
+func OpenStreamWithOptions(reader io.ReaderAt, size int64, opts *ParseOptions) (*StreamReader, error) {
+	o := ParseOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.LazyMedia, o.stream = true, true
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+	err = checkLimits(zipReader, &o)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := unpack(zipReader, &o)
+	if err != nil {
+		return nil, err
+	}
+	doc.initDocument()
+
+	var file *zip.File
+	for _, f := range zipReader.File {
+		if f.Name == DOCUMENT_PART {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, &ParseError{Part: DOCUMENT_PART, Err: fs.ErrNotExist}
+	}
+	zf, err := file.Open()
+	if err != nil {
+		return nil, &ParseError{Part: file.Name, Err: err}
+	}
+	d, free := newDecoder(zf)
+	s := &StreamReader{
+		doc:  doc,
+		zf:   zf,
+		d:    d,
+		free: free,
+		p:    &parsing{f: doc, file: file, d: d},
+		ev:   newNumberingEvaluator(doc),
+	}
+	return s, nil
+}
+
+// Docx returns the document being read, its body stays empty
+// but the items read are attached to it
+func (s *StreamReader) Docx() *Docx {
+	return s.doc
+}
+
+// Next returns the next item of the body: a *Paragraph, a *Table, a *SectPr
+// or an unsupported element. It returns io.EOF at the end of the body
+func (s *StreamReader) Next() (interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	for {
+		t, err := s.d.Token()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, s.fail(s.p.parseError(s.d.InputOffset(), err))
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			if !s.body {
+				if tt.Name.Local == "body" {
+					s.body = true
+					continue
+				}
+				if tt.Name.Local == "document" {
+					s.doc.Document.readRoot(tt)
+					continue
+				}
+				value, err := readUnknown(s.d, tt)
+				if err != nil {
+					return nil, s.fail(s.p.parseError(s.d.InputOffset(), err))
+				}
+				s.doc.Document.items = append(s.doc.Document.items, value)
+				continue
+			}
+			s.doc.parsing = s.p
+			item, err := s.doc.Document.Body.decodeItem(s.d, tt)
+			s.doc.parsing = nil
+			if err != nil {
+				return nil, s.fail(s.p.parseError(s.d.InputOffset(), err))
+			}
+			for _, p := range paragraphsOf([]interface{}{item}) {
+				p.label, p.labelSuffix = s.ev.label(p)
+			}
+			return item, nil
+		case xml.EndElement:
+			if s.body && tt.Name.Local == "body" {
+				s.release()
+				return nil, s.fail(io.EOF)
+			}
+		}
+	}
+}
+
+// Close releases the document part, it does not close the reader
+func (s *StreamReader) Close() error {
+	if s.zf == nil {
+		return nil
+	}
+	err := s.release()
+	if s.err == nil {
+		s.err = ErrStreamClosed
+	}
+	return err
+}
+
+// release closes the document part
+func (s *StreamReader) release() error {
+	if s.zf == nil {
+		return nil
+	}
+	s.free()
+	err := s.zf.Close()
+	s.zf = nil
+	return err
+}
+
+// fail makes err the error of all the next calls
+func (s *StreamReader) fail(err error) error {
+	s.err = err
+	return err
+}
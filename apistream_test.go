@@ -0,0 +1,240 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamWriter(t *testing.T) {
+	const rows = 1000
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fill writes the same content to a document and to a stream
+	fill := func(doc *Docx, addParagraph func() *Paragraph, addTable func(row, col, width int) *Table, flush func()) {
+		doc.WithA4Page()
+		doc.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("header")
+		addParagraph().AddText("title")
+		addParagraph().AddLink("link", "https://example.com/")
+		tbl := addTable(1, 2, 9000).Style("TableGrid", TABLE_STYLE_OPTION_FIRST_ROW|TABLE_STYLE_OPTION_LAST_ROW)
+		for i := 0; i < rows; i++ {
+			r := tbl.AddRow()
+			r.AddCell().AddParagraph().AddText(strconv.Itoa(i))
+			r.AddCell().AddParagraph().AddText("row")
+			if i%100 == 0 {
+				flush()
+			}
+		}
+		_, err := addParagraph().AddInlineDrawing(pic.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		addParagraph().AddText("end")
+	}
+
+	want := New().WithDefaultTheme()
+	fill(want, want.AddParagraph, want.AddTable, func() {})
+	// the stream writes the section properties last
+	items := want.Document.Body.Items
+	want.Document.Body.Items = append(items[1:], items[0])
+	var out bytes.Buffer
+	_, err = want.WriteTo(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stream bytes.Buffer
+	s, err := NewStreamWriter(&stream, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(s.Docx(), s.AddParagraph, s.AddTable, func() {
+		if err := s.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		items := s.Docx().Document.Body.Items
+		if tbl := items[len(items)-1].(*Table); len(tbl.Rows) != 1 {
+			t.Fatalf("%d rows are kept after a flush", len(tbl.Rows))
+		}
+	})
+	err = s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Close() != ErrStreamClosed {
+		t.Fatal("a closed stream can be closed again")
+	}
+	if m := s.Docx().Media("image1.gif"); m == nil || m.Data != nil {
+		t.Fatal("the media is kept once written")
+	}
+
+	partsOf := func(data []byte) ([]string, map[string][]byte) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(zr.File))
+		parts := make(map[string][]byte, len(zr.File))
+		for _, f := range zr.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, f.Name)
+			parts[f.Name], err = io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return names, parts
+	}
+	_, wantParts := partsOf(out.Bytes())
+	names, gotParts := partsOf(stream.Bytes())
+	if len(wantParts) != len(gotParts) {
+		t.Fatalf("%d parts streamed, %d expected", len(gotParts), len(wantParts))
+	}
+	if names[0] != CONTENT_TYPES || names[1] != PACKAGE_RELS_PART || names[2] != DOCUMENT_PART {
+		t.Fatalf("the parts are streamed in the order %v", names[:3])
+	}
+	for name, data := range wantParts {
+		// the streamed content types have the defaults of the media
+		if name != CONTENT_TYPES && !bytes.Equal(gotParts[name], data) {
+			t.Fatalf("%s differs from the one of WriteTo", name)
+		}
+	}
+	var ct ContentTypes
+	err = xml.Unmarshal(gotParts[CONTENT_TYPES], &ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if typ := ct.typeOf(name); typ == "" || strings.HasPrefix(name, WORD_FOLDER) && typ == "application/xml" {
+			t.Fatalf("%s has the content type %q", name, typ)
+		}
+	}
+
+	doc, err := Parse(bytes.NewReader(stream.Bytes()), int64(stream.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(doc.Document.Body.Items); n != 6 {
+		t.Fatalf("%d body items", n)
+	}
+	if tbl, ok := doc.Document.Body.Items[2].(*Table); !ok || len(tbl.Rows) != rows+1 {
+		t.Fatal("the table is not streamed")
+	}
+	if m := doc.Media("image1.gif"); m == nil || !bytes.Equal(m.Data, pic.Bytes()) {
+		t.Fatal("the media is not streamed")
+	}
+
+	// the parts must exist before the body is started
+	s, err = NewStreamWriter(io.Discard, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddParagraph().AddText("first")
+	s.AddParagraph().AddText("second")
+	s.Docx().SectPr().AddHeader(HEADER_FOOTER_DEFAULT)
+	if err = s.Close(); err == nil || !strings.Contains(err.Error(), "word/header1.xml") {
+		t.Fatalf("unexpected error %v for a late header", err)
+	}
+}
+
+func TestStreamReader(t *testing.T) {
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := New().WithDefaultTheme()
+	w.AddParagraph().AddText("title")
+	_, err = w.AddParagraph().AddInlineDrawing(pic.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().AddLink("link", "https://example.com/")
+	w.AddTable(2, 2, 9000).Rows[0].Cells[0].AddParagraph().AddText("cell")
+	w.WithA4Page()
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := OpenStream(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if m := s.Docx().Media("image1.gif"); m == nil || m.Data != nil {
+		t.Fatal("the media is not lazy")
+	}
+	var items []interface{}
+	for {
+		item, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+	}
+	if _, err = s.Next(); err != io.EOF {
+		t.Fatalf("unexpected error %v after the end", err)
+	}
+	if len(items) != len(want.Document.Body.Items) {
+		t.Fatalf("%d items read, %d expected", len(items), len(want.Document.Body.Items))
+	}
+	for i, item := range items {
+		got, ok := item.(fmt.Stringer)
+		if !ok {
+			continue
+		}
+		if exp := want.Document.Body.Items[i].(fmt.Stringer).String(); got.String() != exp {
+			t.Fatalf("item %d is %q, expected %q", i, got.String(), exp)
+		}
+	}
+	if _, ok := items[len(items)-1].(*SectPr); !ok {
+		t.Fatal("the section properties are not read")
+	}
+	link := items[2].(*Paragraph).Children[0].(*Hyperlink)
+	if target, err := s.Docx().ReferTarget(link.ID); err != nil || target != "https://example.com/" {
+		t.Fatalf("unexpected link target %q, %v", target, err)
+	}
+	if len(s.Docx().Document.Body.Items) != 0 {
+		t.Fatal("the items are kept in the body")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import "strconv"
+
+// Styles returns the styles part of the document, it is read
+// from the template on first use or created when there is none
+func (f *Docx) Styles() *Styles {
+	f = f.root()
+	if f.styles == nil {
+		f.styles = f.readStyles()
+		f.stylesRead = nil
+	}
+	return f.styles
+}
+
+// readStyles returns the styles without changing the way
+// the styles part is written (copied from the template)
+func (f *Docx) readStyles() *Styles {
+	f = f.root()
+	if f.styles != nil {
+		return f.styles
+	}
+	if f.stylesRead != nil {
+		return f.stylesRead
+	}
+	name := STYLES_PART
+	for _, r := range f.docRelation.Relationship {
+		if r.Type == REL_STYLES {
+			name = relTargetPart(WORD_FOLDER+"document.xml", r.Target)
+			break
+		}
+	}
+	f.stylesRead = &Styles{name: name}
+	if f.hasTemplateFile(name) {
+		_ = f.loadTemplatePart(name, f.stylesRead)
+	}
+	return f.stylesRead
+}
+
+// AddParagraphStyle adds a paragraph style based on the default paragraph style,
+// the style already using id is returned if any
+func (f *Docx) AddParagraphStyle(id, name string) *StyleDefinition {
+	st := f.Styles().add(STYLE_PARAGRAPH, id, name)
+	if st.ParagraphProperties == nil {
+		st.ParagraphProperties = &ParagraphProperties{}
+	}
+	if st.RunProperties == nil {
+		st.RunProperties = &RunProperties{}
+	}
+	return st
+}
+
+// AddCharacterStyle adds a character style based on the default character style,
+// the style already using id is returned if any
+func (f *Docx) AddCharacterStyle(id, name string) *StyleDefinition {
+	st := f.Styles().add(STYLE_CHARACTER, id, name)
+	if st.RunProperties == nil {
+		st.RunProperties = &RunProperties{}
+	}
+	return st
+}
+
+// AddTableStyle adds a table style based on the default table style,
+// the style already using id is returned if any
+func (f *Docx) AddTableStyle(id, name string) *StyleDefinition {
+	st := f.Styles().add(STYLE_TABLE, id, name)
+	if st.TableProperties == nil {
+		st.TableProperties = &WTableProperties{}
+	}
+	return st
+}
+
+// AddNumberingStyle adds a numbering style using the list definition of l,
+// the style already using id is returned if any
+func (f *Docx) AddNumberingStyle(id, name string, l *List) *StyleDefinition {
+	st := f.Styles().add(STYLE_NUMBERING, id, name)
+	st.QFormat = false
+	if st.ParagraphProperties == nil {
+		st.ParagraphProperties = &ParagraphProperties{}
+	}
+	if l != nil {
+		st.ParagraphProperties.NumProperties = &NumProperties{NumID: &NumID{Val: strconv.Itoa(l.num.ID)}}
+		if a := l.AbstractNum(); a != nil {
+			a.StyleLink = &NumberingVal{Val: id}
+		}
+	}
+	return st
+}
+
+// add appends a custom style of typ, or returns the style using id
+func (s *Styles) add(typ _styleType, id, name string) *StyleDefinition {
+	if st := s.Style(id); st != nil {
+		return st
+	}
+	st := &StyleDefinition{
+		Type:        typ,
+		StyleID:     id,
+		CustomStyle: true,
+		Name:        name,
+		QFormat:     true,
+	}
+	if def := s.Default(typ); def != nil && typ != STYLE_NUMBERING {
+		st.BasedOn = def.StyleID
+	}
+	s.Styles = append(s.Styles, st)
+	return st
+}
+
+// Style gets the style by its id (or nil on notfound)
+func (s *Styles) Style(id string) *StyleDefinition {
+	for _, st := range s.Styles {
+		if st.StyleID == id {
+			return st
+		}
+	}
+	return nil
+}
+
+// StyleByName gets the style by its name (or nil on notfound)
+func (s *Styles) StyleByName(name string) *StyleDefinition {
+	for _, st := range s.Styles {
+		if st.Name == name {
+			return st
+		}
+	}
+	return nil
+}
+
+// Default gets the default style of typ (or nil on notfound)
+func (s *Styles) Default(typ _styleType) *StyleDefinition {
+	for _, st := range s.Styles {
+		if st.Default && st.Type == typ {
+			return st
+		}
+	}
+	return nil
+}
+
+// Inheritance returns the style id followed by the styles it is based on,
+// from the style itself up to the root of the basedOn chain
+func (s *Styles) Inheritance(id string) []*StyleDefinition {
+	chain := make([]*StyleDefinition, 0, 4)
+	for st := s.Style(id); st != nil; st = s.Style(st.BasedOn) {
+		for _, c := range chain {
+			if c == st {
+				return chain // basedOn loop
+			}
+		}
+		chain = append(chain, st)
+		if st.BasedOn == "" {
+			break
+		}
+	}
+	return chain
+}
+
+// LinkedStyle gets the character style linked to a paragraph style
+// or the other way around (or nil on notfound)
+func (s *Styles) LinkedStyle(st *StyleDefinition) *StyleDefinition {
+	if st.Link == "" {
+		return nil
+	}
+	return s.Style(st.Link)
+}
+
+// NextStyle gets the style of the paragraph following a paragraph of st,
+// st itself when there is no next style
+func (s *Styles) NextStyle(st *StyleDefinition) *StyleDefinition {
+	if n := s.Style(st.Next); n != nil {
+		return n
+	}
+	return st
+}
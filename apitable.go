@@ -34,6 +34,13 @@ func (f *Docx) AddTable(
 	col int,
 	tableWidth int,
 ) *Table {
+	tbl := f.newTable(row, col, tableWidth)
+	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
+	return tbl
+}
+
+// newTable creates a new table by col*row
+func (f *Docx) newTable(row, col, tableWidth int) *Table {
 	tbl := &Table{
 		Properties: &WTableProperties{
 			Look: &WTableLook{
@@ -65,7 +72,6 @@ func (f *Docx) AddTable(
 
 	tbl.Style("TableGrid", 0)
 
-	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
 	return tbl
 }
 
@@ -222,47 +228,66 @@ var v_TABLE_STYLE_OPT []t_TABLE_STYLE_OPTION = []t_TABLE_STYLE_OPTION{
 	TABLE_STYLE_OPTION_LAST_COLUMN, TABLE_STYLE_OPTION_HORIZONTAL_BAND, TABLE_STYLE_OPTION_VERTICAL_BAND,
 }
 
-func (t *Table) setConfStyle() {
+// tableConfStyles are the conditional styles written in a table,
+// a style is nil when the look of the table does not use it
+type tableConfStyles struct {
+	firstRow *WTableConfStyle
+	firstCol *WTableConfStyle
+	lastRow  *WTableConfStyle
+	lastCol  *WTableConfStyle
+	oddHBand *WTableConfStyle
+	oddVBand *WTableConfStyle
+	none     *WTableConfStyle
+}
+
+// confStyles returns the conditional styles of the current look of the table,
+// nil if it has no look
+func (t *Table) confStyles() *tableConfStyles {
+	if t.Properties == nil || t.Properties.Look == nil {
+		return nil
+	}
 	g := t.Properties.Look
+	cs := new(tableConfStyles)
 	if g.FirstRow == 1 {
-		t.confStyle.firstRow = &WTableConfStyle{
+		cs.firstRow = &WTableConfStyle{
 			Val:      "100000000000",
 			FirstRow: 1,
 		}
 	}
 	if g.LastRow == 1 {
-		t.confStyle.lastRow = &WTableConfStyle{
+		cs.lastRow = &WTableConfStyle{
 			Val:     "010000000000",
 			LastRow: 1,
 		}
 	}
 	if g.FirstCol == 1 {
-		t.confStyle.firstCol = &WTableConfStyle{
+		cs.firstCol = &WTableConfStyle{
 			Val:      "001000000000",
 			FirstCol: 1,
 		}
 	}
 	if g.LastCol == 1 {
-		t.confStyle.lastCol = &WTableConfStyle{
+		cs.lastCol = &WTableConfStyle{
 			Val:     "000100000000",
 			LastCol: 1,
 		}
 	}
 	if g.NoHBand == 0 {
-		t.confStyle.oddHBand = &WTableConfStyle{
+		cs.oddHBand = &WTableConfStyle{
 			Val:      "000000100000",
 			OddHBand: 1,
 		}
 	}
 	if g.NoVBand == 0 {
-		t.confStyle.oddVBand = &WTableConfStyle{
+		cs.oddVBand = &WTableConfStyle{
 			Val:      "000010000000",
 			OddVBand: 1,
 		}
 	}
-	t.confStyle.none = &WTableConfStyle{
+	cs.none = &WTableConfStyle{
 		Val: "000000000000",
 	}
+	return cs
 }
 
 // Style allows to set table table style
@@ -294,7 +319,6 @@ func (t *Table) Style(style string, option t_TABLE_STYLE_OPTION) *Table {
 		}
 	}
 
-	t.setConfStyle()
 	return t
 }
 
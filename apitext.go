@@ -30,9 +30,10 @@ func (p *Paragraph) AddTab() *Run {
 	run := &Run{
 		RunProperties: &RunProperties{},
 		Children:      c,
+		file:          p.file,
 	}
 
-	p.Children = append(p.Children, run)
+	p.appendRun(run)
 
 	return run
 }
@@ -61,6 +62,7 @@ func (p *Paragraph) AddText(text string) *Run {
 	run := &Run{
 		RunProperties: &RunProperties{},
 		Children:      c,
+		file:          p.file,
 	}
 	if p.Properties != nil && p.Properties.RunProperties != nil {
 		if p.Properties.RunProperties.Lang != nil {
@@ -72,6 +74,6 @@ func (p *Paragraph) AddText(text string) *Run {
 			run.RunProperties.NoProof = &tmp
 		}
 	}
-	p.Children = append(p.Children, run)
+	p.appendRun(run)
 	return run
 }
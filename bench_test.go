@@ -0,0 +1,138 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// benchDocument returns a document like the generated reports:
+// paragraphs of formatted runs, a table, a picture and a header
+func benchDocument(tb testing.TB) []byte {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("report")
+	_, err := w.AddParagraph().AddInlineDrawingFrom("testdata/fumiama.JPG")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	for i := 0; i < 500; i++ {
+		p := w.AddParagraph()
+		p.AddText("paragraph " + strconv.Itoa(i) + " ").Size("22")
+		p.AddText("bold").Size("22").Bold()
+		p.AddText(" and ").Size("22")
+		p.AddText("italic").Size("22").Italic().Color("ff0000")
+		p.AddLink("link", "https://example.com/"+strconv.Itoa(i))
+	}
+	tbl := w.AddTable(100, 5, 9000).Style("TableGrid", TABLE_STYLE_OPTION_FIRST_ROW|TABLE_STYLE_OPTION_HORIZONTAL_BAND)
+	for i, r := range tbl.Rows {
+		for j, c := range r.Cells {
+			c.AddParagraph().AddText(fmt.Sprintf("%d,%d", i, j))
+		}
+	}
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTypeName(t *testing.T) {
+	for _, item := range []interface{}{
+		&Paragraph{}, &Table{}, &SectPr{}, &rawElement{}, &Hyperlink{}, &Run{}, &RunProperties{},
+		&Revision{}, &MoveRangeStart{}, &MoveRangeEnd{}, &CommentRangeStart{}, &CommentRangeEnd{},
+		&Text{}, &DelText{}, &Drawing{}, &Tab{}, &BarterRabbet{}, &FootnoteReference{},
+		&EndnoteReference{}, &CommentReference{}, &Separator{},
+	} {
+		if name := typeName(item); name != fmt.Sprintf("%T", item) {
+			t.Fatalf("type name %s of %T", name, item)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := benchDocument(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Parse(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	data := benchDocument(b)
+	doc, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_, err = doc.WriteTo(&buf)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMergeSamePropRuns(b *testing.B) {
+	data := benchDocument(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, err := Parse(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		paragraphs := paragraphsOf(doc.Document.Body.Items)
+		b.StartTimer()
+		for _, p := range paragraphs {
+			p.MergeText(MergeSamePropRuns)
+			p.MergeText(MergeSamePropRunsOf("Size", "Bold"))
+		}
+	}
+}
+
+func BenchmarkKeepAndDrop(b *testing.B) {
+	data := benchDocument(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, err := Parse(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		doc.Document.Body.DropDrawingOf("NilPicture")
+		doc.Document.Body.DropDrawingOf("ShapeAndCanvasAndGroup")
+		for _, p := range paragraphsOf(doc.Document.Body.Items) {
+			p.KeepElements("*docx.Run", "*docx.Hyperlink")
+		}
+		doc.Document.Body.KeepElements("*docx.Paragraph", "*docx.Table")
+	}
+}
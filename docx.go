@@ -24,11 +24,13 @@ package docx
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"encoding/xml"
 	"io"
 	"io/fs"
 	"os"
 	"sync"
+	"time"
 )
 
 // Docx is the structure that allow to access the internal represntation
@@ -42,15 +44,48 @@ type Docx struct {
 	mediaNameIdx map[string]int
 
 	rID       uintptr
-	imageID   uintptr
-	docID     uintptr
 	slowIDs   map[string]uintptr
 	slowIDsMu sync.Mutex
+	ids       map[string]int // ids are the last ids allocated by NewID, nil until scanned
+	// idCopies maps the ids copied from other documents to their new id
+	idCopies map[idCopy]int
+	// origin is the document this one is derived from, they share their ids
+	origin *Docx
 
 	template string
 	tmplfs   fs.FS
 	tmpfslst []string
 
+	parseOpts *ParseOptions // parseOpts are the options the document is parsed with
+	warnings  []*ParseError // warnings are the problems skipped in lenient mode
+	parsing   *parsing      // parsing is the part being parsed
+
+	parts    map[string][]byte         // parts are the parts set by SetPart
+	partRels map[string]*Relationships // partRels are the relationships of the other parts by rels name
+
+	headers      []*Header
+	footers      []*Footer
+	footnotes    *Notes
+	endnotes     *Notes
+	comments     *Comments
+	settings     *Settings
+	numbering    *Numbering
+	styles       *Styles
+	stylesRead   *Styles // stylesRead are the styles loaded but not written yet
+	contentTypes *ContentTypes
+
+	tracking *revisionTracking // tracking is set while the changes are tracked
+
+	// commentCopies maps the comments copied from other documents to their copy
+	commentCopies map[*Comment]*Comment
+	// numCopies maps the numbering instances and the list definitions
+	// copied from other documents to their new id
+	numCopies map[interface{}]int
+
+	// parent is set when this Docx only holds the relationships of
+	// one part (e.g. a header) of the parent document
+	parent *Docx
+
 	io.Reader
 	io.WriterTo
 }
@@ -91,11 +126,47 @@ func New() *Docx {
 //		docxlib.Parse(file, handler.Size)
 //	}
 func Parse(reader io.ReaderAt, size int64) (doc *Docx, err error) {
+	return ParseWithOptions(reader, size, nil)
+}
+
+// ParseOptions are the options of ParseWithOptions
+type ParseOptions struct {
+	// MaxPartSize is the greatest uncompressed size of a part, no limit when 0
+	MaxPartSize int64
+	// MaxTotalSize is the greatest uncompressed size of all the parts, no limit when 0
+	MaxTotalSize int64
+	// MaxParts is the greatest number of parts, no limit when 0
+	MaxParts int
+	// LazyMedia keeps the media in the zip file until they are used
+	LazyMedia bool
+	// Strict fails on the unexpected elements instead of skipping them
+	Strict bool
+	// Lenient records the problems which can be skipped as warnings (see Docx.Warnings),
+	// the parts other than the document which cannot be parsed are kept as they are
+	Lenient bool
+
+	stream bool // stream leaves the body to a StreamReader
+}
+
+// ParseWithOptions generates a new docx file in memory from a reader
+// like Parse does, the default options are used if opts is nil.
+//
+// The sizes are the uncompressed sizes declared in the zip file,
+// they are checked before reading the parts and the reads
+// cannot go beyond them.
+func ParseWithOptions(reader io.ReaderAt, size int64, opts *ParseOptions) (doc *Docx, err error) {
+	if opts == nil {
+		opts = &ParseOptions{}
+	}
 	zipReader, err := zip.NewReader(reader, size)
 	if err != nil {
 		return nil, err
 	}
-	doc, err = unpack(zipReader)
+	err = checkLimits(zipReader, opts)
+	if err != nil {
+		return nil, err
+	}
+	doc, err = unpack(zipReader, opts)
 	return
 }
 
@@ -148,12 +219,100 @@ func LoadBodyItems(items []interface{}, media []Media) *Docx {
 	return doc
 }
 
+// WriteOptions are the options of WriteToWithOptions
+type WriteOptions struct {
+	// CompressionLevel is the flate level of the parts from 1 to 9,
+	// 0 is flate.DefaultCompression and a negative level stores them uncompressed.
+	// The unchanged parts of a parsed document compressed the same way
+	// are copied as they are, but when a level from 1 to 9 is given
+	CompressionLevel int
+	// ModTime is the modification time of the parts, 1980-01-01 00:00:00 UTC when zero
+	ModTime time.Time
+}
+
 // WriteTo allows to save a docx to a writer
-func (f *Docx) WriteTo(writer io.Writer) (_ int64, err error) {
-	zipWriter := zip.NewWriter(writer)
-	defer zipWriter.Close()
+//
+// The parts are always written in the same order with the same time,
+// so that the same document gives the same bytes
+func (f *Docx) WriteTo(writer io.Writer) (int64, error) {
+	return f.WriteToWithOptions(writer, nil)
+}
 
-	return 0, f.pack(zipWriter)
+// WriteToWithOptions saves a docx to a writer with the options opts,
+// the default options are used if opts is nil
+func (f *Docx) WriteToWithOptions(writer io.Writer, opts *WriteOptions) (n int64, err error) {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	cw := &countWriter{w: writer}
+	zipWriter := opts.newZipWriter(cw)
+	err = f.pack(zipWriter, opts)
+	if err != nil {
+		zipWriter.Close()
+		return cw.n, err
+	}
+	err = zipWriter.Close()
+	return cw.n, err
+}
+
+// newZipWriter returns a zip writer compressing with the level of the options
+func (opts *WriteOptions) newZipWriter(w io.Writer) *zip.Writer {
+	zipWriter := zip.NewWriter(w)
+	if opts.CompressionLevel > 0 {
+		level := opts.CompressionLevel
+		zipWriter.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		})
+	}
+	return zipWriter
+}
+
+// method returns the compression method of the parts
+func (opts *WriteOptions) method() uint16 {
+	if opts.CompressionLevel < 0 {
+		return zip.Store
+	}
+	return zip.Deflate
+}
+
+// modTime returns the modification time of the parts
+func (opts *WriteOptions) modTime() time.Time {
+	if opts.ModTime.IsZero() {
+		return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return opts.ModTime
+}
+
+// countWriter counts the bytes written to w
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write ...
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// root returns the Docx owning the package state (media, ids, ...)
+func (f *Docx) root() *Docx {
+	if f.parent != nil {
+		return f.parent
+	}
+	return f
+}
+
+// newPart returns a Docx sharing the package state of f
+// but owning its own relationships
+func (f *Docx) newPart() *Docx {
+	return &Docx{
+		docRelation: Relationships{
+			Xmlns: XMLNS_REL,
+		},
+		parent: f.root(),
+	}
 }
 
 // ReadDocument allow to load a document as a template to append
@@ -0,0 +1,663 @@
+// Code generated by gen_equal.go; DO NOT EDIT.
+
+package docx
+
+import "fmt"
+
+// equal tells whether a and b are the same RunProperties but their XMLName
+func (a *RunProperties) equal(b *RunProperties) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Ins.equal(b.Ins) &&
+		a.Del.equal(b.Del) &&
+		a.Fonts.equal(b.Fonts) &&
+		a.Bold.equal(b.Bold) &&
+		(a.ICs == nil) == (b.ICs == nil) &&
+		a.Italic.equal(b.Italic) &&
+		a.Highlight.equal(b.Highlight) &&
+		a.Color.equal(b.Color) &&
+		a.Size.equal(b.Size) &&
+		a.SizeCs.equal(b.SizeCs) &&
+		a.Spacing.equal(b.Spacing) &&
+		a.RunStyle.equal(b.RunStyle) &&
+		a.Style.equal(b.Style) &&
+		a.Shade.equal(b.Shade) &&
+		a.Kern.equal(b.Kern) &&
+		a.Underline.equal(b.Underline) &&
+		a.VertAlign.equal(b.VertAlign) &&
+		a.Strike.equal(b.Strike) &&
+		a.Lang.equal(b.Lang) &&
+		a.NoProof.equal(b.NoProof) &&
+		a.Change.equal(b.Change) &&
+		len(a.items) == 0 && len(b.items) == 0
+}
+
+// runPropertiesFields compares the exported fields of two RunProperties by name
+var runPropertiesFields = map[string]func(a, b *RunProperties) bool{
+	"Bold":      func(a, b *RunProperties) bool { return a.Bold.equal(b.Bold) },
+	"Change":    func(a, b *RunProperties) bool { return a.Change.equal(b.Change) },
+	"Color":     func(a, b *RunProperties) bool { return a.Color.equal(b.Color) },
+	"Del":       func(a, b *RunProperties) bool { return a.Del.equal(b.Del) },
+	"Fonts":     func(a, b *RunProperties) bool { return a.Fonts.equal(b.Fonts) },
+	"Highlight": func(a, b *RunProperties) bool { return a.Highlight.equal(b.Highlight) },
+	"ICs":       func(a, b *RunProperties) bool { return (a.ICs == nil) == (b.ICs == nil) },
+	"Ins":       func(a, b *RunProperties) bool { return a.Ins.equal(b.Ins) },
+	"Italic":    func(a, b *RunProperties) bool { return a.Italic.equal(b.Italic) },
+	"Kern":      func(a, b *RunProperties) bool { return a.Kern.equal(b.Kern) },
+	"Lang":      func(a, b *RunProperties) bool { return a.Lang.equal(b.Lang) },
+	"NoProof":   func(a, b *RunProperties) bool { return a.NoProof.equal(b.NoProof) },
+	"RunStyle":  func(a, b *RunProperties) bool { return a.RunStyle.equal(b.RunStyle) },
+	"Shade":     func(a, b *RunProperties) bool { return a.Shade.equal(b.Shade) },
+	"Size":      func(a, b *RunProperties) bool { return a.Size.equal(b.Size) },
+	"SizeCs":    func(a, b *RunProperties) bool { return a.SizeCs.equal(b.SizeCs) },
+	"Spacing":   func(a, b *RunProperties) bool { return a.Spacing.equal(b.Spacing) },
+	"Strike":    func(a, b *RunProperties) bool { return a.Strike.equal(b.Strike) },
+	"Style":     func(a, b *RunProperties) bool { return a.Style.equal(b.Style) },
+	"Underline": func(a, b *RunProperties) bool { return a.Underline.equal(b.Underline) },
+	"VertAlign": func(a, b *RunProperties) bool { return a.VertAlign.equal(b.VertAlign) },
+}
+
+// equal tells whether a and b are the same RevisionMark but their XMLName
+func (a *RevisionMark) equal(b *RevisionMark) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID &&
+		a.Author == b.Author &&
+		a.Date == b.Date
+}
+
+// equal tells whether a and b are the same RPrChange but their XMLName
+func (a *RPrChange) equal(b *RPrChange) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID &&
+		a.Author == b.Author &&
+		a.Date == b.Date &&
+		a.RunProperties.equal(b.RunProperties)
+}
+
+// equal tells whether a and b are the same RunFonts but their XMLName
+func (a *RunFonts) equal(b *RunFonts) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ASCII == b.ASCII &&
+		a.EastAsia == b.EastAsia &&
+		a.HAnsi == b.HAnsi &&
+		a.Hint == b.Hint &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same Bold but their XMLName
+func (a *Bold) equal(b *Bold) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Italic but their XMLName
+func (a *Italic) equal(b *Italic) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Highlight but their XMLName
+func (a *Highlight) equal(b *Highlight) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Color but their XMLName
+func (a *Color) equal(b *Color) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same Size but their XMLName
+func (a *Size) equal(b *Size) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same SizeCs but their XMLName
+func (a *SizeCs) equal(b *SizeCs) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Spacing but their XMLName
+func (a *Spacing) equal(b *Spacing) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val &&
+		a.BeforeLines == b.BeforeLines &&
+		a.Before == b.Before &&
+		a.Line == b.Line &&
+		a.LineRule == b.LineRule &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same RunStyle but their XMLName
+func (a *RunStyle) equal(b *RunStyle) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Style but their XMLName
+func (a *Style) equal(b *Style) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Shade but their XMLName
+func (a *Shade) equal(b *Shade) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val &&
+		a.Color == b.Color &&
+		a.Fill == b.Fill &&
+		a.ThemeFill == b.ThemeFill &&
+		a.ThemeFillTint == b.ThemeFillTint &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same Kern but their XMLName
+func (a *Kern) equal(b *Kern) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Underline but their XMLName
+func (a *Underline) equal(b *Underline) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same VertAlign but their XMLName
+func (a *VertAlign) equal(b *VertAlign) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Strike but their XMLName
+func (a *Strike) equal(b *Strike) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val
+}
+
+// equal tells whether a and b are the same Lang but their XMLName
+func (a *Lang) equal(b *Lang) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Val == b.Val &&
+		equalSlice(len(a.attrs), len(b.attrs), func(i int) bool { return a.attrs[i] == b.attrs[i] })
+}
+
+// equal tells whether a and b are the same NoProof but their XMLName
+func (a *NoProof) equal(b *NoProof) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return true
+}
+
+// typeName returns the name of the type of item as printed by %T,
+// without reflection for the pointers to the structs of the package
+func typeName(item interface{}) string {
+	switch item.(type) {
+	case *AAlphaModFix:
+		return "*docx.AAlphaModFix"
+	case *ABlip:
+		return "*docx.ABlip"
+	case *ABlipFill:
+		return "*docx.ABlipFill"
+	case *AExt:
+		return "*docx.AExt"
+	case *AFillRect:
+		return "*docx.AFillRect"
+	case *AGraphic:
+		return "*docx.AGraphic"
+	case *AGraphicData:
+		return "*docx.AGraphicData"
+	case *AGraphicFrameLocks:
+		return "*docx.AGraphicFrameLocks"
+	case *AGroupShapeLocks:
+		return "*docx.AGroupShapeLocks"
+	case *AHeadEnd:
+		return "*docx.AHeadEnd"
+	case *ALine:
+		return "*docx.ALine"
+	case *AMiter:
+		return "*docx.AMiter"
+	case *AOff:
+		return "*docx.AOff"
+	case *APicLocks:
+		return "*docx.APicLocks"
+	case *APrstDash:
+		return "*docx.APrstDash"
+	case *APrstGeom:
+		return "*docx.APrstGeom"
+	case *ASPLocks:
+		return "*docx.ASPLocks"
+	case *ASolidFill:
+		return "*docx.ASolidFill"
+	case *ASrcRect:
+		return "*docx.ASrcRect"
+	case *ASrgbClr:
+		return "*docx.ASrgbClr"
+	case *AStretch:
+		return "*docx.AStretch"
+	case *ATailEnd:
+		return "*docx.ATailEnd"
+	case *ATile:
+		return "*docx.ATile"
+	case *AXfrm:
+		return "*docx.AXfrm"
+	case *AbstractNum:
+		return "*docx.AbstractNum"
+	case *AdjustRightInd:
+		return "*docx.AdjustRightInd"
+	case *AnnotationRef:
+		return "*docx.AnnotationRef"
+	case *BarterRabbet:
+		return "*docx.BarterRabbet"
+	case *Body:
+		return "*docx.Body"
+	case *Bold:
+		return "*docx.Bold"
+	case *Change:
+		return "*docx.Change"
+	case *Color:
+		return "*docx.Color"
+	case *Cols:
+		return "*docx.Cols"
+	case *Comment:
+		return "*docx.Comment"
+	case *CommentEx:
+		return "*docx.CommentEx"
+	case *CommentRangeEnd:
+		return "*docx.CommentRangeEnd"
+	case *CommentRangeStart:
+		return "*docx.CommentRangeStart"
+	case *CommentReference:
+		return "*docx.CommentReference"
+	case *Comments:
+		return "*docx.Comments"
+	case *CommentsEx:
+		return "*docx.CommentsEx"
+	case *CompareOptions:
+		return "*docx.CompareOptions"
+	case *ContentTypeDefault:
+		return "*docx.ContentTypeDefault"
+	case *ContentTypeOverride:
+		return "*docx.ContentTypeOverride"
+	case *ContentTypes:
+		return "*docx.ContentTypes"
+	case *ContinuationSeparator:
+		return "*docx.ContinuationSeparator"
+	case *DelText:
+		return "*docx.DelText"
+	case *DocDefaults:
+		return "*docx.DocDefaults"
+	case *DocGrid:
+		return "*docx.DocGrid"
+	case *Document:
+		return "*docx.Document"
+	case *Docx:
+		return "*docx.Docx"
+	case *Drawing:
+		return "*docx.Drawing"
+	case *EndnoteRef:
+		return "*docx.EndnoteRef"
+	case *EndnoteReference:
+		return "*docx.EndnoteReference"
+	case *Footer:
+		return "*docx.Footer"
+	case *FooterReference:
+		return "*docx.FooterReference"
+	case *FootnoteRef:
+		return "*docx.FootnoteRef"
+	case *FootnoteReference:
+		return "*docx.FootnoteReference"
+	case *Formatting:
+		return "*docx.Formatting"
+	case *Fragment:
+		return "*docx.Fragment"
+	case *Header:
+		return "*docx.Header"
+	case *HeaderReference:
+		return "*docx.HeaderReference"
+	case *Highlight:
+		return "*docx.Highlight"
+	case *Hyperlink:
+		return "*docx.Hyperlink"
+	case *Ilevel:
+		return "*docx.Ilevel"
+	case *Ind:
+		return "*docx.Ind"
+	case *Italic:
+		return "*docx.Italic"
+	case *Justification:
+		return "*docx.Justification"
+	case *KeepLines:
+		return "*docx.KeepLines"
+	case *KeepNext:
+		return "*docx.KeepNext"
+	case *Kern:
+		return "*docx.Kern"
+	case *Kinsoku:
+		return "*docx.Kinsoku"
+	case *Lang:
+		return "*docx.Lang"
+	case *LatentStyles:
+		return "*docx.LatentStyles"
+	case *Level:
+		return "*docx.Level"
+	case *List:
+		return "*docx.List"
+	case *LsdException:
+		return "*docx.LsdException"
+	case *LvlOverride:
+		return "*docx.LvlOverride"
+	case *Media:
+		return "*docx.Media"
+	case *MoveRangeEnd:
+		return "*docx.MoveRangeEnd"
+	case *MoveRangeStart:
+		return "*docx.MoveRangeStart"
+	case *NoProof:
+		return "*docx.NoProof"
+	case *NonVisualProperties:
+		return "*docx.NonVisualProperties"
+	case *Note:
+		return "*docx.Note"
+	case *Notes:
+		return "*docx.Notes"
+	case *Num:
+		return "*docx.Num"
+	case *NumID:
+		return "*docx.NumID"
+	case *NumProperties:
+		return "*docx.NumProperties"
+	case *Numbering:
+		return "*docx.Numbering"
+	case *NumberingVal:
+		return "*docx.NumberingVal"
+	case *OverflowPunct:
+		return "*docx.OverflowPunct"
+	case *PICBlipFill:
+		return "*docx.PICBlipFill"
+	case *PICNonVisualPicProperties:
+		return "*docx.PICNonVisualPicProperties"
+	case *PICSpPr:
+		return "*docx.PICSpPr"
+	case *PPrChange:
+		return "*docx.PPrChange"
+	case *PageBreakBefore:
+		return "*docx.PageBreakBefore"
+	case *Paragraph:
+		return "*docx.Paragraph"
+	case *ParagraphProperties:
+		return "*docx.ParagraphProperties"
+	case *ParseError:
+		return "*docx.ParseError"
+	case *ParseOptions:
+		return "*docx.ParseOptions"
+	case *PartRelationship:
+		return "*docx.PartRelationship"
+	case *PgMar:
+		return "*docx.PgMar"
+	case *PgSz:
+		return "*docx.PgSz"
+	case *PicCNvPicPr:
+		return "*docx.PicCNvPicPr"
+	case *Picture:
+		return "*docx.Picture"
+	case *RPrChange:
+		return "*docx.RPrChange"
+	case *Relationship:
+		return "*docx.Relationship"
+	case *Relationships:
+		return "*docx.Relationships"
+	case *Revision:
+		return "*docx.Revision"
+	case *RevisionMark:
+		return "*docx.RevisionMark"
+	case *Run:
+		return "*docx.Run"
+	case *RunFonts:
+		return "*docx.RunFonts"
+	case *RunProperties:
+		return "*docx.RunProperties"
+	case *RunStyle:
+		return "*docx.RunStyle"
+	case *SectPr:
+		return "*docx.SectPr"
+	case *Separator:
+		return "*docx.Separator"
+	case *Settings:
+		return "*docx.Settings"
+	case *Shade:
+		return "*docx.Shade"
+	case *ShapeProperties:
+		return "*docx.ShapeProperties"
+	case *Size:
+		return "*docx.Size"
+	case *SizeCs:
+		return "*docx.SizeCs"
+	case *SnapToGrid:
+		return "*docx.SnapToGrid"
+	case *Spacing:
+		return "*docx.Spacing"
+	case *StreamReader:
+		return "*docx.StreamReader"
+	case *StreamWriter:
+		return "*docx.StreamWriter"
+	case *Strike:
+		return "*docx.Strike"
+	case *Style:
+		return "*docx.Style"
+	case *StyleDefinition:
+		return "*docx.StyleDefinition"
+	case *Styles:
+		return "*docx.Styles"
+	case *SuppressAutoHyphens:
+		return "*docx.SuppressAutoHyphens"
+	case *Tab:
+		return "*docx.Tab"
+	case *Table:
+		return "*docx.Table"
+	case *TableStyleProperties:
+		return "*docx.TableStyleProperties"
+	case *Tabs:
+		return "*docx.Tabs"
+	case *Text:
+		return "*docx.Text"
+	case *TextAlignment:
+		return "*docx.TextAlignment"
+	case *TitlePg:
+		return "*docx.TitlePg"
+	case *Underline:
+		return "*docx.Underline"
+	case *VertAlign:
+		return "*docx.VertAlign"
+	case *WGridCol:
+		return "*docx.WGridCol"
+	case *WGridSpan:
+		return "*docx.WGridSpan"
+	case *WPAnchor:
+		return "*docx.WPAnchor"
+	case *WPCBackground:
+		return "*docx.WPCBackground"
+	case *WPCNvGraphicFramePr:
+		return "*docx.WPCNvGraphicFramePr"
+	case *WPCWhole:
+		return "*docx.WPCWhole"
+	case *WPDocPr:
+		return "*docx.WPDocPr"
+	case *WPEffectExtent:
+		return "*docx.WPEffectExtent"
+	case *WPExtent:
+		return "*docx.WPExtent"
+	case *WPGGroupShape:
+		return "*docx.WPGGroupShape"
+	case *WPGcNvGrpSpPr:
+		return "*docx.WPGcNvGrpSpPr"
+	case *WPInline:
+		return "*docx.WPInline"
+	case *WPPositionH:
+		return "*docx.WPPositionH"
+	case *WPPositionV:
+		return "*docx.WPPositionV"
+	case *WPSBodyPr:
+		return "*docx.WPSBodyPr"
+	case *WPSCNvCnPr:
+		return "*docx.WPSCNvCnPr"
+	case *WPSCNvSpPr:
+		return "*docx.WPSCNvSpPr"
+	case *WPSTextBox:
+		return "*docx.WPSTextBox"
+	case *WPSimplePos:
+		return "*docx.WPSimplePos"
+	case *WPWrapSquare:
+		return "*docx.WPWrapSquare"
+	case *WTableBorder:
+		return "*docx.WTableBorder"
+	case *WTableBorders:
+		return "*docx.WTableBorders"
+	case *WTableCell:
+		return "*docx.WTableCell"
+	case *WTableCellBorders:
+		return "*docx.WTableCellBorders"
+	case *WTableCellProperties:
+		return "*docx.WTableCellProperties"
+	case *WTableCellWidth:
+		return "*docx.WTableCellWidth"
+	case *WTableConfStyle:
+		return "*docx.WTableConfStyle"
+	case *WTableGrid:
+		return "*docx.WTableGrid"
+	case *WTableLook:
+		return "*docx.WTableLook"
+	case *WTablePositioningProperties:
+		return "*docx.WTablePositioningProperties"
+	case *WTableProperties:
+		return "*docx.WTableProperties"
+	case *WTableRow:
+		return "*docx.WTableRow"
+	case *WTableRowHeight:
+		return "*docx.WTableRowHeight"
+	case *WTableRowProperties:
+		return "*docx.WTableRowProperties"
+	case *WTableStyle:
+		return "*docx.WTableStyle"
+	case *WTableWidth:
+		return "*docx.WTableWidth"
+	case *WTextBoxContent:
+		return "*docx.WTextBoxContent"
+	case *WVerticalAlignment:
+		return "*docx.WVerticalAlignment"
+	case *WordChange:
+		return "*docx.WordChange"
+	case *WordprocessingCanvas:
+		return "*docx.WordprocessingCanvas"
+	case *WordprocessingGroup:
+		return "*docx.WordprocessingGroup"
+	case *WordprocessingShape:
+		return "*docx.WordprocessingShape"
+	case *WriteOptions:
+		return "*docx.WriteOptions"
+	case *WvMerge:
+		return "*docx.WvMerge"
+	case *cellPlace:
+		return "*docx.cellPlace"
+	case *comparer:
+		return "*docx.comparer"
+	case *countWriter:
+		return "*docx.countWriter"
+	case *diffOp:
+		return "*docx.diffOp"
+	case *diffToken:
+		return "*docx.diffToken"
+	case *formatResolver:
+		return "*docx.formatResolver"
+	case *idCopy:
+		return "*docx.idCopy"
+	case *keptItem:
+		return "*docx.keptItem"
+	case *listCounters:
+		return "*docx.listCounters"
+	case *marshaller:
+		return "*docx.marshaller"
+	case *numberingEvaluator:
+		return "*docx.numberingEvaluator"
+	case *orderedChild:
+		return "*docx.orderedChild"
+	case *packing:
+		return "*docx.packing"
+	case *paraWithID:
+		return "*docx.paraWithID"
+	case *paragraphBuilder:
+		return "*docx.paragraphBuilder"
+	case *parsing:
+		return "*docx.parsing"
+	case *partDir:
+		return "*docx.partDir"
+	case *partEntry:
+		return "*docx.partEntry"
+	case *partFile:
+		return "*docx.partFile"
+	case *partInfo:
+		return "*docx.partInfo"
+	case *partRelationships:
+		return "*docx.partRelationships"
+	case *rawElement:
+		return "*docx.rawElement"
+	case *revisionResolver:
+		return "*docx.revisionResolver"
+	case *revisionTracking:
+		return "*docx.revisionTracking"
+	case *slice:
+		return "*docx.slice"
+	case *tableConfStyles:
+		return "*docx.tableConfStyles"
+	case *templatePart:
+		return "*docx.templatePart"
+	case *toggle:
+		return "*docx.toggle"
+	case *tokenReader:
+		return "*docx.tokenReader"
+	}
+	return fmt.Sprintf("%T", item)
+}
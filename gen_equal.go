@@ -0,0 +1,214 @@
+//go:build ignore
+
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// gen_equal writes equal.go, the equal methods of the types given
+// as arguments, comparing them field by field but their XMLName:
+//
+//	go run gen_equal.go -fields RunProperties RunProperties RunFonts...
+//
+// The pointers to the generated types are compared by their equal method,
+// the pointers to empty structs by their presence, the slices of values item
+// by item and the slices of pointers, which are elements kept as read, are
+// only equal when both are empty. The types of -fields also get a map of the
+// comparisons of each field by name.
+//
+// It also writes typeName, naming the pointers to all the structs of the
+// package as %T does, without reflection.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	fields := flag.String("fields", "", "comma separated types getting a map of their field comparisons")
+	out := flag.String("o", "equal.go", "output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		if strings.HasSuffix(fi.Name(), "_test.go") {
+			return false
+		}
+		match, err := build.Default.MatchFile(".", fi.Name())
+		return err == nil && match
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	structs := make(map[string]*ast.StructType, 512)
+	for _, pkg := range pkgs {
+		if pkg.Name != "docx" {
+			continue
+		}
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						structs[ts.Name.Name] = st
+					}
+				}
+			}
+		}
+	}
+
+	types := flag.Args()
+	generated := make(map[string]bool, len(types))
+	for _, name := range types {
+		if structs[name] == nil {
+			log.Fatalf("no struct %s", name)
+		}
+		generated[name] = true
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_equal.go; DO NOT EDIT.\n\npackage docx\n\nimport \"fmt\"\n\n")
+	for _, name := range types {
+		conds := make([]string, 0, 16)
+		byName := make(map[string]string, 16)
+		for _, field := range structs[name].Fields.List {
+			for _, id := range field.Names {
+				// the document being parsed is not part of the value
+				if id.Name == "XMLName" || id.Name == "_" || id.Name == "file" {
+					continue
+				}
+				cond, err := compare(field.Type, "a."+id.Name, "b."+id.Name, generated)
+				if err != nil {
+					log.Fatalf("%s.%s: %v", name, id.Name, err)
+				}
+				conds = append(conds, cond)
+				if ast.IsExported(id.Name) {
+					byName[id.Name] = cond
+				}
+			}
+		}
+		fmt.Fprintf(&buf, "// equal tells whether a and b are the same %s but their XMLName\n", name)
+		fmt.Fprintf(&buf, "func (a *%s) equal(b *%s) bool {\n", name, name)
+		buf.WriteString("\tif a == nil || b == nil {\n\t\treturn a == b\n\t}\n")
+		if len(conds) == 0 {
+			buf.WriteString("\treturn true\n}\n\n")
+			continue
+		}
+		fmt.Fprintf(&buf, "\treturn %s\n}\n\n", strings.Join(conds, " &&\n\t\t"))
+
+		if !strings.Contains(","+*fields+",", ","+name+",") {
+			continue
+		}
+		names := make([]string, 0, len(byName))
+		for n := range byName {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		v := strings.ToLower(name[:1]) + name[1:] + "Fields"
+		fmt.Fprintf(&buf, "// %s compares the exported fields of two %s by name\n", v, name)
+		fmt.Fprintf(&buf, "var %s = map[string]func(a, b *%s) bool{\n", v, name)
+		for _, n := range names {
+			fmt.Fprintf(&buf, "\t%q: func(a, b *%s) bool { return %s },\n", n, name, byName[n])
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	all := make([]string, 0, len(structs))
+	for name := range structs {
+		all = append(all, name)
+	}
+	sort.Strings(all)
+	buf.WriteString("// typeName returns the name of the type of item as printed by %T,\n")
+	buf.WriteString("// without reflection for the pointers to the structs of the package\n")
+	buf.WriteString("func typeName(item interface{}) string {\n\tswitch item.(type) {\n")
+	for _, name := range all {
+		fmt.Fprintf(&buf, "\tcase *%s:\n\t\treturn \"*docx.%s\"\n", name, name)
+	}
+	buf.WriteString("\t}\n\treturn fmt.Sprintf(\"%T\", item)\n}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile(*out, src, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// compare returns the condition of the fields x and y of type typ being equal
+func compare(typ ast.Expr, x, y string, generated map[string]bool) (string, error) {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string", "bool", "int", "int64", "uint", "uint64", "float64":
+			return x + " == " + y, nil
+		}
+		if generated[t.Name] {
+			return "(&" + x + ").equal(&" + y + ")", nil
+		}
+	case *ast.SelectorExpr:
+		// xml.Name, xml.Attr...
+		return x + " == " + y, nil
+	case *ast.StarExpr:
+		switch e := t.X.(type) {
+		case *ast.Ident:
+			if generated[e.Name] {
+				return x + ".equal(" + y + ")", nil
+			}
+		case *ast.StructType:
+			if len(e.Fields.List) == 0 {
+				return "(" + x + " == nil) == (" + y + " == nil)", nil
+			}
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if _, ok := t.Elt.(*ast.StarExpr); ok {
+			return "len(" + x + ") == 0 && len(" + y + ") == 0", nil
+		}
+		if _, err := compare(t.Elt, "", "", generated); err != nil {
+			return "", err
+		}
+		return "equalSlice(len(" + x + "), len(" + y + "), func(i int) bool { return " +
+			strings.TrimSpace(mustCompare(t.Elt, x+"[i]", y+"[i]", generated)) + " })", nil
+	}
+	return "", fmt.Errorf("unsupported type %T", typ)
+}
+
+// mustCompare is compare of a type already checked
+func mustCompare(typ ast.Expr, x, y string, generated map[string]bool) string {
+	cond, err := compare(typ, x, y, generated)
+	if err != nil {
+		panic(err)
+	}
+	return cond
+}
@@ -52,7 +52,10 @@ func GetInt64(s string) (int64, error) {
 		return int64(v2), nil
 	}
 	_, err = fmt.Sscanf(s, "%d", &v)
-	return v, err
+	if err != nil {
+		return 0, &strconv.NumError{Func: "GetInt64", Num: s, Err: strconv.ErrSyntax}
+	}
+	return v, nil
 }
 
 // GetInt from string
@@ -66,5 +69,8 @@ func GetInt(s string) (int, error) {
 		return int(v2), nil
 	}
 	_, err = fmt.Sscanf(s, "%d", &v)
-	return v, err
+	if err != nil {
+		return 0, &strconv.NumError{Func: "GetInt", Num: s, Err: strconv.ErrSyntax}
+	}
+	return v, nil
 }
@@ -20,8 +20,180 @@
 
 package docx
 
+import (
+	"bytes"
+	"encoding/xml"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// kinds of the ids allocated by NewID
+//
+//nolint:revive,stylecheck
+const (
+	ID_DRAWING  = "drawing"  // ID_DRAWING are the ids of wp:docPr
+	ID_MEDIA    = "media"    // ID_MEDIA are the numbers of the media names (e.g. image3.png)
+	ID_BOOKMARK = "bookmark" // ID_BOOKMARK are the ids of w:bookmarkStart and w:bookmarkEnd
+	ID_REVISION = "revision" // ID_REVISION are the ids of the tracked changes
+)
+
+// revisionElements are the elements whose w:id is a revision id
+var revisionElements = map[string]bool{
+	"ins": true, "del": true, "moveFrom": true, "moveTo": true,
+	"moveFromRangeStart": true, "moveFromRangeEnd": true,
+	"moveToRangeStart": true, "moveToRangeEnd": true,
+	"rPrChange": true, "pPrChange": true, "sectPrChange": true,
+	"tblPrChange": true, "tblGridChange": true, "trPrChange": true, "tcPrChange": true,
+	"numberingChange": true, "cellIns": true, "cellDel": true, "cellMerge": true,
+}
+
+// NewID returns a new id of kind (e.g. ID_DRAWING) unique in the document.
+//
+// The ids used by the document are scanned on first use,
+// the documents split from it or appended to it share the same ids.
+func (f *Docx) NewID(kind string) int {
+	f = f.root()
+	f.slowIDsMu.Lock()
+	defer f.slowIDsMu.Unlock()
+	f.scanIDs()
+	f.ids[kind]++
+	return f.ids[kind]
+}
+
+// useIDs makes the ids allocated by f follow the ids
+func (f *Docx) useIDs(ids map[string]int) {
+	f = f.root()
+	f.slowIDsMu.Lock()
+	defer f.slowIDsMu.Unlock()
+	f.scanIDs()
+	for kind, n := range ids {
+		if n > f.ids[kind] {
+			f.ids[kind] = n
+		}
+	}
+}
+
+// usedIDs returns the last ids allocated by f by kind
+func (f *Docx) usedIDs() map[string]int {
+	f = f.root()
+	f.slowIDsMu.Lock()
+	defer f.slowIDsMu.Unlock()
+	f.scanIDs()
+	ids := make(map[string]int, len(f.ids))
+	for kind, n := range f.ids {
+		ids[kind] = n
+	}
+	return ids
+}
+
+// scanIDs collects the greatest ids used in the parts once, f.slowIDsMu is held
+func (f *Docx) scanIDs() {
+	if f.ids != nil {
+		return
+	}
+	f.ids = make(map[string]int, 8)
+	use := func(kind string, n int) {
+		if n > f.ids[kind] {
+			f.ids[kind] = n
+		}
+	}
+	for _, m := range f.media {
+		name := strings.TrimSuffix(m.Name, path.Ext(m.Name))
+		i := len(name)
+		for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+			i--
+		}
+		if n, err := strconv.Atoi(name[i:]); err == nil {
+			use(ID_MEDIA, n)
+		}
+	}
+
+	parts := make([]interface{}, 0, 8+len(f.headers)+len(f.footers))
+	parts = append(parts, &f.Document)
+	for _, h := range f.headers {
+		parts = append(parts, h)
+	}
+	for _, h := range f.footers {
+		parts = append(parts, h)
+	}
+	for _, n := range []*Notes{f.footnotes, f.endnotes} {
+		if n != nil {
+			parts = append(parts, n)
+		}
+	}
+	if f.comments != nil {
+		parts = append(parts, f.comments)
+	}
+	var buf bytes.Buffer
+	for _, part := range parts {
+		buf.Reset()
+		_, err := marshaller{data: part}.WriteTo(&buf)
+		if err != nil {
+			continue
+		}
+		d := xml.NewDecoder(&buf)
+		for {
+			t, err := d.Token()
+			if err != nil {
+				break
+			}
+			tt, ok := t.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			var kind string
+			switch {
+			case tt.Name.Space == XMLNS_WP && tt.Name.Local == "docPr":
+				kind = ID_DRAWING
+			case tt.Name.Space == XMLNS_W && (tt.Name.Local == "bookmarkStart" || tt.Name.Local == "bookmarkEnd"):
+				kind = ID_BOOKMARK
+			case tt.Name.Space == XMLNS_W && revisionElements[tt.Name.Local]:
+				kind = ID_REVISION
+			default:
+				continue
+			}
+			for _, a := range tt.Attr {
+				if a.Name.Local == "id" {
+					if n, err := strconv.Atoi(a.Value); err == nil {
+						use(kind, n)
+					}
+				}
+			}
+		}
+	}
+}
+
+// copyID returns the id of kind to use in f for the id of from,
+// the ids of another document are renumbered once so that they stay
+// unique and paired (e.g. a bookmark start and its end)
+func (f *Docx) copyID(from *Docx, kind string, id int) int {
+	f = f.root()
+	if from == nil || from.root() == f || from.root() == f.origin {
+		return id
+	}
+	key := idCopy{from: from.root(), kind: kind, id: id}
+	if n, ok := f.idCopies[key]; ok {
+		return n
+	}
+	n := f.NewID(kind)
+	if f.idCopies == nil {
+		f.idCopies = make(map[idCopy]int, 16)
+	}
+	f.idCopies[key] = n
+	return n
+}
+
+// idCopy is an id of the document from
+type idCopy struct {
+	from *Docx
+	kind string
+	id   int
+}
+
 // IncreaseID by name
 func (f *Docx) IncreaseID(name string) (n uintptr) {
+	f = f.root()
 	f.slowIDsMu.Lock()
 	n = f.slowIDs[name]
 	n++
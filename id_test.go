@@ -0,0 +1,165 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/gif"
+	"strconv"
+	"testing"
+)
+
+const decoded_ids = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	`<w:p><w:bookmarkStart w:id="0" w:name="here"/><w:r><w:t>a</w:t></w:r><w:bookmarkEnd w:id="0"/>` +
+	`<w:ins w:id="7" w:author="me"><w:r><w:t>b</w:t></w:r></w:ins></w:p>` +
+	`</w:body></w:document>`
+
+// idsOf returns the ids of the elements of kind found in the document
+func idsOf(t *testing.T, f *Docx, local string) []int {
+	data, err := xml.Marshal(&f.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if tt, ok := tok.(xml.StartElement); ok && tt.Name.Local == local {
+			for _, a := range tt.Attr {
+				if a.Name.Local == "id" {
+					n, _ := strconv.Atoi(a.Value)
+					ids = append(ids, n)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+func TestDocumentIDs(t *testing.T) {
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDoc := func() *Docx {
+		doc := New().WithDefaultTheme()
+		doc.Document.Body.file = doc
+		err := xml.Unmarshal(StringToBytes(decoded_ids), &doc.Document)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = doc.AddParagraph().AddInlineDrawing(pic.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+
+	doc := newDoc()
+	if ids := idsOf(t, doc, "docPr"); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("unexpected drawing ids %v", ids)
+	}
+	if n := doc.NewID(ID_BOOKMARK); n != 1 {
+		t.Fatalf("new bookmark id %d", n)
+	}
+	if n := doc.NewID(ID_REVISION); n != 8 {
+		t.Fatalf("new revision id %d", n)
+	}
+
+	doc.AppendFile(newDoc())
+	for _, local := range []string{"docPr", "bookmarkStart", "ins"} {
+		seen := make(map[int]bool)
+		for _, id := range idsOf(t, doc, local) {
+			if seen[id] {
+				t.Fatalf("%s id %d is used twice", local, id)
+			}
+			seen[id] = true
+		}
+		if len(seen) != 2 {
+			t.Fatalf("%d %s ids", len(seen), local)
+		}
+	}
+	starts, ends := idsOf(t, doc, "bookmarkStart"), idsOf(t, doc, "bookmarkEnd")
+	if len(starts) != len(ends) || starts[1] != ends[1] {
+		t.Fatalf("bookmark starts %v and ends %v do not match", starts, ends)
+	}
+	if len(doc.media) != 2 || doc.media[0].Name == doc.media[1].Name {
+		t.Fatalf("unexpected media %v %v", doc.media[0].Name, doc.media[1].Name)
+	}
+
+	var max int
+	for _, id := range idsOf(t, doc, "docPr") {
+		if id > max {
+			max = id
+		}
+	}
+	for _, part := range doc.SplitByParagraph(func(p *Paragraph) bool { return len(p.Children) > 0 }) {
+		if n := part.NewID(ID_DRAWING); n <= max {
+			t.Fatalf("new drawing id %d of a split document is used", n)
+		}
+	}
+}
+
+func TestParsedDocumentIDs(t *testing.T) {
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(doc *Docx) {
+		t.Helper()
+		p := doc.AddParagraph()
+		_, err := p.AddInlineDrawing(pic.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddInlineShape(100, 100, "line", "auto", "line", &ALine{})
+		p.Bookmark("mark")
+	}
+	w := New().WithDefaultTheme()
+	add(w)
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	add(doc)
+	for _, local := range []string{"docPr", "cNvPr", "bookmarkStart"} {
+		seen := make(map[int]bool)
+		for _, id := range idsOf(t, doc, local) {
+			if seen[id] {
+				t.Fatalf("%s id %d is used twice", local, id)
+			}
+			seen[id] = true
+		}
+	}
+	starts, ends := idsOf(t, doc, "bookmarkStart"), idsOf(t, doc, "bookmarkEnd")
+	if len(starts) != 2 || len(ends) != 2 || starts[1] != ends[1] {
+		t.Fatalf("bookmark starts %v and ends %v do not match", starts, ends)
+	}
+}
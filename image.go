@@ -20,14 +20,17 @@
 
 package docx
 
-import (
-	"strconv"
-	"sync/atomic"
-)
+import "strconv"
 
 // addImage add image to docx and return its rId
 func (f *Docx) addImage(format string, data []byte) string {
-	m := Media{Name: "image" + strconv.Itoa(int(atomic.AddUintptr(&f.imageID, 1))) + "." + format, Data: data}
-	f.addMedia(m)
+	var m Media
+	for {
+		m = Media{Name: "image" + strconv.Itoa(f.NewID(ID_MEDIA)) + "." + format, Data: data}
+		if f.Media(m.Name) == nil {
+			break
+		}
+	}
+	f.root().addMedia(m)
 	return f.addImageRelation(m)
 }
@@ -22,8 +22,6 @@ package docx
 
 import (
 	"errors"
-	"strconv"
-	"sync/atomic"
 )
 
 var (
@@ -38,7 +36,7 @@ var (
 //	this func is not thread-safe
 func (f *Docx) addLinkRelation(link string) string {
 	rel := Relationship{
-		ID:         "rId" + strconv.Itoa(int(atomic.AddUintptr(&f.rID, 1))),
+		ID:         f.docRelation.newID(&f.rID),
 		Type:       REL_HYPERLINK,
 		Target:     link,
 		TargetMode: REL_TARGETMODE,
@@ -54,7 +52,7 @@ func (f *Docx) addLinkRelation(link string) string {
 //	this func is not thread-safe
 func (f *Docx) addImageRelation(m Media) string {
 	rel := Relationship{
-		ID:     "rId" + strconv.Itoa(int(atomic.AddUintptr(&f.rID, 1))),
+		ID:     f.docRelation.newID(&f.rID),
 		Type:   REL_IMAGE,
 		Target: "media/" + m.Name,
 	}
@@ -64,6 +62,21 @@ func (f *Docx) addImageRelation(m Media) string {
 	return rel.ID
 }
 
+// addRelation stores a reference to an internal target (a part name relative to word/)
+//
+//	this func is not thread-safe
+func (f *Docx) addRelation(typ, target string) string {
+	rel := Relationship{
+		ID:     f.docRelation.newID(&f.rID),
+		Type:   typ,
+		Target: target,
+	}
+
+	f.docRelation.Relationship = append(f.docRelation.Relationship, rel)
+
+	return rel.ID
+}
+
 // ReferTarget gets the target for a reference
 func (f *Docx) ReferTarget(id string) (string, error) {
 	for _, a := range f.docRelation.Relationship {
@@ -83,3 +96,13 @@ func (f *Docx) ReferID(target string) (string, error) {
 	}
 	return "", ErrRefIDNotFound
 }
+
+// hasRelation tells whether there is a relationship of type typ
+func (f *Docx) hasRelation(typ string) bool {
+	for _, a := range f.docRelation.Relationship {
+		if a.Type == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -20,13 +20,63 @@
 
 package docx
 
+import (
+	"archive/zip"
+	"hash/crc32"
+	"io"
+)
+
 //nolint:revive,stylecheck
 const MEDIA_FOLDER = `word/media/`
 
 // Media is in word/media
 type Media struct {
 	Name string // Name is for word/media/Name
-	Data []byte // Data is data of this media
+	Data []byte // Data is data of this media, nil if it is not loaded yet (see Bytes)
+
+	file *zip.File // file is the media in the parsed zip file
+}
+
+// Bytes returns the data of the media, reading it from
+// the parsed file if it is loaded lazily
+func (m *Media) Bytes() ([]byte, error) {
+	if m.Data != nil || m.file == nil {
+		return m.Data, nil
+	}
+	r, err := m.file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// rawFile returns the media in the parsed zip file if it is unchanged
+func (m *Media) rawFile() *zip.File {
+	if m.file == nil {
+		return nil
+	}
+	if m.Data == nil {
+		return m.file
+	}
+	if uint64(len(m.Data)) == m.file.UncompressedSize64 && crc32.ChecksumIEEE(m.Data) == m.file.CRC32 {
+		return m.file
+	}
+	return nil
+}
+
+// WriteTo writes the data of the media
+func (m *Media) WriteTo(w io.Writer) (int64, error) {
+	if m.Data != nil || m.file == nil {
+		n, err := w.Write(m.Data)
+		return int64(n), err
+	}
+	r, err := m.file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.Copy(w, r)
 }
 
 // String is the full path of the media
@@ -36,6 +86,7 @@ func (m *Media) String() string {
 
 // Media get media struct pointer (or nil on notfound) by name
 func (f *Docx) Media(name string) *Media {
+	f = f.root()
 	i, ok := f.mediaNameIdx[name]
 	if !ok {
 		return nil
@@ -25,53 +25,275 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//nolint:revive,stylecheck
+const (
+	WORD_FOLDER       = `word/`
+	PACKAGE_RELS_PART = `_rels/.rels`
+	DOCUMENT_PART     = `word/document.xml`
+	SETTINGS_PART     = `word/settings.xml`
+	FOOTNOTES_PART    = `word/footnotes.xml`
+	ENDNOTES_PART     = `word/endnotes.xml`
+	COMMENTS_PART     = `word/comments.xml`
+	NUMBERING_PART    = `word/numbering.xml`
+	STYLES_PART       = `word/styles.xml`
+
+	COMMENTS_EXTENDED_PART = `word/commentsExtended.xml`
 )
 
 // pack receives a zip file writer (word documents are a zip with multiple xml inside)
 // and writes the relevant files. Some of them come from the empty_constants file,
 // others from the actual in-memory structure
-func (f *Docx) pack(zipWriter *zip.Writer) (err error) {
-	files := make(map[string]io.Reader, 64)
+func (f *Docx) pack(zipWriter *zip.Writer, opts *WriteOptions) (err error) {
+	files, err := f.packParts()
+	if err != nil {
+		return
+	}
+	return writeParts(zipWriter, files, opts)
+}
 
-	if f.template != "" {
-		for _, name := range f.tmpfslst {
-			files[name], err = f.tmplfs.Open("xml/" + f.template + "/" + name)
-			if err != nil {
-				return
-			}
+// writeParts writes the files in the order of sortedParts
+func writeParts(zipWriter *zip.Writer, files map[string]io.WriterTo, opts *WriteOptions) error {
+	for _, name := range sortedParts(files) {
+		err := writePart(zipWriter, name, files[name], opts)
+		if err != nil {
+			return err
 		}
-	} else {
-		for _, name := range f.tmpfslst {
-			files[name], err = f.tmplfs.Open(name)
-			if err != nil {
-				return
-			}
+	}
+
+	return nil
+}
+
+// writePart writes the part name of the package
+func writePart(zipWriter *zip.Writer, name string, part io.WriterTo, opts *WriteOptions) error {
+	method, modTime := opts.method(), opts.modTime()
+	// the unchanged parts of a parsed file are copied as they are compressed,
+	// unless they are to be compressed with another level
+	if r, ok := part.(rawPart); ok && (method == zip.Store || opts.CompressionLevel == 0) {
+		if file := r.rawFile(); file != nil && file.Method == method {
+			return copyRaw(zipWriter, file, name, modTime)
 		}
 	}
+	w, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   method,
+		Modified: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.WriteTo(w)
+	return err
+}
 
-	files["word/_rels/document.xml.rels"] = marshaller{data: &f.docRelation}
-	files["word/document.xml"] = marshaller{data: &f.Document}
+// packParts returns the parts of the package by name as listParts
+// and makes the relationships and the content types they need
+// part of the document
+func (f *Docx) packParts() (map[string]io.WriterTo, error) {
+	files, pk, err := f.listParts()
+	if err != nil {
+		return nil, err
+	}
+	f.contentTypes = pk.ct
+	f.docRelation.Relationship = pk.rels.Relationship
+	f.rID = pk.rID
+	if f.comments != nil {
+		f.comments.exName = pk.exName
+	}
+	return files, nil
+}
 
-	for _, m := range f.media {
-		files[m.String()] = bytes.NewReader(m.Data)
+// packing holds the content types and the document relationships
+// of the listed parts, which are not yet those of the document
+type packing struct {
+	ct     *ContentTypes
+	rels   Relationships
+	rID    uintptr
+	exName string // exName is the name of the extended comments part
+}
+
+// addRelation adds a document relationship as Docx.addRelation
+// if there is none of type typ
+func (pk *packing) addRelation(typ, target string) {
+	for _, r := range pk.rels.Relationship {
+		if r.Type == typ {
+			return
+		}
 	}
+	pk.rels.Relationship = append(pk.rels.Relationship, Relationship{
+		ID:     pk.rels.newID(&pk.rID),
+		Type:   typ,
+		Target: target,
+	})
+}
 
-	for path, r := range files {
-		w, err := zipWriter.Create(path)
-		if err != nil {
-			return err
+// listParts returns the parts of the package by name without changing
+// the document, they are only marshalled or read from the template when written
+func (f *Docx) listParts() (files map[string]io.WriterTo, pk *packing, err error) {
+	files = make(map[string]io.WriterTo, 64)
+
+	base, err := f.loadContentTypes()
+	if err != nil {
+		return
+	}
+	ct := &ContentTypes{
+		XMLName:   base.XMLName,
+		Defaults:  append([]ContentTypeDefault(nil), base.Defaults...),
+		Overrides: append([]ContentTypeOverride(nil), base.Overrides...),
+	}
+	pk = &packing{ct: ct, rels: f.docRelation, rID: f.rID}
+	pk.rels.Relationship = append([]Relationship(nil), f.docRelation.Relationship...)
+	if f.comments != nil {
+		pk.exName = f.comments.exName
+	}
+
+	for _, name := range f.tmpfslst {
+		if name == CONTENT_TYPES || (name == SETTINGS_PART && f.settings != nil) ||
+			(f.numbering != nil && name == f.numbering.name) ||
+			(f.styles != nil && name == f.styles.name) {
+			continue
 		}
+		files[name] = templatePart{f: f, name: name}
+	}
 
-		_, err = io.Copy(w, r)
-		if err != nil {
-			return err
+	for name, rels := range f.partRels {
+		if len(rels.Relationship) > 0 {
+			files[name] = marshaller{data: rels}
 		}
 	}
+	for name, data := range f.parts {
+		files[name] = bytes.NewReader(data)
+	}
+
+	if f.settings != nil {
+		pk.addRelation(REL_SETTINGS, SETTINGS_PART[len(WORD_FOLDER):])
+		ct.Override(SETTINGS_PART, CT_SETTINGS)
+		files[SETTINGS_PART] = marshaller{data: f.settings}
+	}
+
+	if f.numbering != nil {
+		pk.addRelation(REL_NUMBERING, f.numbering.name[len(WORD_FOLDER):])
+		ct.Override(f.numbering.name, CT_NUMBERING)
+		files[f.numbering.name] = marshaller{data: f.numbering}
+	}
+
+	if f.styles != nil {
+		pk.addRelation(REL_STYLES, f.styles.name[len(WORD_FOLDER):])
+		ct.Override(f.styles.name, CT_STYLES)
+		files[f.styles.name] = marshaller{data: f.styles}
+	}
+
+	for _, n := range []*Notes{f.footnotes, f.endnotes} {
+		if n == nil {
+			continue
+		}
+		if n.XMLName.Local == "w:footnotes" {
+			ct.Override(n.name, CT_FOOTNOTES)
+		} else {
+			ct.Override(n.name, CT_ENDNOTES)
+		}
+		files[n.name] = marshaller{data: n}
+		if len(n.file.docRelation.Relationship) > 0 {
+			files[relsPartName(n.name)] = marshaller{data: &n.file.docRelation}
+		}
+	}
+
+	if f.comments != nil {
+		ct.Override(f.comments.name, CT_COMMENTS)
+		files[f.comments.name] = marshaller{data: f.comments}
+		if len(f.comments.file.docRelation.Relationship) > 0 {
+			files[relsPartName(f.comments.name)] = marshaller{data: &f.comments.file.docRelation}
+		}
+		if ex := f.comments.extended(); ex != nil {
+			if pk.exName == "" {
+				pk.exName = COMMENTS_EXTENDED_PART
+			}
+			name := pk.exName
+			pk.addRelation(REL_COMMENTS_EXTENDED, name[len(WORD_FOLDER):])
+			ct.Override(name, CT_COMMENTS_EXTENDED)
+			files[name] = marshaller{data: ex}
+		}
+	}
+
+	for _, h := range f.headers {
+		ct.Override(h.name, CT_HEADER)
+		files[h.name] = marshaller{data: h}
+		if len(h.file.docRelation.Relationship) > 0 {
+			files[relsPartName(h.name)] = marshaller{data: &h.file.docRelation}
+		}
+	}
+	for _, h := range f.footers {
+		ct.Override(h.name, CT_FOOTER)
+		files[h.name] = marshaller{data: h}
+		if len(h.file.docRelation.Relationship) > 0 {
+			files[relsPartName(h.name)] = marshaller{data: &h.file.docRelation}
+		}
+	}
+
+	files[CONTENT_TYPES] = marshaller{data: ct}
+	files["word/_rels/document.xml.rels"] = marshaller{data: &pk.rels}
+	files[DOCUMENT_PART] = marshaller{data: &f.Document}
+
+	for i, m := range f.media {
+		name := m.String()
+		if ct.typeOf(name) == "" {
+			ct.Default(strings.ToLower(strings.TrimPrefix(path.Ext(name), ".")), mediaContentType(name))
+		}
+		files[name] = &f.media[i]
+	}
+
+	// the parts which are not written anymore are dropped from the content types
+	written := make(map[string]bool, len(files))
+	for name := range files {
+		written[strings.ToLower(name)] = true
+	}
+	ct.removeOverrides(func(name string) bool {
+		return written[strings.ToLower(name)]
+	})
 
 	return
 }
 
+// sortedParts returns the names of files in the order they are written
+func sortedParts(files map[string]io.WriterTo) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := partRank(names[i]), partRank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// partRank ranks the parts in the package: the content types first,
+// then the package relationships and the main document
+func partRank(name string) int {
+	switch name {
+	case CONTENT_TYPES:
+		return 0
+	case PACKAGE_RELS_PART:
+		return 1
+	case DOCUMENT_PART:
+		return 2
+	case relsPartName(DOCUMENT_PART):
+		return 3
+	}
+	return 4
+}
+
 type marshaller struct {
 	data interface{}
 	io.Reader
@@ -92,3 +314,123 @@ func (m marshaller) WriteTo(w io.Writer) (n int64, err error) {
 	err = xml.NewEncoder(w).Encode(m.data)
 	return
 }
+
+// templatePart is a part copied from the template
+type templatePart struct {
+	f    *Docx
+	name string
+}
+
+// WriteTo copies the part
+func (t templatePart) WriteTo(w io.Writer) (int64, error) {
+	r, err := t.f.openTemplate(t.name)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.Copy(w, r)
+}
+
+// rawFile returns the part in the parsed zip file, nil if the template is not one
+func (t templatePart) rawFile() *zip.File {
+	zr, ok := t.f.tmplfs.(*zip.Reader)
+	if !ok || t.f.template != "" {
+		return nil
+	}
+	for _, file := range zr.File {
+		if file.Name == t.name {
+			return file
+		}
+	}
+	return nil
+}
+
+// rawPart is a part which can be copied from the parsed zip file
+type rawPart interface {
+	rawFile() *zip.File
+}
+
+// copyRaw copies the compressed file to the part name
+func copyRaw(zipWriter *zip.Writer, file *zip.File, name string, modTime time.Time) error {
+	r, err := file.OpenRaw()
+	if err != nil {
+		return err
+	}
+	w, err := zipWriter.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             file.Method,
+		Modified:           modTime,
+		CRC32:              file.CRC32,
+		CompressedSize64:   file.CompressedSize64,
+		UncompressedSize64: file.UncompressedSize64,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// openTemplate opens the file name of the template
+func (f *Docx) openTemplate(name string) (fs.File, error) {
+	if f.template != "" {
+		return f.tmplfs.Open("xml/" + f.template + "/" + name)
+	}
+	return f.tmplfs.Open(name)
+}
+
+// hasTemplateFile tells whether name is one of the template files
+func (f *Docx) hasTemplateFile(name string) bool {
+	for _, n := range f.tmpfslst {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTemplatePart unmarshals the template file name into v
+func (f *Docx) loadTemplatePart(name string, v interface{}) error {
+	r, err := f.openTemplate(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	d, release := newDecoder(r)
+	defer release()
+	return d.Decode(v)
+}
+
+// loadContentTypes returns the content types of the package,
+// loading them from the template on first use
+func (f *Docx) loadContentTypes() (*ContentTypes, error) {
+	if f.contentTypes != nil {
+		return f.contentTypes, nil
+	}
+	ct := &ContentTypes{}
+	if f.hasTemplateFile(CONTENT_TYPES) {
+		err := f.loadTemplatePart(CONTENT_TYPES, ct)
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.contentTypes = ct
+	return ct, nil
+}
+
+// relsPartName returns the name of the relationships part of the part name
+//
+//	e.g. word/document.xml => word/_rels/document.xml.rels
+func relsPartName(name string) string {
+	dir, file := path.Split(name)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// relTargetPart resolves the target of a relationship
+// of the part source to a part name
+func relTargetPart(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return target[1:]
+	}
+	return path.Join(path.Dir(source), target)
+}
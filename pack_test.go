@@ -0,0 +1,257 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestDeterministicPack(t *testing.T) {
+	newDoc := func() *Docx {
+		w := New().WithDefaultTheme().WithA4Page()
+		w.AddParagraph().AddText("body")
+		w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("header")
+		w.AddParagraph().AddFootnote("note")
+		return w
+	}
+	var outs [2]bytes.Buffer
+	for i := range outs {
+		n, err := newDoc().WriteTo(&outs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(outs[i].Len()) {
+			t.Fatalf("%d bytes written, %d counted", outs[i].Len(), n)
+		}
+	}
+	if !bytes.Equal(outs[0].Bytes(), outs[1].Bytes()) {
+		t.Fatal("the same document gives different bytes")
+	}
+	zr, err := zip.NewReader(bytes.NewReader(outs[0].Bytes()), int64(outs[0].Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zr.File[0].Name != CONTENT_TYPES {
+		t.Fatalf("first part is %s", zr.File[0].Name)
+	}
+	for i := 1; i < len(zr.File); i++ {
+		if partRank(zr.File[i-1].Name) == partRank(zr.File[i].Name) && zr.File[i-1].Name > zr.File[i].Name {
+			t.Fatalf("%s is written before %s", zr.File[i-1].Name, zr.File[i].Name)
+		}
+	}
+
+	mod := time.Date(2024, 5, 6, 7, 8, 10, 0, time.UTC)
+	var buf bytes.Buffer
+	_, err = newDoc().WriteToWithOptions(&buf, &WriteOptions{CompressionLevel: -1, ModTime: mod})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err = zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Method != zip.Store || !f.Modified.Equal(mod) {
+			t.Fatalf("%s: unexpected method %d or time %v", f.Name, f.Method, f.Modified)
+		}
+	}
+	_, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	_, err = newDoc().WriteToWithOptions(&buf, &WriteOptions{CompressionLevel: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > outs[0].Len() {
+		t.Fatalf("best compression gives %d bytes, default %d", buf.Len(), outs[0].Len())
+	}
+
+	// the parsed parts are compressed again with the level
+	themeSize := func(data []byte) uint64 {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range zr.File {
+			if f.Name == "word/theme/theme1.xml" {
+				return f.CompressedSize64
+			}
+		}
+		t.Fatal("no theme")
+		return 0
+	}
+	best := themeSize(buf.Bytes())
+	buf.Reset()
+	_, err = newDoc().WriteToWithOptions(&buf, &WriteOptions{CompressionLevel: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	_, err = doc.WriteToWithOptions(&buf, &WriteOptions{CompressionLevel: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := themeSize(buf.Bytes()); size != best {
+		t.Fatalf("the parsed theme is compressed to %d bytes, %d expected", size, best)
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.AddParagraph().AddInlineDrawing(pic.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SectPr().AddFooter(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("footer")
+	ct, err := w.loadContentTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct.Override("word/removed.xml", CT_HEADER)
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed ContentTypes
+	r, err := zr.Open(CONTENT_TYPES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = xml.NewDecoder(r).Decode(&parsed)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ := parsed.typeOf("word/media/image1.gif"); typ != "image/gif" {
+		t.Fatalf("unexpected gif content type %q", typ)
+	}
+	if typ := parsed.typeOf("word/footer1.xml"); typ != CT_FOOTER {
+		t.Fatalf("unexpected footer content type %q", typ)
+	}
+	for _, o := range parsed.Overrides {
+		if _, err := zr.Open(o.PartName[1:]); err != nil {
+			t.Fatalf("override of the missing part %s", o.PartName)
+		}
+	}
+}
+
+func TestRawCopy(t *testing.T) {
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 64, 32)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := New().WithDefaultTheme()
+	_, err = w.AddParagraph().AddInlineDrawing(pic.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var in bytes.Buffer
+	_, err = w.WriteTo(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawOf := func(data []byte) map[string][]byte {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := make(map[string][]byte, len(zr.File))
+		for _, f := range zr.File {
+			r, err := f.OpenRaw()
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := new(bytes.Buffer)
+			_, err = buf.ReadFrom(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw[f.Name] = buf.Bytes()
+		}
+		return raw
+	}
+	save := func(doc *Docx) []byte {
+		var out bytes.Buffer
+		_, err := doc.WriteTo(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.Bytes()
+	}
+
+	for _, lazy := range []bool{false, true} {
+		doc, err := ParseWithOptions(bytes.NewReader(in.Bytes()), int64(in.Len()), &ParseOptions{LazyMedia: lazy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.AddParagraph().AddText("more")
+		out := save(doc)
+		before, after := rawOf(in.Bytes()), rawOf(out)
+		for _, name := range []string{"word/media/image1.gif", "word/theme/theme1.xml", "docProps/app.xml"} {
+			if !bytes.Equal(before[name], after[name]) {
+				t.Fatalf("%s is not copied as it is", name)
+			}
+		}
+		if bytes.Equal(before[DOCUMENT_PART], after[DOCUMENT_PART]) {
+			t.Fatal("the document is copied as it is")
+		}
+
+		// the changed media is compressed again
+		doc, err = Parse(bytes.NewReader(out), int64(len(out)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(doc.Media("image1.gif").Data, pic.Bytes()) {
+			t.Fatal("unexpected media before the change")
+		}
+		doc.Media("image1.gif").Data = []byte("changed")
+		out = save(doc)
+		doc, err = Parse(bytes.NewReader(out), int64(len(out)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc.Media("image1.gif").Data) != "changed" {
+			t.Fatal("the changed media is not written")
+		}
+	}
+}
@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"strings"
 )
 
 // WordprocessingCanvas ...
@@ -53,27 +52,29 @@ func (c *WordprocessingCanvas) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 			case "bg":
 				c.Background = new(WPCBackground)
 				err = d.DecodeElement(c.Background, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 			case "whole":
 				c.Whole = new(WPCWhole)
+				c.Whole.file = c.file
 				err = d.DecodeElement(c.Whole, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 			case "wsp":
 				var value WordprocessingShape
 				value.file = c.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				c.Items = append(c.Items, &value)
 			case "pic":
 				var value Picture
+				value.file = c.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				value.XMLPIC = getAtt(tt.Attr, "pic")
@@ -82,7 +83,7 @@ func (c *WordprocessingCanvas) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 				var value WordprocessingGroup
 				value.file = c.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				c.Items = append(c.Items, &value)
@@ -135,6 +136,8 @@ func (b *WPCBackground) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) (err er
 type WPCWhole struct {
 	XMLName xml.Name `xml:"wpc:whole,omitempty"`
 	Line    *ALine
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -152,8 +155,9 @@ func (w *WPCWhole) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) (err error)
 			switch tt.Name.Local {
 			case "ln":
 				w.Line = new(ALine)
+				w.Line.file = w.file
 				err = d.DecodeElement(w.Line, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			default:
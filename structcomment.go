@@ -0,0 +1,306 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+//nolint:revive,stylecheck
+const (
+	XMLNS_W14 = `http://schemas.microsoft.com/office/word/2010/wordml`
+	XMLNS_W15 = `http://schemas.microsoft.com/office/word/2012/wordml`
+)
+
+// Comments is the comments part (word/comments.xml) <w:comments>
+type Comments struct {
+	XMLName xml.Name `xml:"w:comments"`
+	XMLW    string   `xml:"xmlns:w,attr"`
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"`
+
+	Comments []*Comment `xml:"w:comment"`
+
+	name   string // name is the part name, e.g. word/comments.xml
+	exName string // exName is the extended part name, e.g. word/commentsExtended.xml
+	file   *Docx  // file owns the relationships of the part
+
+	namespaces []xml.Attr // namespaces are the other declarations of the root (xmlns:w15, mc:Ignorable...)
+}
+
+// UnmarshalXML ...
+func (c *Comments) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	c.namespaces = rootAttrs(start, map[string]*string{
+		"w": &c.XMLW, "r": &c.XMLR, "wp": &c.XMLWP, "w14": &c.XMLW14,
+	})
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "comment" {
+				var value Comment
+				value.file = c.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !c.file.tolerated(err) {
+					return err
+				}
+				c.Comments = append(c.Comments, &value)
+				continue
+			}
+			err = d.Skip() // skip unsupported tags
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes the namespaces declared on the root
+func (c *Comments) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type _c Comments
+	return e.Encode(struct {
+		*_c
+		Namespaces []xml.Attr `xml:",any,attr"`
+	}{(*_c)(c), c.namespaces})
+}
+
+// Comment <w:comment> is a review comment
+//
+// It holds the same items as the Body: *Paragraph and *Table
+type Comment struct {
+	XMLName  xml.Name `xml:"w:comment"`
+	ID       int      `xml:"w:id,attr"`
+	Author   string   `xml:"w:author,attr,omitempty"`
+	Date     string   `xml:"w:date,attr,omitempty"`
+	Initials string   `xml:"w:initials,attr,omitempty"`
+
+	Items []interface{}
+
+	paraID       string // paraID is the w14:paraId of the last paragraph
+	parentParaID string // parentParaID is the paraID of the replied comment
+	done         bool
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (c *Comment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			c.ID, err = GetInt(attr.Value)
+			if err != nil {
+				return err
+			}
+		case "author":
+			c.Author = attr.Value
+		case "date":
+			c.Date = attr.Value
+		case "initials":
+			c.Initials = attr.Value
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if isEnd(t, start) {
+			break
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "p":
+				var value Paragraph
+				value.file = c.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !c.file.tolerated(err) {
+					return err
+				}
+				c.paraID = getAtt(tt.Attr, "paraId")
+				c.Items = append(c.Items, &value)
+			case "tbl":
+				var value Table
+				value.file = c.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !c.file.tolerated(err) {
+					return err
+				}
+				c.Items = append(c.Items, &value)
+			default:
+				raw, err := readUnknown(d, tt)
+				if err != nil {
+					return err
+				}
+				c.Items = append(c.Items, raw)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes the paraID on the last paragraph
+func (c *Comment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _c Comment
+	if c.paraID == "" || len(c.Items) == 0 {
+		return e.EncodeElement((*_c)(c), start)
+	}
+	p, ok := c.Items[len(c.Items)-1].(*Paragraph)
+	if !ok {
+		return e.EncodeElement((*_c)(c), start)
+	}
+	nc := *c
+	nc.Items = c.Items[:len(c.Items)-1]
+	nc.Items = append(nc.Items[:len(nc.Items):len(nc.Items)], &paraWithID{p: p, id: c.paraID})
+	return e.EncodeElement((*_c)(&nc), start)
+}
+
+// paraWithID encodes a paragraph with a w14:paraId
+type paraWithID struct {
+	p  *Paragraph
+	id string
+}
+
+// MarshalXML ...
+func (p *paraWithID) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.EncodeElement(p.p, xml.StartElement{
+		Name: xml.Name{Local: "w:p"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "w14:paraId"}, Value: p.id}},
+	})
+}
+
+// CommentsEx is the extended comments part (word/commentsExtended.xml) <w15:commentsEx>
+type CommentsEx struct {
+	XMLName xml.Name `xml:"w15:commentsEx"`
+	XMLW15  string   `xml:"xmlns:w15,attr"`
+
+	Items []*CommentEx
+}
+
+// UnmarshalXML ...
+func (c *CommentsEx) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "commentEx" {
+				var value CommentEx
+				for _, attr := range tt.Attr {
+					switch attr.Name.Local {
+					case "paraId":
+						value.ParaID = attr.Value
+					case "paraIdParent":
+						value.ParaIDParent = attr.Value
+					case "done":
+						value.Done = attr.Value
+					}
+				}
+				c.Items = append(c.Items, &value)
+			}
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CommentEx <w15:commentEx> holds the reply and resolved state of a comment
+type CommentEx struct {
+	XMLName      xml.Name `xml:"w15:commentEx"`
+	ParaID       string   `xml:"w15:paraId,attr"`
+	ParaIDParent string   `xml:"w15:paraIdParent,attr,omitempty"`
+	Done         string   `xml:"w15:done,attr,omitempty"`
+}
+
+// CommentRangeStart <w:commentRangeStart> marks the start of the commented content
+type CommentRangeStart struct {
+	XMLName xml.Name `xml:"w:commentRangeStart,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *CommentRangeStart) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, err = GetInt(getAtt(start.Attr, "id"))
+	if err != nil {
+		return
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// CommentRangeEnd <w:commentRangeEnd> marks the end of the commented content
+type CommentRangeEnd struct {
+	XMLName xml.Name `xml:"w:commentRangeEnd,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *CommentRangeEnd) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, err = GetInt(getAtt(start.Attr, "id"))
+	if err != nil {
+		return
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// CommentReference <w:commentReference> is the mark of a comment in a run
+type CommentReference struct {
+	XMLName xml.Name `xml:"w:commentReference,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *CommentReference) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, err = GetInt(getAtt(start.Attr, "id"))
+	if err != nil {
+		return
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// AnnotationRef <w:annotationRef> is the mark of the comment in the comment itself
+type AnnotationRef struct {
+	XMLName xml.Name `xml:"w:annotationRef,omitempty"`
+}
@@ -0,0 +1,157 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommentsRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("reviewed text")
+	date := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := p.AddComment("Reviewer", "RV", date, "please check")
+	c.Reply("Author", "AU", date, "checked").Resolve()
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments := doc.Comments()
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if comments[0].Author != "Reviewer" || comments[0].Initials != "RV" || comments[0].String() != "please check" ||
+		comments[0].Date != "2025-01-02T03:04:05Z" {
+		t.Fatalf("unexpected comment %+v", comments[0])
+	}
+	if comments[0].Parent() != nil || comments[0].Resolved() {
+		t.Fatal("unexpected comment state")
+	}
+	if comments[1].Parent() != comments[0] || !comments[1].Resolved() {
+		t.Fatal("reply state was not saved")
+	}
+
+	para := doc.Document.Body.Items[0].(*Paragraph)
+	if starts, ends, refs := commentMarks(para); starts != 2 || ends != 2 || refs != 2 {
+		t.Fatalf("unexpected comment marks %d %d %d", starts, ends, refs)
+	}
+	if para.String() != "reviewed text" {
+		t.Fatalf("unexpected paragraph text %q", para.String())
+	}
+
+	nw := New().WithDefaultTheme()
+	if nw.AddParagraph().AddComment("Other", "OT", time.Time{}, "existing").Date != "" {
+		t.Fatal("the zero date is written")
+	}
+	nw.AppendFile(doc)
+	if len(nw.Comments()) != 3 {
+		t.Fatalf("expected 3 comments, got %d", len(nw.Comments()))
+	}
+	if nw.Comments()[2].Parent() != nw.Comments()[1] || nw.Comments()[1].ID == 0 {
+		t.Fatal("comments were not copied")
+	}
+}
+
+// commentMarks counts the comment ranges and references of the paragraph
+func commentMarks(p *Paragraph) (starts, ends, refs int) {
+	for _, c := range p.Children {
+		switch o := c.(type) {
+		case *CommentRangeStart:
+			starts++
+		case *CommentRangeEnd:
+			ends++
+		case *Run:
+			for _, rc := range o.Children {
+				if _, ok := rc.(*CommentReference); ok {
+					refs++
+				}
+			}
+		}
+	}
+	return
+}
+
+func TestCommentReplyInHeader(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph()
+	p.AddText("header")
+	p.AddComment("Reviewer", "RV", time.Time{}, "in the header").Reply("Author", "AU", time.Time{}, "replied")
+	if starts, ends, refs := commentMarks(p); starts != 2 || ends != 2 || refs != 2 {
+		t.Fatalf("unexpected comment marks %d %d %d", starts, ends, refs)
+	}
+}
+
+const decoded_comments = `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" ` +
+	`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" ` +
+	`xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml" mc:Ignorable="w14 w15">` +
+	`<w:comment w:id="0" w:author="Reviewer"><w:p><w:r><w:t>comment</w:t></w:r></w:p></w:comment></w:comments>`
+
+func TestCommentsNamespaces(t *testing.T) {
+	var c Comments
+	err := xml.Unmarshal(StringToBytes(decoded_comments), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, frag := range []string{
+		`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"`,
+		`xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml"`,
+		`mc:Ignorable="w14 w15"`,
+	} {
+		if !strings.Contains(string(out), frag) {
+			t.Errorf("%s not found in %s", frag, out)
+		}
+	}
+}
+
+func TestCommentsDecodeItems(t *testing.T) {
+	const comments = `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:comment w:id="0" w:author="a"><w:p><w:r><w:t>first</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>cell</w:t></w:r></w:p></w:tc></w:tr></w:tbl></w:comment>` +
+		`<w:comment w:id="1" w:author="b"><w:p><w:r><w:t>second</w:t></w:r></w:p></w:comment></w:comments>`
+	var c Comments
+	err := xml.Unmarshal(StringToBytes(comments), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Comments) != 2 || len(c.Comments[0].Items) != 2 || len(c.Comments[1].Items) != 1 {
+		t.Fatalf("unexpected comments %+v", c.Comments)
+	}
+	if _, ok := c.Comments[0].Items[1].(*Table); !ok {
+		t.Fatalf("unexpected item %T", c.Comments[0].Items[1])
+	}
+	if c.Comments[1].Author != "b" || c.Comments[1].Items[0].(*Paragraph).String() != "second" {
+		t.Fatal("the second comment is not decoded")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"path"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	CONTENT_TYPES = `[Content_Types].xml`
+
+	CT_HEADER    = `application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml`
+	CT_FOOTER    = `application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml`
+	CT_SETTINGS  = `application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml`
+	CT_FOOTNOTES = `application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml`
+	CT_ENDNOTES  = `application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml`
+	CT_COMMENTS  = `application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml`
+	CT_NUMBERING = `application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml`
+	CT_STYLES    = `application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml`
+
+	CT_COMMENTS_EXTENDED = `application/vnd.openxmlformats-officedocument.wordprocessingml.commentsExtended+xml`
+)
+
+// ContentTypes is [Content_Types].xml
+type ContentTypes struct {
+	XMLName   xml.Name              `xml:"http://schemas.openxmlformats.org/package/2006/content-types Types"`
+	Defaults  []ContentTypeDefault  `xml:"Default"`
+	Overrides []ContentTypeOverride `xml:"Override"`
+}
+
+// ContentTypeDefault gives the content type of all the parts with an extension
+type ContentTypeDefault struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// ContentTypeOverride gives the content type of one part
+type ContentTypeOverride struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// Override sets the content type of the part name (without the leading /)
+func (ct *ContentTypes) Override(name, contentType string) {
+	name = "/" + strings.TrimPrefix(name, "/")
+	for i := range ct.Overrides {
+		if strings.EqualFold(ct.Overrides[i].PartName, name) {
+			ct.Overrides[i].ContentType = contentType
+			return
+		}
+	}
+	ct.Overrides = append(ct.Overrides, ContentTypeOverride{PartName: name, ContentType: contentType})
+}
+
+// Default sets the content type of the parts with the extension ext
+func (ct *ContentTypes) Default(ext, contentType string) {
+	for i := range ct.Defaults {
+		if strings.EqualFold(ct.Defaults[i].Extension, ext) {
+			ct.Defaults[i].ContentType = contentType
+			return
+		}
+	}
+	ct.Defaults = append(ct.Defaults, ContentTypeDefault{Extension: ext, ContentType: contentType})
+}
+
+// typeOf returns the content type of the part name (without the leading /)
+// or "" if it has none
+func (ct *ContentTypes) typeOf(name string) string {
+	pname := "/" + strings.TrimPrefix(name, "/")
+	for _, o := range ct.Overrides {
+		if strings.EqualFold(o.PartName, pname) {
+			return o.ContentType
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, d := range ct.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return d.ContentType
+		}
+	}
+	return ""
+}
+
+// removeOverrides removes the overrides of the parts for which keep is false
+func (ct *ContentTypes) removeOverrides(keep func(name string) bool) {
+	n := 0
+	for _, o := range ct.Overrides {
+		if keep(strings.TrimPrefix(o.PartName, "/")) {
+			ct.Overrides[n] = o
+			n++
+		}
+	}
+	ct.Overrides = ct.Overrides[:n]
+}
+
+// mediaContentTypes are the content types of the usual media extensions
+var mediaContentTypes = map[string]string{
+	"png":  "image/png",
+	"jpeg": "image/jpeg",
+	"jpg":  "image/jpeg",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"tif":  "image/tiff",
+	"tiff": "image/tiff",
+	"svg":  "image/svg+xml",
+	"webp": "image/webp",
+	"emf":  "image/x-emf",
+	"wmf":  "image/x-wmf",
+	"ico":  "image/x-icon",
+}
+
+// mediaContentType returns the content type of the media name
+func mediaContentType(name string) string {
+	if t, ok := mediaContentTypes[strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))]; ok {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -23,9 +23,7 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"reflect"
 	"regexp"
-	"strings"
 )
 
 //nolint:revive,stylecheck
@@ -62,7 +60,7 @@ type Body struct {
 }
 
 // UnmarshalXML ...
-func (b *Body) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (b *Body) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -71,43 +69,44 @@ func (b *Body) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.StartElement); ok {
-			switch tt.Name.Local {
-			case "p":
-				var value Paragraph
-				value.file = b.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			case "tbl":
-				var value Table
-				value.file = b.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			case "sectPr":
-				var value SectPr
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
-					return err
-				}
+			value, err := b.decodeItem(d, tt)
+			if err != nil {
+				return err
 			}
+			b.Items = append(b.Items, value)
 		}
 	}
 	return nil
 }
 
+// decodeItem decodes the item of the body started by tt
+func (b *Body) decodeItem(d *xml.Decoder, tt xml.StartElement) (interface{}, error) {
+	var value xml.Unmarshaler
+	var err error
+	switch tt.Name.Local {
+	case "p":
+		value = &Paragraph{file: b.file}
+		err = value.UnmarshalXML(d, tt)
+	case "tbl":
+		value = &Table{file: b.file}
+		err = value.UnmarshalXML(d, tt)
+	case "sectPr":
+		value = &SectPr{file: b.file}
+		err = value.UnmarshalXML(d, tt)
+	default:
+		return readUnknown(d, tt)
+	}
+	if err != nil && !b.file.tolerated(err) {
+		return nil, err
+	}
+	return value, nil
+}
+
 // KeepElements keep named elems amd removes others
 //
 // names: *docx.Paragraph *docx.Table
@@ -118,7 +117,7 @@ func (b *Body) KeepElements(name ...string) {
 		namemap[n] = struct{}{}
 	}
 	for _, item := range b.Items {
-		_, ok := namemap[reflect.ValueOf(item).Type().String()]
+		_, ok := namemap[typeName(item)]
 		if ok {
 			items = append(items, item)
 		}
@@ -129,23 +128,32 @@ func (b *Body) KeepElements(name ...string) {
 // DropDrawingOf drops all matched drawing in body
 // name: Canvas, Shape, Group, ShapeAndCanvas, ShapeAndCanvasAndGroup, NilPicture
 func (b *Body) DropDrawingOf(name string) {
+	var drop func(*Paragraph)
+	switch name {
+	case "Canvas":
+		drop = (*Paragraph).DropCanvas
+	case "Shape":
+		drop = (*Paragraph).DropShape
+	case "Group":
+		drop = (*Paragraph).DropGroup
+	case "ShapeAndCanvas":
+		drop = (*Paragraph).DropShapeAndCanvas
+	case "ShapeAndCanvasAndGroup":
+		drop = (*Paragraph).DropShapeAndCanvasAndGroup
+	case "NilPicture":
+		drop = (*Paragraph).DropNilPicture
+	default:
+		return
+	}
 	for _, item := range b.Items {
 		switch o := item.(type) {
 		case *Paragraph:
-			f := reflect.ValueOf(o).MethodByName("Drop" + name)
-			if !f.IsValid() {
-				continue
-			}
-			_ = f.Call(nil)
+			drop(o)
 		case *Table:
 			for _, tr := range o.Rows {
 				for _, tc := range tr.Cells {
 					for _, p := range tc.Paragraphs {
-						f := reflect.ValueOf(p).MethodByName("Drop" + name)
-						if !f.IsValid() {
-							continue
-						}
-						_ = f.Call(nil)
+						drop(p)
 					}
 				}
 			}
@@ -172,19 +180,52 @@ type Document struct {
 	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
 	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
 	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
-	// XMLMC   string   `xml:"xmlns:mc,attr,omitempty"`  // cannot be unmarshalled in
+	XMLMC   string   `xml:"xmlns:mc,attr,omitempty"`  // XMLMC is declared along with MCIgnorable
 	// XMLWP14 string   `xml:"xmlns:wp14,attr,omitempty"` // cannot be unmarshalled in
 
 	// XMLO string `xml:"xmlns:o,attr,omitempty"` // cannot be unmarshalled in
 	// XMLV string `xml:"xmlns:v,attr,omitempty"` // cannot be unmarshalled in
 
-	// MCIgnorable string `xml:"mc:Ignorable,attr,omitempty"`
+	MCIgnorable string `xml:"mc:Ignorable,attr,omitempty"` // MCIgnorable lists the ignorable prefixes
 
 	Body Body `xml:"w:body"`
+
+	namespaces []xml.Attr    // namespaces are the other declarations of the root (xmlns:w14...)
+	items      []*rawElement // items are the unsupported elements before the body
+}
+
+// declare declares url with prefix on the root
+func (doc *Document) declare(prefix, url string) {
+	switch prefix {
+	case "w":
+		doc.XMLW = url
+	case "r":
+		doc.XMLR = url
+	case "wp":
+		doc.XMLWP = url
+	case "wps":
+		doc.XMLWPS = url
+	case "wpc":
+		doc.XMLWPC = url
+	case "wpg":
+		doc.XMLWPG = url
+	case "mc":
+		doc.XMLMC = url
+	default:
+		name := xml.Name{Local: "xmlns:" + prefix}
+		for i, ns := range doc.namespaces {
+			if ns.Name == name {
+				doc.namespaces[i].Value = url
+				return
+			}
+		}
+		doc.namespaces = append(doc.namespaces, xml.Attr{Name: name, Value: url})
+	}
 }
 
 // UnmarshalXML ...
-func (doc *Document) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (doc *Document) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	doc.readRoot(start)
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -196,21 +237,80 @@ func (doc *Document) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 
 		if tt, ok := t.(xml.StartElement); ok {
 			if tt.Name.Local == "body" {
-				err = d.DecodeElement(&doc.Body, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = doc.Body.UnmarshalXML(d, tt)
+				if err != nil && !doc.Body.file.tolerated(err) {
 					return err
 				}
 				continue
 			}
-			err = d.Skip() // skip unsupported tags
+			value, err := readUnknown(d, tt)
 			if err != nil {
 				return err
 			}
+			doc.items = append(doc.items, value)
 		}
 	}
 	return nil
 }
 
+// readRoot reads the namespaces declared on the root element start
+func (doc *Document) readRoot(start xml.StartElement) {
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			doc.declare(attr.Name.Local, attr.Value)
+		case attr.Name.Space == XMLNS_MC && attr.Name.Local == "Ignorable":
+			doc.MCIgnorable = attr.Value
+		}
+	}
+	// mc:Ignorable is written with the mc prefix whatever the one read
+	if doc.MCIgnorable != "" && doc.XMLMC == "" {
+		doc.XMLMC = XMLNS_MC
+	}
+}
+
+// rootAttrs reads the namespaces declared on the root element start of a part,
+// the prefixes of known are set through their field and the other
+// declarations are returned along with mc:Ignorable, the mc prefix
+// is declared for it if the namespace is read under another one
+func rootAttrs(start xml.StartElement, known map[string]*string) (attrs []xml.Attr) {
+	mc, ignorable := false, false
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			mc = mc || attr.Name.Local == "mc"
+			if v, ok := known[attr.Name.Local]; ok {
+				*v = attr.Value
+				continue
+			}
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + attr.Name.Local}, Value: attr.Value})
+		case attr.Name.Space == XMLNS_MC && attr.Name.Local == "Ignorable":
+			ignorable = true
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "mc:Ignorable"}, Value: attr.Value})
+		}
+	}
+	if ignorable && !mc {
+		if v, ok := known["mc"]; ok {
+			*v = XMLNS_MC
+		} else {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:mc"}, Value: XMLNS_MC})
+		}
+	}
+	return
+}
+
+// MarshalXML writes the namespaces declared on the root
+// and the unsupported elements (e.g. <w:background>) before the body
+func (doc *Document) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type _d Document
+	return e.Encode(struct {
+		*_d
+		Namespaces []xml.Attr `xml:",any,attr"`
+		Items      []*rawElement
+		Body       *Body `xml:"w:body"`
+	}{(*_d)(doc), doc.namespaces, doc.items, &doc.Body})
+}
+
 // ParagraphSplitRule check whether the paragraph is a separator or not
 type ParagraphSplitRule func(*Paragraph) bool
 
@@ -229,51 +329,7 @@ func (f *Docx) SplitByParagraph(separator ParagraphSplitRule) (docs []*Docx) {
 	items := f.Document.Body.Items
 newdoclop:
 	for len(items) > 0 {
-		ndoc := new(Docx)
-
-		// migrate base data
-		ndoc.mediaNameIdx = make(map[string]int, 64)
-		ndoc.slowIDs = make(map[string]uintptr, 64)
-		ndoc.template = f.template
-		ndoc.tmplfs = f.tmplfs
-		ndoc.tmpfslst = f.tmpfslst
-
-		ndoc.Document.XMLW = XMLNS_W
-		ndoc.Document.XMLR = XMLNS_R
-		ndoc.Document.XMLWP = XMLNS_WP
-		// ndoc.Document.XMLMC = XMLNS_MC
-		// ndoc.Document.XMLO = XMLNS_O
-		// ndoc.Document.XMLV = XMLNS_V
-		ndoc.Document.XMLWPS = XMLNS_WPS
-		ndoc.Document.XMLWPC = XMLNS_WPC
-		ndoc.Document.XMLWPG = XMLNS_WPG
-		// ndoc.Document.XMLWP14 = XMLNS_WP14
-		ndoc.Document.XMLName.Space = XMLNS_W
-		ndoc.Document.XMLName.Local = "document"
-		ndoc.Document.Body.file = ndoc
-
-		ndoc.docRelation = Relationships{
-			Xmlns: XMLNS_REL,
-			Relationship: []Relationship{
-				{
-					ID:     "rId1",
-					Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles`,
-					Target: "styles.xml",
-				},
-				{
-					ID:     "rId2",
-					Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme`,
-					Target: "theme/theme1.xml",
-				},
-				{
-					ID:     "rId3",
-					Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable`,
-					Target: "fontTable.xml",
-				},
-			},
-		}
-
-		ndoc.rID = 3
+		ndoc := f.derive()
 
 		for i, item := range items {
 			switch o := item.(type) {
@@ -288,8 +344,12 @@ newdoclop:
 			case *Table:
 				nt := o.copymedia(ndoc)
 				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, &nt)
+			case *SectPr:
+				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, o.copymedia(ndoc))
 			default:
-				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, o)
+				if copyable(o) {
+					ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, o)
+				}
 			}
 		}
 
@@ -301,16 +361,90 @@ newdoclop:
 	return
 }
 
+// derive returns a new empty document using the same template as f
+func (f *Docx) derive() *Docx {
+	ndoc := new(Docx)
+
+	// migrate base data
+	ndoc.mediaNameIdx = make(map[string]int, 64)
+	ndoc.slowIDs = make(map[string]uintptr, 64)
+	ndoc.ids = f.usedIDs()
+	ndoc.origin = f.root()
+	ndoc.template = f.template
+	ndoc.tmplfs = f.tmplfs
+	ndoc.tmpfslst = f.tmpfslst
+	if len(f.parts) > 0 {
+		ndoc.parts = make(map[string][]byte, len(f.parts))
+		for name, data := range f.parts {
+			ndoc.parts[name] = data
+		}
+	}
+	ndoc.styles = f.root().styles // the styles defined in code are kept
+
+	ndoc.Document.XMLW = XMLNS_W
+	ndoc.Document.XMLR = XMLNS_R
+	ndoc.Document.XMLWP = XMLNS_WP
+	ndoc.Document.XMLMC = f.Document.XMLMC
+	ndoc.Document.MCIgnorable = f.Document.MCIgnorable
+	ndoc.Document.namespaces = f.Document.namespaces
+	// ndoc.Document.XMLO = XMLNS_O
+	// ndoc.Document.XMLV = XMLNS_V
+	ndoc.Document.XMLWPS = XMLNS_WPS
+	ndoc.Document.XMLWPC = XMLNS_WPC
+	ndoc.Document.XMLWPG = XMLNS_WPG
+	// ndoc.Document.XMLWP14 = XMLNS_WP14
+	ndoc.Document.XMLName.Space = XMLNS_W
+	ndoc.Document.XMLName.Local = "document"
+	ndoc.Document.Body.file = ndoc
+
+	ndoc.docRelation = Relationships{
+		Xmlns: XMLNS_REL,
+		Relationship: []Relationship{
+			{
+				ID:     "rId1",
+				Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles`,
+				Target: "styles.xml",
+			},
+			{
+				ID:     "rId2",
+				Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme`,
+				Target: "theme/theme1.xml",
+			},
+			{
+				ID:     "rId3",
+				Type:   `http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable`,
+				Target: "fontTable.xml",
+			},
+		},
+	}
+
+	ndoc.rID = 3
+
+	return ndoc
+}
+
 func (r *Run) copymedia(to *Docx) *Run {
 	nr := *r
 	nr.Children = make([]interface{}, 0, len(r.Children))
 	nr.file = to
+	if rp := r.RunProperties; rp != nil && (rp.Ins != nil || rp.Del != nil || rp.Change != nil) {
+		nr.RunProperties = rp.copyRevisions(r.file, to)
+	}
 	for _, rc := range r.Children {
-		if d, ok := rc.(*Drawing); ok {
+		switch d := rc.(type) {
+		case *Drawing:
 			nr.Children = append(nr.Children, d.copymedia(to))
-			continue
+		case *FootnoteReference:
+			nr.Children = append(nr.Children, d.copymedia(r.file, to))
+		case *EndnoteReference:
+			nr.Children = append(nr.Children, d.copymedia(r.file, to))
+		case *CommentReference:
+			nr.Children = append(nr.Children, &CommentReference{ID: to.copyComment(r.file, d.ID)})
+		default:
+			if copyable(rc) {
+				nr.Children = append(nr.Children, rc)
+			}
 		}
-		nr.Children = append(nr.Children, rc)
 	}
 	return &nr
 }
@@ -319,24 +453,60 @@ func (p *Paragraph) copymedia(to *Docx) (np Paragraph) {
 	np = *p
 	np.Children = make([]interface{}, 0, len(p.Children))
 	np.file = to
-	for _, pc := range p.Children {
-		if r, ok := pc.(*Run); ok {
-			np.Children = append(np.Children, r.copymedia(to))
-			continue
+	np.attrs = copyAttrs(p.attrs)
+	if p.Properties != nil && p.Properties.NumProperties != nil && p.Properties.NumProperties.NumID != nil {
+		num := p.Properties.NumProperties
+		if id := to.copyNum(p.file, num.NumID.Val); id != num.NumID.Val {
+			pp := *p.Properties
+			pp.NumProperties = &NumProperties{NumID: &NumID{Val: id}, Ilvl: num.Ilvl}
+			np.Properties = &pp
 		}
-		if h, ok := pc.(*Hyperlink); ok {
-			tgt, err := p.file.ReferTarget(h.ID)
-			if err != nil {
-				continue
+	}
+	if pp := np.Properties; pp != nil && (pp.Change != nil || pp.RunProperties != nil) {
+		npp := *pp
+		if pp.Change != nil {
+			c := *pp.Change
+			c.ID = to.copyID(p.file, ID_REVISION, c.ID)
+			npp.Change = &c
+		}
+		if rp := pp.RunProperties; rp != nil && (rp.Ins != nil || rp.Del != nil || rp.Change != nil) {
+			npp.RunProperties = rp.copyRevisions(p.file, to)
+		}
+		np.Properties = &npp
+	}
+	for _, pc := range p.Children {
+		switch o := pc.(type) {
+		case *Run:
+			np.Children = append(np.Children, o.copymedia(to))
+		case *Hyperlink:
+			nh := o.copymedia(p.file, to)
+			if nh != nil {
+				np.Children = append(np.Children, nh)
+			}
+		case *Revision:
+			nrv := *o
+			nrv.ID = to.copyID(p.file, ID_REVISION, o.ID)
+			rp := Paragraph{Children: o.Children, file: p.file}
+			nrv.Children = rp.copymedia(to).Children
+			nrv.file = to
+			np.Children = append(np.Children, &nrv)
+		case *MoveRangeStart:
+			nm := *o
+			nm.ID = to.copyID(p.file, ID_REVISION, o.ID)
+			np.Children = append(np.Children, &nm)
+		case *MoveRangeEnd:
+			nm := *o
+			nm.ID = to.copyID(p.file, ID_REVISION, o.ID)
+			np.Children = append(np.Children, &nm)
+		case *CommentRangeStart:
+			np.Children = append(np.Children, &CommentRangeStart{ID: to.copyComment(p.file, o.ID)})
+		case *CommentRangeEnd:
+			np.Children = append(np.Children, &CommentRangeEnd{ID: to.copyComment(p.file, o.ID)})
+		case *rawElement:
+			if copyable(o) {
+				np.Children = append(np.Children, o.copyBookmark(p.file, to))
 			}
-			rid := to.addLinkRelation(tgt)
-			np.Children = append(np.Children, &Hyperlink{
-				ID:  rid,
-				Run: *h.Run.copymedia(to),
-			})
-			continue
 		}
-		np.Children = append(np.Children, pc)
 	}
 	return
 }
@@ -345,14 +515,18 @@ func (t *Table) copymedia(to *Docx) (nt Table) {
 	nt = *t
 	nt.Rows = make([]*WTableRow, 0, len(t.Rows))
 	nt.file = to
+	nt.items = copyKept(t.items)
 	for _, tr := range t.Rows {
 		ntr := *tr
 		ntr.Cells = make([]*WTableCell, 0, len(tr.Cells))
 		ntr.file = to
+		ntr.attrs = copyAttrs(tr.attrs)
+		ntr.items = copyKept(tr.items)
 		for _, tc := range tr.Cells {
 			ntc := *tc
 			ntc.Paragraphs = make([]*Paragraph, 0, len(tc.Paragraphs))
 			ntc.file = to
+			ntc.items = copyKept(tc.items)
 			for _, p := range tc.Paragraphs {
 				np := p.copymedia(to)
 				ntc.Paragraphs = append(ntc.Paragraphs, &np)
@@ -364,18 +538,45 @@ func (t *Table) copymedia(to *Docx) (nt Table) {
 	return
 }
 
+// copyAttrs returns the kept attributes of a copied paragraph or row
+// without their ids, which must stay unique in the document
+func copyAttrs(attrs []xml.Attr) []xml.Attr {
+	var kept []xml.Attr
+	for _, a := range attrs {
+		if a.Name.Local != "w14:paraId" && a.Name.Local != "w14:textId" {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
 // AppendFile appends all contents in af to f
 func (f *Docx) AppendFile(af *Docx) {
-	for _, item := range af.Document.Body.Items {
+	f.Document.Body.Items = append(f.Document.Body.Items, f.copyItems(af, af.Document.Body.Items)...)
+}
+
+// copyItems returns the copies in f of the body items of af
+func (f *Docx) copyItems(af *Docx, items []interface{}) []interface{} {
+	copies := make([]interface{}, 0, len(items))
+	for _, item := range items {
 		switch o := item.(type) {
 		case *Paragraph:
 			np := o.copymedia(f)
-			f.Document.Body.Items = append(f.Document.Body.Items, &np)
+			copies = append(copies, &np)
 		case *Table:
 			nt := o.copymedia(f)
-			f.Document.Body.Items = append(f.Document.Body.Items, &nt)
+			copies = append(copies, &nt)
+		case *SectPr:
+			copies = append(copies, o.copymedia(f))
+		case *rawElement:
+			if copyable(o) {
+				copies = append(copies, o.copyBookmark(af, f))
+			}
 		default:
-			f.Document.Body.Items = append(f.Document.Body.Items, o)
+			if copyable(o) {
+				copies = append(copies, o)
+			}
 		}
 	}
+	return copies
 }
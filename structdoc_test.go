@@ -22,6 +22,7 @@ package docx
 
 import (
 	"encoding/xml"
+	"strings"
 	"testing"
 )
 
@@ -34,7 +35,7 @@ func TestUnmarshalPlainStructure(t *testing.T) {
 		numParagraphs int
 	}{
 		{decoded_doc_1, 6},
-		{decoded_doc_2, 15},
+		{decoded_doc_2, 16}, // the table of contents <w:sdt> is kept
 	}
 	for _, tc := range testCases {
 		doc := Document{
@@ -71,3 +72,40 @@ func TestUnmarshalPlainStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestIgnorableOtherPrefix(t *testing.T) {
+	// Word 2007 declares the markup compatibility namespace as ve
+	const root = ` xmlns:ve="` + XMLNS_MC + `" xmlns:w="` + XMLNS_W + `" xmlns:w14="` + XMLNS_W14 + `" ve:Ignorable="w14">`
+	for _, it := range []struct {
+		v    interface{}
+		data string
+	}{
+		{&Document{}, `<w:document` + root + `<w:body><w:p w14:paraId="0A1B2C3D"></w:p></w:body></w:document>`},
+		{&Header{}, `<w:hdr` + root + `<w:p w14:paraId="0A1B2C3D"></w:p></w:hdr>`},
+	} {
+		err := xml.Unmarshal(StringToBytes(it.data), it.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := xml.Marshal(it.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := string(out)
+		if !strings.Contains(s, `mc:Ignorable="w14"`) || !strings.Contains(s, `xmlns:mc="`+XMLNS_MC+`"`) {
+			t.Fatalf("mc is not declared along with mc:Ignorable in %s", s)
+		}
+		d := xml.NewDecoder(strings.NewReader(s))
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, a := range tok.(xml.StartElement).Attr {
+			found = found || a.Name.Space == XMLNS_MC && a.Name.Local == "Ignorable"
+		}
+		if !found {
+			t.Fatalf("mc:Ignorable is not in its namespace in %s", s)
+		}
+	}
+}
@@ -21,13 +21,13 @@
 package docx
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/xml"
 	"io"
 	"strconv"
 	"strings"
-	"sync/atomic"
 )
 
 //nolint:revive,stylecheck
@@ -48,9 +48,36 @@ type Drawing struct {
 	Inline  *WPInline
 	Anchor  *WPAnchor
 
+	raw   *rawElement // raw is the element as read
+	model []byte      // model is the model of raw as written when read
+
 	file *Docx
 }
 
+// drawingModel is a Drawing written from its model
+type drawingModel Drawing
+
+// keep keeps raw which is written back while the model is not changed
+func (r *Drawing) keep(raw *rawElement) error {
+	model, err := xml.Marshal((*drawingModel)(r))
+	if err != nil {
+		return err
+	}
+	r.raw, r.model = raw, model
+	return nil
+}
+
+// MarshalXML writes the element as read if the model is not changed
+func (r *Drawing) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	if r.raw != nil {
+		model, err := xml.Marshal((*drawingModel)(r))
+		if err == nil && bytes.Equal(model, r.model) {
+			return e.Encode(r.raw)
+		}
+	}
+	return e.Encode((*drawingModel)(r))
+}
+
 // UnmarshalXML ...
 func (r *Drawing) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	for {
@@ -68,14 +95,14 @@ func (r *Drawing) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				r.Inline = new(WPInline)
 				r.Inline.file = r.file
 				err = d.DecodeElement(r.Inline, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "anchor":
 				r.Anchor = new(WPAnchor)
 				r.Anchor.file = r.file
 				err = d.DecodeElement(r.Anchor, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -195,13 +222,13 @@ func (r *WPInline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err err
 			case "docPr":
 				r.DocPr = new(WPDocPr)
 				err = d.DecodeElement(r.DocPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "cNvGraphicFramePr":
 				var value WPCNvGraphicFramePr
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.CNvGraphicFramePr = &value
@@ -209,7 +236,7 @@ func (r *WPInline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err err
 				var value AGraphic
 				value.file = r.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Graphic = &value
@@ -243,8 +270,10 @@ func (r *WPInline) String() string {
 			tgt, err := r.file.ReferTarget(r.Graphic.GraphicData.Pic.BlipFill.Blip.Embed)
 			if err != nil {
 				sb.WriteString(err.Error())
+			} else if data, err := r.file.Media(tgt[6:]).Bytes(); err != nil {
+				sb.WriteString(err.Error())
 			} else {
-				h := md5.Sum(r.file.Media(tgt[6:]).Data)
+				h := md5.Sum(data)
 				sb.WriteString(hex.EncodeToString(h[:]))
 			}
 		}
@@ -291,14 +320,17 @@ func (r *WPInline) copymedia(to *Docx) *WPInline { //nolint: dupl
 				return nil
 			}
 			format := tgt[strings.LastIndex(tgt, ".")+1:]
-			idn := int(atomic.AddUintptr(&to.docID, 1))
-			id := int(to.IncreaseID("图片"))
-			ids := strconv.Itoa(id)
+			idn := to.NewID(ID_DRAWING)
+			ids := strconv.Itoa(idn)
 			m := r.file.Media(tgt[6:])
 			if m == nil {
 				return nil
 			}
-			rid := to.addImage(format, m.Data)
+			data, err := m.Bytes()
+			if err != nil {
+				return nil
+			}
+			rid := to.addImage(format, data)
 			inln := *r
 			grph := *r.Graphic
 			inln.Graphic = &grph
@@ -316,7 +348,7 @@ func (r *WPInline) copymedia(to *Docx) *WPInline { //nolint: dupl
 			}
 			pic.NonVisualPicProperties = &PICNonVisualPicProperties{
 				NonVisualDrawingProperties: NonVisualProperties{
-					ID:   id,
+					ID:   idn,
 					Name: "图片 " + ids,
 				},
 				CNvPicPr: r.Graphic.GraphicData.Pic.NonVisualPicProperties.CNvPicPr,
@@ -450,14 +482,19 @@ func (w *WPCNvGraphicFramePr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
 			case "graphicFrameLocks":
-				w.Locks.XMLA = getAtt(tt.Attr, "a")
-				v := getAtt(tt.Attr, "noChangeAspect")
-				if v == "" {
-					continue
-				}
-				w.Locks.NoChangeAspect, err = GetInt(v)
-				if err != nil {
-					return err
+				for _, attr := range tt.Attr {
+					switch attr.Name.Local {
+					case "a":
+						w.Locks.XMLA = attr.Value
+					case "noChangeAspect":
+						if attr.Value == "" {
+							continue
+						}
+						w.Locks.NoChangeAspect, err = GetInt(attr.Value)
+						if err != nil {
+							return err
+						}
+					}
 				}
 			default:
 				err = d.Skip() // skip unsupported tags
@@ -512,7 +549,7 @@ func (a *AGraphic) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 				var value AGraphicData
 				value.file = a.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !a.file.tolerated(err) {
 					return err
 				}
 				value.URI = getAtt(tt.Attr, "uri")
@@ -565,8 +602,9 @@ func (a *AGraphicData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 			switch tt.Name.Local {
 			case "pic":
 				var value Picture
+				value.file = a.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !a.file.tolerated(err) {
 					return err
 				}
 				value.XMLPIC = getAtt(tt.Attr, "pic")
@@ -575,7 +613,7 @@ func (a *AGraphicData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 				var value WordprocessingShape
 				value.file = a.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !a.file.tolerated(err) {
 					return err
 				}
 				a.Shape = &value
@@ -583,7 +621,7 @@ func (a *AGraphicData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 				var value WordprocessingCanvas
 				value.file = a.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !a.file.tolerated(err) {
 					return err
 				}
 				a.Canvas = &value
@@ -591,7 +629,7 @@ func (a *AGraphicData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 				var value WordprocessingGroup
 				value.file = a.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !a.file.tolerated(err) {
 					return err
 				}
 				a.Group = &value
@@ -614,6 +652,8 @@ type Picture struct {
 	NonVisualPicProperties *PICNonVisualPicProperties
 	BlipFill               *PICBlipFill
 	SpPr                   *PICSpPr
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -631,22 +671,25 @@ func (p *Picture) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			switch tt.Name.Local {
 			case "nvPicPr":
 				var value PICNonVisualPicProperties
+				value.file = p.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.NonVisualPicProperties = &value
 			case "blipFill":
 				var value PICBlipFill
+				value.file = p.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.BlipFill = &value
 			case "spPr":
 				var value PICSpPr
+				value.file = p.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.SpPr = &value
@@ -667,6 +710,8 @@ type PICNonVisualPicProperties struct {
 	XMLName                    xml.Name            `xml:"pic:nvPicPr,omitempty"`
 	NonVisualDrawingProperties NonVisualProperties `xml:"pic:cNvPr,omitempty"`
 	CNvPicPr                   PicCNvPicPr
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -694,7 +739,7 @@ func (p *PICNonVisualPicProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElem
 				p.NonVisualDrawingProperties.Name = getAtt(tt.Attr, "name")
 			case "cNvPicPr":
 				err = d.DecodeElement(&p.CNvPicPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -764,6 +809,8 @@ type PICBlipFill struct {
 	XMLName xml.Name `xml:"pic:blipFill,omitempty"`
 	Blip    ABlip
 	Stretch AStretch
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -781,12 +828,13 @@ func (p *PICBlipFill) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			switch tt.Name.Local {
 			case "blip":
 				err = d.DecodeElement(&p.Blip, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			case "stretch":
+				p.Stretch.file = p.file
 				err = d.DecodeElement(&p.Stretch, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -865,6 +913,8 @@ type AAlphaModFix struct {
 type AStretch struct {
 	XMLName  xml.Name `xml:"a:stretch,omitempty"`
 	FillRect *AFillRect
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -883,7 +933,7 @@ func (s *AStretch) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "fillRect":
 				var value AFillRect
 				/*err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !s.file.tolerated(err) {
 					return err
 				}*/
 				s.FillRect = &value
@@ -914,6 +964,8 @@ type PICSpPr struct {
 	XMLName  xml.Name `xml:"pic:spPr,omitempty"`
 	Xfrm     AXfrm
 	PrstGeom *APrstGeom
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -931,13 +983,13 @@ func (p *PICSpPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			switch tt.Name.Local {
 			case "xfrm":
 				err = d.DecodeElement(&p.Xfrm, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			case "prstGeom":
 				var value APrstGeom
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.PrstGeom = &value
@@ -1253,28 +1305,30 @@ func (r *WPAnchor) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err err
 				}
 			case "positionH":
 				r.PositionH = new(WPPositionH)
+				r.PositionH.file = r.file
 				// r.PositionH.RelativeFrom = getAtt(tt.Attr, "relativeFrom")
 				err = d.DecodeElement(&r.PositionH, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "positionV":
 				r.PositionV = new(WPPositionV)
+				r.PositionV.file = r.file
 				// r.PositionV.RelativeFrom = getAtt(tt.Attr, "relativeFrom")
 				err = d.DecodeElement(&r.PositionV, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "extent":
 				r.Extent = new(WPExtent)
 				err = d.DecodeElement(&r.Extent, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "effectExtent":
 				r.EffectExtent = new(WPEffectExtent)
 				err = d.DecodeElement(&r.EffectExtent, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "wrapNone":
@@ -1285,20 +1339,20 @@ func (r *WPAnchor) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err err
 			case "docPr":
 				r.DocPr = new(WPDocPr)
 				err = d.DecodeElement(r.DocPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "cNvGraphicFramePr":
 				r.CNvGraphicFramePr = new(WPCNvGraphicFramePr)
 				err = d.DecodeElement(r.CNvGraphicFramePr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			case "graphic":
 				r.Graphic = new(AGraphic)
 				r.Graphic.file = r.file
 				err = d.DecodeElement(&r.Graphic, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -1332,8 +1386,10 @@ func (r *WPAnchor) String() string {
 				tgt, err := r.file.ReferTarget(r.Graphic.GraphicData.Pic.BlipFill.Blip.Embed)
 				if err != nil {
 					sb.WriteString(err.Error())
+				} else if data, err := r.file.Media(tgt[6:]).Bytes(); err != nil {
+					sb.WriteString(err.Error())
 				} else {
-					h := md5.Sum(r.file.Media(tgt[6:]).Data)
+					h := md5.Sum(data)
 					sb.WriteString(hex.EncodeToString(h[:]))
 				}
 			}
@@ -1381,14 +1437,17 @@ func (r *WPAnchor) copymedia(to *Docx) *WPAnchor { //nolint: dupl
 				return nil
 			}
 			format := tgt[strings.LastIndex(tgt, ".")+1:]
-			idn := int(atomic.AddUintptr(&to.docID, 1))
-			id := int(to.IncreaseID("图片"))
-			ids := strconv.Itoa(id)
+			idn := to.NewID(ID_DRAWING)
+			ids := strconv.Itoa(idn)
 			m := r.file.Media(tgt[6:])
 			if m == nil {
 				return nil
 			}
-			rid := to.addImage(format, m.Data)
+			data, err := m.Bytes()
+			if err != nil {
+				return nil
+			}
+			rid := to.addImage(format, data)
 			anch := *r
 			grph := *r.Graphic
 			anch.Graphic = &grph
@@ -1406,7 +1465,7 @@ func (r *WPAnchor) copymedia(to *Docx) *WPAnchor { //nolint: dupl
 			}
 			pic.NonVisualPicProperties = &PICNonVisualPicProperties{
 				NonVisualDrawingProperties: NonVisualProperties{
-					ID:   id,
+					ID:   idn,
 					Name: "图片 " + ids,
 				},
 				CNvPicPr: r.Graphic.GraphicData.Pic.NonVisualPicProperties.CNvPicPr,
@@ -1443,6 +1502,8 @@ type WPPositionH struct {
 	XMLName      xml.Name `xml:"wp:positionH,omitempty"`
 	RelativeFrom string   `xml:"relativeFrom,attr"`
 	PosOffset    int64    `xml:"wp:posOffset"`
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -1466,7 +1527,7 @@ func (r *WPPositionH) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 			switch tt.Name.Local {
 			case "posOffset":
 				err = d.DecodeElement(&r.PosOffset, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -1486,6 +1547,8 @@ type WPPositionV struct {
 	XMLName      xml.Name `xml:"wp:positionV,omitempty"`
 	RelativeFrom string   `xml:"relativeFrom,attr"`
 	PosOffset    int64    `xml:"wp:posOffset"`
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -1509,7 +1572,7 @@ func (r *WPPositionV) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 			switch tt.Name.Local {
 			case "posOffset":
 				err = d.DecodeElement(&r.PosOffset, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 			default:
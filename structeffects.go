@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"strings"
 )
 
 // RunStyle contains styling for a run
@@ -43,6 +42,8 @@ type Style struct {
 type Color struct {
 	XMLName xml.Name `xml:"w:color,omitempty"`
 	Val     string   `xml:"w:val,attr"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (themeColor...)
 }
 
 // Size contains the font size
@@ -60,17 +61,21 @@ type SizeCs struct {
 // Bold ...
 type Bold struct {
 	XMLName xml.Name `xml:"w:b,omitempty"`
+	Val     string   `xml:"w:val,attr,omitempty"` // Val is empty or "1" when on, "0" when off
 }
 
 // Italic ...
 type Italic struct {
 	XMLName xml.Name `xml:"w:i,omitempty"`
+	Val     string   `xml:"w:val,attr,omitempty"` // Val is empty or "1" when on, "0" when off
 }
 
 // Underline ...
 type Underline struct {
 	XMLName xml.Name `xml:"w:u,omitempty"`
 	Val     string   `xml:"w:val,attr,omitempty"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (color...)
 }
 
 // Highlight ...
@@ -120,6 +125,8 @@ type Strike struct {
 type Lang struct {
 	XMLName xml.Name `xml:"w:lang,omitempty"`
 	Val     string   `xml:"w:val,attr"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (eastAsia, bidi)
 }
 
 // Lang ...
@@ -135,6 +142,8 @@ type Shade struct {
 	Fill          string   `xml:"w:fill,attr,omitempty"`
 	ThemeFill     string   `xml:"w:themeFill,attr,omitempty"`
 	ThemeFillTint string   `xml:"w:themeFillTint,attr,omitempty"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read
 }
 
 // UnmarshalXML ...
@@ -155,6 +164,7 @@ func (s *Shade) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			// ignore other attributes
 		}
 	}
+	s.attrs = extraAttrs(start.Attr, "val", "color", "fill", "themeFill", "themeFillTint")
 	// Consume the end element
 	_, err := d.Token()
 	return err
@@ -197,6 +207,8 @@ type ShapeProperties struct {
 
 	// EffectList struct{} `xml:"a:effectLst"`
 	// ExtList    struct{} `xml:"a:extLst"`
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -222,7 +234,7 @@ func (w *ShapeProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 			switch tt.Name.Local {
 			case "xfrm":
 				err = d.DecodeElement(&w.Xfrm, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "prstGeom":
@@ -230,14 +242,15 @@ func (w *ShapeProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 			case "solidFill":
 				var value ASolidFill
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.SolidFill = &value
 			case "blipFill":
 				var value ABlipFill
+				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.BlipFill = &value
@@ -246,7 +259,7 @@ func (w *ShapeProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 			case "ln":
 				var ln ALine
 				err = d.DecodeElement(&ln, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Line = &ln
@@ -298,6 +311,8 @@ type Spacing struct {
 	Before      int    `xml:"w:before,attr,omitempty"`
 	Line        int    `xml:"w:line,attr,omitempty"`
 	LineRule    string `xml:"w:lineRule,attr,omitempty"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (after...)
 }
 
 // UnmarshalXML ...
@@ -330,6 +345,7 @@ func (s *Spacing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err erro
 			// ignore other attributes
 		}
 	}
+	s.attrs = extraAttrs(start.Attr, "val", "beforeLines", "before", "line", "lineRule")
 	// Consume the end element
 	_, err = d.Token()
 	return
@@ -345,6 +361,8 @@ type Ind struct {
 	FirstLine      int `xml:"w:firstLine,attr,omitempty"`
 	HangingChars   int `xml:"w:hangingChars,attr,omitempty"`
 	Hanging        int `xml:"w:hanging,attr,omitempty"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (right...)
 }
 
 // UnmarshalXML ...
@@ -403,7 +421,62 @@ func (i *Ind) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
 			// ignore other attributes
 		}
 	}
+	i.attrs = extraAttrs(start.Attr, "leftChars", "left", "firstLineChars", "firstLine", "hangingChars", "hanging")
 	// Consume the end element
 	_, err = d.Token()
 	return
 }
+
+// MarshalXML writes the kept attributes
+func (c *Color) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _color Color
+	return e.EncodeElement(struct {
+		*_color
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_color)(c), c.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (l *Lang) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _lang Lang
+	return e.EncodeElement(struct {
+		*_lang
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_lang)(l), l.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (u *Underline) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _underline Underline
+	return e.EncodeElement(struct {
+		*_underline
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_underline)(u), u.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (s *Shade) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _shade Shade
+	return e.EncodeElement(struct {
+		*_shade
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_shade)(s), s.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (s *Spacing) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _spacing Spacing
+	return e.EncodeElement(struct {
+		*_spacing
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_spacing)(s), s.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (i *Ind) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _ind Ind
+	return e.EncodeElement(struct {
+		*_ind
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_ind)(i), i.attrs}, start)
+}
@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"strings"
 )
 
 // WordprocessingGroup represents a group of drawing objects or pictures
@@ -52,21 +51,23 @@ func (w *WordprocessingGroup) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 			case "cNvGrpSpPr":
 				var value WPGcNvGrpSpPr
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.CNvGrpSpPr = &value
 			case "grpSpPr":
 				var value ShapeProperties
+				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.GroupShapeProperties = &value
 			case "pic":
 				var value Picture
+				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -74,7 +75,7 @@ func (w *WordprocessingGroup) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				var value WordprocessingShape
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -82,7 +83,7 @@ func (w *WordprocessingGroup) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				var value WordprocessingCanvas
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -90,7 +91,7 @@ func (w *WordprocessingGroup) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				var value WPGGroupShape
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -175,28 +176,30 @@ func (w *WPGGroupShape) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "cNvPr":
 				var value NonVisualProperties
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.CNvPr = &value
 			case "cNvGrpSpPr":
 				var value WPGcNvGrpSpPr
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.CNvGrpSpPr = &value
 			case "grpSpPr":
 				var value ShapeProperties
+				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.GroupShapeProperties = &value
 			case "pic":
 				var value Picture
+				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -204,7 +207,7 @@ func (w *WPGGroupShape) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				var value WordprocessingShape
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
@@ -212,7 +215,7 @@ func (w *WPGGroupShape) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				var value WordprocessingCanvas
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Elems = append(w.Elems, &value)
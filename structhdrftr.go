@@ -0,0 +1,149 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+)
+
+// Header is a header part (word/headerN.xml) <w:hdr>
+//
+// It holds the same items as the Body: *Paragraph and *Table
+type Header struct {
+	XMLName xml.Name `xml:"w:hdr"`
+	XMLW    string   `xml:"xmlns:w,attr"`
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"`
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"`
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"`
+
+	Items []interface{}
+
+	name       string     // name is the part name, e.g. word/header1.xml
+	file       *Docx      // file owns the relationships of the part
+	namespaces []xml.Attr // namespaces are the other declarations of the root (xmlns:w14, mc:Ignorable...)
+}
+
+// UnmarshalXML ...
+func (h *Header) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	h.namespaces = rootAttrs(start, map[string]*string{
+		"w": &h.XMLW, "r": &h.XMLR, "wp": &h.XMLWP, "wps": &h.XMLWPS, "wpc": &h.XMLWPC, "wpg": &h.XMLWPG,
+	})
+	b := Body{file: h.file}
+	err := b.UnmarshalXML(d, start)
+	h.Items = b.Items
+	return err
+}
+
+// MarshalXML writes the namespaces declared on the root
+func (h *Header) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type _h Header
+	return e.Encode(struct {
+		*_h
+		Namespaces []xml.Attr `xml:",any,attr"`
+	}{(*_h)(h), h.namespaces})
+}
+
+// Footer is a footer part (word/footerN.xml) <w:ftr>
+//
+// It holds the same items as the Body: *Paragraph and *Table
+type Footer struct {
+	XMLName xml.Name `xml:"w:ftr"`
+	XMLW    string   `xml:"xmlns:w,attr"`
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"`
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"`
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"`
+
+	Items []interface{}
+
+	name       string     // name is the part name, e.g. word/footer1.xml
+	file       *Docx      // file owns the relationships of the part
+	namespaces []xml.Attr // namespaces are the other declarations of the root (xmlns:w14, mc:Ignorable...)
+}
+
+// UnmarshalXML ...
+func (h *Footer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	h.namespaces = rootAttrs(start, map[string]*string{
+		"w": &h.XMLW, "r": &h.XMLR, "wp": &h.XMLWP, "wps": &h.XMLWPS, "wpc": &h.XMLWPC, "wpg": &h.XMLWPG,
+	})
+	b := Body{file: h.file}
+	err := b.UnmarshalXML(d, start)
+	h.Items = b.Items
+	return err
+}
+
+// MarshalXML writes the namespaces declared on the root
+func (h *Footer) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type _h Footer
+	return e.Encode(struct {
+		*_h
+		Namespaces []xml.Attr `xml:",any,attr"`
+	}{(*_h)(h), h.namespaces})
+}
+
+// HeaderReference <w:headerReference> links a section to a header part
+type HeaderReference struct {
+	XMLName xml.Name `xml:"w:headerReference,omitempty"`
+	Type    string   `xml:"w:type,attr"`
+	ID      string   `xml:"r:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *HeaderReference) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "type":
+			r.Type = attr.Value
+		case "id":
+			r.ID = attr.Value
+		}
+	}
+	// Consume the end element
+	_, err := d.Token()
+	return err
+}
+
+// FooterReference <w:footerReference> links a section to a footer part
+type FooterReference struct {
+	XMLName xml.Name `xml:"w:footerReference,omitempty"`
+	Type    string   `xml:"w:type,attr"`
+	ID      string   `xml:"r:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *FooterReference) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "type":
+			r.Type = attr.Value
+		case "id":
+			r.ID = attr.Value
+		}
+	}
+	// Consume the end element
+	_, err := d.Token()
+	return err
+}
+
+// TitlePg <w:titlePg> enables the first page header and footer of a section
+type TitlePg struct {
+	XMLName xml.Name `xml:"w:titlePg,omitempty"`
+}
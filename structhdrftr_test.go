@@ -0,0 +1,158 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFooterRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddText("body")
+	s := w.SectPr()
+	s.AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("default header")
+	s.AddHeader(HEADER_FOOTER_FIRST).AddParagraph().AddText("first header")
+	s.AddFooter(HEADER_FOOTER_EVEN).AddParagraph().AddText("even footer")
+	s.AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddLink("link", "https://example.com")
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Headers()) != 2 || len(doc.Footers()) != 1 {
+		t.Fatalf("expected 2 headers and 1 footer, got %d and %d", len(doc.Headers()), len(doc.Footers()))
+	}
+	s = doc.SectPr()
+	if s.TitlePg == nil {
+		t.Fatal("titlePg was not saved")
+	}
+	if !doc.Settings().EvenAndOddHeaders {
+		t.Fatal("evenAndOddHeaders was not saved")
+	}
+	h := s.Header(HEADER_FOOTER_DEFAULT)
+	if h == nil || len(h.Items) != 2 {
+		t.Fatal("default header was not parsed")
+	}
+	if txt := h.Items[0].(*Paragraph).String(); txt != "default header" {
+		t.Fatalf("unexpected default header text %q", txt)
+	}
+	lnk, ok := h.Items[1].(*Paragraph).Children[0].(*Hyperlink)
+	if !ok {
+		t.Fatal("header link was not parsed")
+	}
+	tgt, err := h.Items[1].(*Paragraph).file.ReferTarget(lnk.ID)
+	if err != nil || tgt != "https://example.com" {
+		t.Fatal("header link relationship was not saved")
+	}
+	if f := s.Footer(HEADER_FOOTER_EVEN); f == nil || f.Items[0].(*Paragraph).String() != "even footer" {
+		t.Fatal("even footer was not parsed")
+	}
+	if s.Header(HEADER_FOOTER_EVEN) != nil {
+		t.Fatal("unexpected even header")
+	}
+
+	ct := doc.contentTypes
+	n := 0
+	for _, o := range ct.Overrides {
+		if o.ContentType == CT_HEADER || o.ContentType == CT_FOOTER {
+			if !strings.HasPrefix(o.PartName, "/word/") {
+				t.Fatal("invalid part name", o.PartName)
+			}
+			n++
+		}
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 header/footer overrides, got %d", n)
+	}
+
+	docs := doc.SplitByParagraph(func(*Paragraph) bool { return false })
+	if len(docs) != 1 || len(docs[0].Headers()) != 2 || docs[0].SectPr().Footer(HEADER_FOOTER_EVEN) == nil {
+		t.Fatal("headers and footers were not copied")
+	}
+}
+
+const decoded_header = `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" ` +
+	`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" ` +
+	`mc:Ignorable="w14"><w:p w14:paraId="0A1B2C3D"><w:r><w:t>header</w:t></w:r></w:p></w:hdr>`
+
+func TestHeaderNamespaces(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddText("body")
+	w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("header")
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := rewriteZip(t, buf.Bytes(), func(name, content string) string {
+		if name == "word/header1.xml" {
+			return xml.Header + decoded_header
+		}
+		return content
+	})
+
+	doc, err := Parse(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	_, err = doc.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := zr.Open("word/header1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, frag := range []string{
+		`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"`,
+		`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"`,
+		`mc:Ignorable="w14"`,
+		`<w:p w14:paraId="0A1B2C3D">`,
+	} {
+		if !strings.Contains(string(out), frag) {
+			t.Errorf("%s not found in %s", frag, out)
+		}
+	}
+
+	var s SectPr
+	if s.AddHeader(HEADER_FOOTER_DEFAULT) != nil || s.AddFooter(HEADER_FOOTER_EVEN) != nil {
+		t.Fatal("unexpected header of a section out of a document")
+	}
+}
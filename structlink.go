@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"strings"
 )
 
 // Hyperlink element contains links
@@ -31,10 +30,15 @@ type Hyperlink struct {
 	XMLName xml.Name `xml:"w:hyperlink,omitempty"`
 	ID      string   `xml:"r:id,attr"`
 	Run     Run
+
+	attrs []xml.Attr    // attrs are the attributes kept as read (w:anchor, w:history...)
+	items []interface{} // items are the children as read, nil standing for Run
 }
 
 // UnmarshalXML ...
-func (r *Hyperlink) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (r *Hyperlink) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	r.attrs = extraAttrs(start.Attr, "id")
+	hasRun := false
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -43,20 +47,95 @@ func (r *Hyperlink) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "r" && !hasRun {
+				err = r.Run.UnmarshalXML(d, tt)
+				if err != nil && !r.Run.file.tolerated(err) {
+					return err
+				}
+				hasRun = true
+				r.items = append(r.items, nil)
+				continue
+			}
 			if tt.Name.Local == "r" {
-				err = d.DecodeElement(&r.Run, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				var value Run
+				value.file = r.Run.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !r.Run.file.tolerated(err) {
 					return err
 				}
+				r.items = append(r.items, &value)
 				continue
 			}
-			err = d.Skip() // skip unsupported tags
+			raw, err := readUnknown(d, tt)
 			if err != nil {
 				return err
 			}
+			r.items = append(r.items, raw)
 		}
 	}
 	return nil
 }
+
+// MarshalXML ...
+func (r *Hyperlink) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "w:hyperlink"}
+	start.Attr = make([]xml.Attr, 0, 1+len(r.attrs))
+	// the internal links are read with their anchor as ID
+	if r.ID != "" && r.ID != getAtt(r.attrs, "w:anchor") {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "r:id"}, Value: r.ID})
+	}
+	start.Attr = append(start.Attr, r.attrs...)
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if len(r.items) == 0 {
+		err = e.Encode(&r.Run)
+	}
+	for _, item := range r.items {
+		if item == nil {
+			item = &r.Run
+		}
+		err = e.Encode(item)
+		if err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// copymedia copies the link of from to the document to,
+// nil is returned when its target is not found
+func (r *Hyperlink) copymedia(from, to *Docx) *Hyperlink {
+	nh := &Hyperlink{
+		ID:    r.ID,
+		Run:   *r.Run.copymedia(to),
+		attrs: r.attrs,
+	}
+	if r.ID != "" && r.ID != getAtt(r.attrs, "w:anchor") {
+		tgt, err := from.ReferTarget(r.ID)
+		if err != nil {
+			return nil
+		}
+		nh.ID = to.addLinkRelation(tgt)
+	}
+	for _, item := range r.items {
+		switch o := item.(type) {
+		case *Run:
+			nh.items = append(nh.items, o.copymedia(to))
+		default:
+			if item == nil || copyable(item) {
+				nh.items = append(nh.items, item)
+			}
+		}
+	}
+	return nh
+}
@@ -0,0 +1,224 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+//nolint:revive,stylecheck
+const (
+	NOTE_SEPARATOR              = "separator"
+	NOTE_CONTINUATION_SEPARATOR = "continuationSeparator"
+)
+
+// Notes is the footnotes part (word/footnotes.xml) <w:footnotes>
+// or the endnotes part (word/endnotes.xml) <w:endnotes>
+type Notes struct {
+	XMLName xml.Name
+	XMLW    string `xml:"xmlns:w,attr"`
+	XMLR    string `xml:"xmlns:r,attr,omitempty"`
+	XMLWP   string `xml:"xmlns:wp,attr,omitempty"`
+	XMLWPS  string `xml:"xmlns:wps,attr,omitempty"`
+	XMLWPC  string `xml:"xmlns:wpc,attr,omitempty"`
+	XMLWPG  string `xml:"xmlns:wpg,attr,omitempty"`
+
+	Notes []*Note
+
+	name       string     // name is the part name, e.g. word/footnotes.xml
+	file       *Docx      // file owns the relationships of the part
+	namespaces []xml.Attr // namespaces are the other declarations of the root (xmlns:w14, mc:Ignorable...)
+	items      []keptItem // items are the unsupported elements kept between the notes
+}
+
+// UnmarshalXML ...
+func (n *Notes) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	n.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	n.namespaces = rootAttrs(start, map[string]*string{
+		"w": &n.XMLW, "r": &n.XMLR, "wp": &n.XMLWP, "wps": &n.XMLWPS, "wpc": &n.XMLWPC, "wpg": &n.XMLWPG,
+	})
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "footnote", "endnote":
+				var value Note
+				value.file = n.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !n.file.tolerated(err) {
+					return err
+				}
+				n.Notes = append(n.Notes, &value)
+			default:
+				raw, err := readUnknown(d, tt)
+				if err != nil {
+					return err
+				}
+				n.items = append(n.items, keptItem{at: len(n.Notes), raw: raw})
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes the namespaces declared on the root
+// and the unsupported elements between the notes
+func (n *Notes) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: n.XMLName, Attr: make([]xml.Attr, 0, 6+len(n.namespaces))}
+	for _, ns := range []struct{ prefix, uri string }{
+		{"w", n.XMLW}, {"r", n.XMLR}, {"wp", n.XMLWP}, {"wps", n.XMLWPS}, {"wpc", n.XMLWPC}, {"wpg", n.XMLWPG},
+	} {
+		if ns.uri != "" {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + ns.prefix}, Value: ns.uri})
+		}
+	}
+	start.Attr = append(start.Attr, n.namespaces...)
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	next := 0
+	for i, note := range n.Notes {
+		next, err = encodeKept(e, n.items, next, i)
+		if err != nil {
+			return err
+		}
+		err = e.Encode(note)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = encodeKept(e, n.items, next, len(n.Notes))
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Note is a footnote <w:footnote> or an endnote <w:endnote>
+//
+// It holds the same items as the Body: *Paragraph and *Table
+type Note struct {
+	XMLName xml.Name
+	Type    string `xml:"w:type,attr,omitempty"`
+	ID      int    `xml:"w:id,attr"`
+
+	Items []interface{}
+
+	attrs []xml.Attr // attrs are the attributes kept as read
+	file  *Docx
+}
+
+// UnmarshalXML ...
+func (n *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	n.attrs = extraAttrs(start.Attr, "type", "id")
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "type":
+			n.Type = attr.Value
+		case "id":
+			n.ID, err = GetInt(attr.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	b := Body{file: n.file}
+	err = b.UnmarshalXML(d, start)
+	n.Items = b.Items
+	return err
+}
+
+// MarshalXML writes the attributes kept as read
+func (n *Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _n Note
+	start.Name = n.XMLName
+	start.Attr = mergeAttrs(start.Attr, n.attrs)
+	return e.EncodeElement((*_n)(n), start)
+}
+
+// FootnoteReference <w:footnoteReference> is the mark of a footnote in a run
+type FootnoteReference struct {
+	XMLName xml.Name `xml:"w:footnoteReference,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *FootnoteReference) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	v := getAtt(start.Attr, "id")
+	if v != "" {
+		r.ID, err = GetInt(v)
+		if err != nil {
+			return
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// EndnoteReference <w:endnoteReference> is the mark of an endnote in a run
+type EndnoteReference struct {
+	XMLName xml.Name `xml:"w:endnoteReference,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *EndnoteReference) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	v := getAtt(start.Attr, "id")
+	if v != "" {
+		r.ID, err = GetInt(v)
+		if err != nil {
+			return
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// FootnoteRef <w:footnoteRef> is the number of the footnote in the footnote itself
+type FootnoteRef struct {
+	XMLName xml.Name `xml:"w:footnoteRef,omitempty"`
+}
+
+// EndnoteRef <w:endnoteRef> is the number of the endnote in the endnote itself
+type EndnoteRef struct {
+	XMLName xml.Name `xml:"w:endnoteRef,omitempty"`
+}
+
+// Separator <w:separator> is the line between the text and the notes
+type Separator struct {
+	XMLName xml.Name `xml:"w:separator,omitempty"`
+}
+
+// ContinuationSeparator <w:continuationSeparator> is the line between
+// the text and the notes continued from the previous page
+type ContinuationSeparator struct {
+	XMLName xml.Name `xml:"w:continuationSeparator,omitempty"`
+}
@@ -0,0 +1,142 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestNotesRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("body")
+	p.AddFootnote("first footnote")
+	p.AddFootnote("second footnote").AddParagraph().AddText("continued")
+	w.AddParagraph().AddEndnote("an endnote")
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.footnotes.Notes) != 4 {
+		t.Fatalf("expected 2 separators and 2 footnotes, got %d notes", len(doc.footnotes.Notes))
+	}
+	notes := doc.Footnotes()
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 footnotes, got %d", len(notes))
+	}
+	if notes[0].String() != "first footnote" {
+		t.Fatalf("unexpected footnote text %q", notes[0].String())
+	}
+	if len(notes[1].Paragraphs()) != 2 || notes[1].String() != "second footnote\ncontinued" {
+		t.Fatalf("unexpected footnote text %q", notes[1].String())
+	}
+	if _, ok := notes[0].Paragraphs()[0].Children[0].(*Run).Children[0].(*FootnoteRef); !ok {
+		t.Fatal("footnoteRef was not parsed")
+	}
+
+	var ids []int
+	for _, c := range doc.Document.Body.Items[0].(*Paragraph).Children {
+		if r, ok := c.(*Run); ok {
+			for _, rc := range r.Children {
+				if ref, ok := rc.(*FootnoteReference); ok {
+					ids = append(ids, ref.ID)
+				}
+			}
+		}
+	}
+	if len(ids) != 2 || doc.Footnote(ids[0]) != notes[0] || doc.Footnote(ids[1]) != notes[1] {
+		t.Fatalf("footnote references were not parsed: %v", ids)
+	}
+
+	endnotes := doc.Endnotes()
+	if len(endnotes) != 1 || endnotes[0].String() != "an endnote" {
+		t.Fatal("endnote was not parsed")
+	}
+
+	docs := doc.SplitByParagraph(func(p *Paragraph) bool { return true })
+	if len(docs) != 2 || len(docs[0].Footnotes()) != 2 || len(docs[1].Endnotes()) != 1 {
+		t.Fatal("notes were not copied")
+	}
+	if docs[1].Footnotes() != nil {
+		t.Fatal("unexpected footnotes")
+	}
+}
+
+const decoded_footnotes = `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" ` +
+	`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" mc:Ignorable="w14">` +
+	`<w:footnote w:id="1"><w:p w14:paraId="0A1B2C3D"><w:r><w:t>note</w:t></w:r></w:p></w:footnote></w:footnotes>`
+
+func TestNotesNamespaces(t *testing.T) {
+	var n Notes
+	err := xml.Unmarshal(StringToBytes(decoded_footnotes), &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, frag := range []string{
+		`<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`,
+		`xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"`,
+		`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"`,
+		`mc:Ignorable="w14"`,
+		`<w:p w14:paraId="0A1B2C3D">`,
+	} {
+		if !strings.Contains(string(out), frag) {
+			t.Errorf("%s not found in %s", frag, out)
+		}
+	}
+}
+
+func TestNotesKeepUnknown(t *testing.T) {
+	const footnotes = `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml">` +
+		`<w:footnote w:id="1" w14:textId="0A1B2C3D"><w:p><w:r><w:t>one</w:t></w:r></w:p></w:footnote>` +
+		`<w:extra w:val="x"><w:inner/></w:extra>` +
+		`<w:footnote w:id="2"><w:p><w:r><w:t>two</w:t></w:r></w:p></w:footnote></w:footnotes>`
+	var n Notes
+	err := xml.Unmarshal(StringToBytes(footnotes), &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	for _, frag := range []string{
+		`w14:textId="0A1B2C3D"`,
+		`</w:footnote><w:extra w:val="x"><w:inner></w:inner></w:extra><w:footnote w:id="2">`,
+	} {
+		if !strings.Contains(s, frag) {
+			t.Errorf("%s not found in %s", frag, s)
+		}
+	}
+}
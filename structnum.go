@@ -20,6 +20,7 @@ package docx
 import (
 	"encoding/xml"
 	"io"
+	"strconv"
 )
 
 // NumProperties show the number properties
@@ -74,3 +75,405 @@ func (n *NumProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 
 	return nil
 }
+
+// Numbering is the numbering part (word/numbering.xml) <w:numbering>
+//
+// The picture bullets and the other elements which are not modelled
+// are kept as they are.
+type Numbering struct {
+	AbstractNums []*AbstractNum
+	Nums         []*Num
+
+	attrs []xml.Attr
+	items []*rawElement
+
+	name string // name is the part name, e.g. word/numbering.xml
+
+	file *Docx
+}
+
+// AbstractNum <w:abstractNum> is a list definition shared by the numbering instances
+type AbstractNum struct {
+	XMLName        xml.Name      `xml:"w:abstractNum"`
+	ID             int           `xml:"w:abstractNumId,attr"`
+	Nsid           *NumberingVal `xml:"w:nsid"`
+	MultiLevelType *NumberingVal `xml:"w:multiLevelType"`
+	Tmpl           *NumberingVal `xml:"w:tmpl"`
+	Name           *NumberingVal `xml:"w:name"`
+	StyleLink      *NumberingVal `xml:"w:styleLink"`
+	NumStyleLink   *NumberingVal `xml:"w:numStyleLink"`
+	Levels         []*Level
+
+	file *Docx
+}
+
+// Level <w:lvl> is the definition of a level of a list
+type Level struct {
+	XMLName        xml.Name      `xml:"w:lvl"`
+	Ilvl           int           `xml:"w:ilvl,attr"`
+	Tentative      string        `xml:"w:tentative,attr,omitempty"`
+	Start          *NumberingVal `xml:"w:start"`
+	NumFmt         *NumberingVal `xml:"w:numFmt"`
+	LvlRestart     *NumberingVal `xml:"w:lvlRestart"`
+	PStyle         *NumberingVal `xml:"w:pStyle"`
+	IsLgl          *NumberingVal `xml:"w:isLgl"`
+	Suff           *NumberingVal `xml:"w:suff"`
+	LvlText        *NumberingVal `xml:"w:lvlText"`
+	LvlPicBulletID *NumberingVal `xml:"w:lvlPicBulletId"`
+	LvlJc          *NumberingVal `xml:"w:lvlJc"`
+	Properties     *ParagraphProperties
+	RunProperties  *RunProperties
+
+	file *Docx
+}
+
+// Num <w:num> is a numbering instance referred by the paragraphs
+type Num struct {
+	XMLName       xml.Name      `xml:"w:num"`
+	ID            int           `xml:"w:numId,attr"`
+	AbstractNumID *NumberingVal `xml:"w:abstractNumId"`
+	LvlOverrides  []*LvlOverride
+
+	file *Docx
+}
+
+// LvlOverride <w:lvlOverride> overrides a level of the list definition for one instance
+type LvlOverride struct {
+	XMLName       xml.Name      `xml:"w:lvlOverride"`
+	Ilvl          int           `xml:"w:ilvl,attr"`
+	StartOverride *NumberingVal `xml:"w:startOverride"`
+	Level         *Level
+
+	file *Docx
+}
+
+// NumberingVal is a numbering element holding only a w:val attribute
+type NumberingVal struct {
+	Val string `xml:"w:val,attr,omitempty"`
+}
+
+// UnmarshalXML ...
+func (n *Numbering) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	ns := nsPrefixes(nil, start.Attr)
+	ns[XMLNS_W] = "w"
+	n.attrs = prefixAttrs(start.Attr, ns)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch {
+			case tt.Name.Space == XMLNS_W && tt.Name.Local == "abstractNum":
+				var value AbstractNum
+				value.file = n.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !n.file.tolerated(err) {
+					return err
+				}
+				n.AbstractNums = append(n.AbstractNums, &value)
+			case tt.Name.Space == XMLNS_W && tt.Name.Local == "num":
+				var value Num
+				value.file = n.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !n.file.tolerated(err) {
+					return err
+				}
+				n.Nums = append(n.Nums, &value)
+			default:
+				r, err := readRawElement(d, tt, ns)
+				if err != nil {
+					return err
+				}
+				n.items = append(n.items, r)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML ...
+func (n *Numbering) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: xml.Name{Local: "w:numbering"}, Attr: n.attrs}
+	if len(start.Attr) == 0 {
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "xmlns:w"}, Value: XMLNS_W}}
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	// the picture bullets come first, the other elements last
+	for _, item := range n.items {
+		if item.name() == "w:numPicBullet" {
+			err = e.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, a := range n.AbstractNums {
+		err = e.Encode(a)
+		if err != nil {
+			return err
+		}
+	}
+	for _, num := range n.Nums {
+		err = e.Encode(num)
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range n.items {
+		if item.name() != "w:numPicBullet" {
+			err = e.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML ...
+func (a *AbstractNum) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	a.ID, err = GetInt(getAtt(start.Attr, "abstractNumId"))
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "nsid":
+				a.Nsid = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "multiLevelType":
+				a.MultiLevelType = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "tmpl":
+				a.Tmpl = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "name":
+				a.Name = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "styleLink":
+				a.StyleLink = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "numStyleLink":
+				a.NumStyleLink = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "lvl":
+				var value Level
+				value.file = a.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !a.file.tolerated(err) {
+					return err
+				}
+				a.Levels = append(a.Levels, &value)
+				continue
+			}
+			err = d.Skip() // skip the content of the element
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML ...
+func (l *Level) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	l.Ilvl, err = GetInt(getAtt(start.Attr, "ilvl"))
+	if err != nil {
+		return err
+	}
+	l.Tentative = getAtt(start.Attr, "tentative")
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			v := &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			switch tt.Name.Local {
+			case "start":
+				l.Start = v
+			case "numFmt":
+				l.NumFmt = v
+			case "lvlRestart":
+				l.LvlRestart = v
+			case "pStyle":
+				l.PStyle = v
+			case "isLgl":
+				l.IsLgl = v
+			case "suff":
+				l.Suff = v
+			case "lvlText":
+				l.LvlText = v
+			case "lvlPicBulletId":
+				l.LvlPicBulletID = v
+			case "lvlJc":
+				l.LvlJc = v
+			case "pPr":
+				var value ParagraphProperties
+				value.file = l.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !l.file.tolerated(err) {
+					return err
+				}
+				l.Properties = &value
+				continue
+			case "rPr":
+				var value RunProperties
+				value.file = l.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !l.file.tolerated(err) {
+					return err
+				}
+				l.RunProperties = &value
+				continue
+			}
+			err = d.Skip() // skip the content of the element
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML ...
+func (n *Num) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.ID, err = GetInt(getAtt(start.Attr, "numId"))
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "abstractNumId":
+				n.AbstractNumID = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "lvlOverride":
+				var value LvlOverride
+				value.file = n.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !n.file.tolerated(err) {
+					return err
+				}
+				n.LvlOverrides = append(n.LvlOverrides, &value)
+				continue
+			}
+			err = d.Skip() // skip the content of the element
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML ...
+func (o *LvlOverride) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	o.Ilvl, err = GetInt(getAtt(start.Attr, "ilvl"))
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "startOverride":
+				o.StartOverride = &NumberingVal{Val: getAtt(tt.Attr, "val")}
+			case "lvl":
+				var value Level
+				value.file = o.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !o.file.tolerated(err) {
+					return err
+				}
+				o.Level = &value
+				continue
+			}
+			err = d.Skip() // skip the content of the element
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AbstractNumByID gets the list definition by its id (or nil on notfound)
+func (n *Numbering) AbstractNumByID(id int) *AbstractNum {
+	for _, a := range n.AbstractNums {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// NumByID gets the numbering instance by its id (or nil on notfound)
+func (n *Numbering) NumByID(id int) *Num {
+	for _, num := range n.Nums {
+		if num.ID == id {
+			return num
+		}
+	}
+	return nil
+}
+
+// AbstractNum gets the list definition of the instance in n (or nil on notfound)
+func (num *Num) AbstractNum(n *Numbering) *AbstractNum {
+	if num.AbstractNumID == nil {
+		return nil
+	}
+	id, err := strconv.Atoi(num.AbstractNumID.Val)
+	if err != nil {
+		return nil
+	}
+	return n.AbstractNumByID(id)
+}
+
+// Level gets the definition of the level ilvl (or nil on notfound)
+func (a *AbstractNum) Level(ilvl int) *Level {
+	for _, l := range a.Levels {
+		if l.Ilvl == ilvl {
+			return l
+		}
+	}
+	return nil
+}
+
+// LvlOverride gets the override of the level ilvl (or nil on notfound)
+func (num *Num) LvlOverride(ilvl int) *LvlOverride {
+	for _, o := range num.LvlOverrides {
+		if o.Ilvl == ilvl {
+			return o
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const decoded_numbering = `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml">` +
+	`<w:numPicBullet w:numPicBulletId="0"><w:pict/></w:numPicBullet>` +
+	`<w:abstractNum w:abstractNumId="3" w14:restartNumberingAfterBreak="0"><w:nsid w:val="1A2B3C4D"/><w:multiLevelType w:val="multilevel"/>` +
+	`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="chineseCounting"/><w:lvlText w:val="第%1章"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="420" w:hanging="420"/></w:pPr></w:lvl>` +
+	`<w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1.%2"/><w:lvlJc w:val="left"/></w:lvl>` +
+	`</w:abstractNum>` +
+	`<w:num w:numId="1"><w:abstractNumId w:val="3"/></w:num>` +
+	`<w:num w:numId="2"><w:abstractNumId w:val="3"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="5"/></w:lvlOverride></w:num>` +
+	`<w:numIdMacAtCleanup w:val="2"/>` +
+	`</w:numbering>`
+
+func TestUnmarshalNumbering(t *testing.T) {
+	var n Numbering
+	err := xml.Unmarshal(StringToBytes(decoded_numbering), &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.AbstractNums) != 1 || len(n.Nums) != 2 || len(n.items) != 2 {
+		t.Fatalf("unexpected numbering %+v", n)
+	}
+	a := n.Nums[1].AbstractNum(&n)
+	if a == nil || a.ID != 3 || a.Nsid.Val != "1A2B3C4D" || len(a.Levels) != 2 {
+		t.Fatal("abstract numbering was not parsed")
+	}
+	if l := a.Level(0); l.NumFmt.Val != "chineseCounting" || l.LvlText.Val != "第%1章" || l.Properties.Ind.Hanging != 420 {
+		t.Fatalf("unexpected level %+v", l)
+	}
+	if o := n.Nums[1].LvlOverride(0); o == nil || o.StartOverride.Val != "5" {
+		t.Fatal("level override was not parsed")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = xml.NewEncoder(buf).Encode(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"><w:numPicBullet w:numPicBulletId="0"><w:pict></w:pict></w:numPicBullet><w:abstractNum w:abstractNumId="3">`,
+		`<w:lvl w:ilvl="0"><w:start w:val="1"></w:start><w:numFmt w:val="chineseCounting"></w:numFmt><w:lvlText w:val="第%1章"></w:lvlText>`,
+		`<w:num w:numId="2"><w:abstractNumId w:val="3"></w:abstractNumId><w:lvlOverride w:ilvl="0"><w:startOverride w:val="5"></w:startOverride></w:lvlOverride></w:num><w:numIdMacAtCleanup w:val="2"></w:numIdMacAtCleanup></w:numbering>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("%s not found in %s", s, out)
+		}
+	}
+}
+
+func TestListsRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	bullets := w.AddBulletList()
+	bullets.AddParagraph(0).AddText("first bullet")
+	bullets.AddParagraph(1).AddText("sub bullet")
+	numbers := w.AddNumberedList(NUMFMT_LOWER_ROMAN)
+	numbers.AddParagraph(0).AddText("one")
+	numbers.Restart().AddParagraph(0).AddText("one again")
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.hasRelation(REL_NUMBERING) {
+		t.Fatal("numbering relationship not found")
+	}
+	n := doc.numbering
+	if n == nil || len(n.AbstractNums) != 2 || len(n.Nums) != 3 {
+		t.Fatalf("unexpected numbering %+v", n)
+	}
+	p := doc.Document.Body.Items[3].(*Paragraph)
+	l := doc.List(3)
+	if l == nil || p.Properties.NumProperties.NumID.Val != "3" || l.AbstractNum().Level(0).NumFmt.Val != string(NUMFMT_LOWER_ROMAN) {
+		t.Fatal("restarted list not found")
+	}
+	if o := l.Num().LvlOverride(0); o == nil || o.StartOverride.Val != "1" {
+		t.Fatal("restarted list does not start again")
+	}
+
+	// the lists are copied along with the paragraphs
+	other := New().WithDefaultTheme()
+	other.AddNumberedList(NUMFMT_DECIMAL).AddParagraph(0).AddText("other")
+	other.AppendFile(doc)
+	if len(other.Numbering().Nums) != 4 || len(other.Numbering().AbstractNums) != 3 {
+		t.Fatalf("unexpected numbering %+v", other.Numbering())
+	}
+	p = other.Document.Body.Items[1].(*Paragraph)
+	if p.Properties.NumProperties.NumID.Val != "2" || doc.Document.Body.Items[0].(*Paragraph).Properties.NumProperties.NumID.Val != "1" {
+		t.Fatal("numbering id was not remapped")
+	}
+}
+
+func TestEvaluateNumbering(t *testing.T) {
+	doc := New()
+	doc.numbering = &Numbering{name: NUMBERING_PART}
+	err := xml.Unmarshal(StringToBytes(decoded_numbering), doc.numbering)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range []struct{ num, lvl, text string }{
+		{"1", "0", "Intro"}, {"1", "1", "a"}, {"1", "1", "b"}, {"1", "0", "Body"}, {"1", "1", "c"}, {"2", "0", "Annex"},
+	} {
+		doc.AddParagraph().NumPr(it.num, it.lvl).AddText(it.text)
+	}
+	table := doc.AddTable(1, 1, 1000)
+	table.Rows[0].Cells[0].AddParagraph().NumPr("2", "1").AddText("d")
+	doc.EvaluateNumbering()
+
+	labels := make([]string, 0, 8)
+	for _, p := range paragraphsOf(doc.Document.Body.Items) {
+		labels = append(labels, p.Label())
+	}
+	if l := strings.Join(labels, ","); l != "第一章,一.1,一.2,第二章,二.1,第五章,五.1" {
+		t.Fatalf("unexpected labels %s", l)
+	}
+	if s := doc.Document.Body.Items[1].(*Paragraph).String(); s != "  一.1\ta" {
+		t.Fatalf("unexpected text %q", s)
+	}
+}
+
+func TestEvaluateStyleNumbering(t *testing.T) {
+	doc := New()
+	doc.numbering = &Numbering{name: NUMBERING_PART}
+	err := xml.Unmarshal(StringToBytes(decoded_numbering), doc.numbering)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.AddParagraphStyle("Heading1", "heading 1").ParagraphProperties.NumProperties = &NumProperties{NumID: &NumID{Val: "1"}}
+	h2 := doc.AddParagraphStyle("Heading2", "heading 2")
+	h2.BasedOn = "Heading1"
+	h2.ParagraphProperties.NumProperties = &NumProperties{Ilvl: &Ilevel{Val: "1"}}
+	for _, it := range []struct{ style, text string }{
+		{"Heading1", "Intro"}, {"Heading2", "a"}, {"Heading2", "b"}, {"Heading1", "Body"},
+	} {
+		doc.AddParagraph().Style(it.style).AddText(it.text)
+	}
+	doc.AddParagraph().Style("Heading1").NumPr("2", "0").AddText("Annex")
+	doc.AddParagraph().Style("Heading1").NumPr("0", "0").AddText("Unnumbered")
+	doc.EvaluateNumbering()
+
+	labels := make([]string, 0, 8)
+	for _, p := range paragraphsOf(doc.Document.Body.Items) {
+		labels = append(labels, p.Label())
+	}
+	if l := strings.Join(labels, ","); l != "第一章,一.1,一.2,第二章,第五章," {
+		t.Fatalf("unexpected labels %s", l)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	for _, c := range []struct {
+		n      int
+		format _numFmt
+		text   string
+	}{
+		{7, NUMFMT_DECIMAL, "7"},
+		{7, NUMFMT_DECIMAL_ZERO, "07"},
+		{1994, NUMFMT_UPPER_ROMAN, "MCMXCIV"},
+		{4, NUMFMT_LOWER_ROMAN, "iv"},
+		{3, NUMFMT_UPPER_LETTER, "C"},
+		{28, NUMFMT_LOWER_LETTER, "bb"},
+		{10, NUMFMT_CHINESE_COUNTING, "十"},
+		{21, NUMFMT_CHINESE_COUNTING, "二十一"},
+		{105, NUMFMT_CHINESE_COUNTING, "一百〇五"},
+		{10001, NUMFMT_CHINESE_COUNTING, "一万〇一"},
+		{3, "ordinalText", "3"},
+	} {
+		if s := FormatNumber(c.n, c.format); s != c.text {
+			t.Fatalf("%d in %s is %q instead of %q", c.n, c.format, s, c.text)
+		}
+	}
+}
@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"reflect"
 	"strconv"
 	"strings"
 )
@@ -52,6 +51,57 @@ type ParagraphProperties struct {
 	RunProperties *RunProperties
 
 	ConfStyle *WTableConfStyle
+
+	Change *PPrChange
+
+	items []*rawElement // items are the unsupported properties kept as read
+
+	file *Docx
+}
+
+// pPrOrder is the sequence of the children of <w:pPr> (CT_PPr)
+var pPrOrder = orderIndex(
+	"w:pStyle", "w:keepNext", "w:keepLines", "w:pageBreakBefore", "w:framePr", "w:widowControl",
+	"w:numPr", "w:suppressLineNumbers", "w:pBdr", "w:shd", "w:tabs", "w:suppressAutoHyphens",
+	"w:kinsoku", "w:wordWrap", "w:overflowPunct", "w:topLinePunct", "w:autoSpaceDE", "w:autoSpaceDN",
+	"w:bidi", "w:adjustRightInd", "w:snapToGrid", "w:spacing", "w:kern", "w:ind", "w:contextualSpacing",
+	"w:mirrorIndents", "w:suppressOverlap", "w:jc", "w:textDirection", "w:textAlignment",
+	"w:textboxTightWrap", "w:outlineLvl", "w:divId", "w:cnfStyle", "w:rPr", "w:sectPr", "*", "w:pPrChange",
+)
+
+// MarshalXML writes the properties in the order of the schema
+func (p *ParagraphProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "w:pPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = encodeOrdered(e, pPrOrder, []orderedChild{
+		{"w:pStyle", p.Style},
+		{"w:keepNext", p.KeepNext},
+		{"w:keepLines", p.KeepLines},
+		{"w:pageBreakBefore", p.PageBreakBefore},
+		{"w:numPr", p.NumProperties},
+		{"w:shd", p.Shade},
+		{"w:tabs", p.Tabs},
+		{"w:suppressAutoHyphens", p.SuppressAutoHyphens},
+		{"w:kinsoku", p.Kinsoku},
+		{"w:overflowPunct", p.OverflowPunct},
+		{"w:adjustRightInd", p.AdjustRightInd},
+		{"w:snapToGrid", p.SnapToGrid},
+		{"w:spacing", p.Spacing},
+		{"w:kern", p.Kern},
+		{"w:ind", p.Ind},
+		{"w:jc", p.Justification},
+		{"w:textAlignment", p.TextAlignment},
+		{"w:cnfStyle", p.ConfStyle},
+		{"w:rPr", p.RunProperties},
+		{"w:pPrChange", p.Change},
+	}, p.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
 }
 
 type KeepNext struct {
@@ -71,7 +121,7 @@ type SuppressAutoHyphens struct {
 }
 
 // UnmarshalXML ...
-func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -80,26 +130,30 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
 			case "tabs":
 				var value Tabs
+				value.file = p.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Tabs = &value
 			case "spacing":
 				var value Spacing
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Spacing = &value
 			case "ind":
 				var value Ind
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Ind = &value
@@ -108,7 +162,7 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 			case "shd":
 				var value Shade
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Shade = &value
@@ -125,20 +179,35 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				p.Kern = &value
 			case "rPr":
 				var value RunProperties
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				value.file = p.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.RunProperties = &value
+			case "pPrChange":
+				var value PPrChange
+				value.file = p.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !p.file.tolerated(err) {
+					return err
+				}
+				p.Change = &value
 			case "pStyle":
 				p.Style = &Style{Val: getAtt(tt.Attr, "val")}
 			case "numPr":
 				var value NumProperties
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.NumProperties = &value
+			case "cnfStyle":
+				p.ConfStyle = new(WTableConfStyle)
+				err = d.DecodeElement(p.ConfStyle, &tt)
+				if err != nil && !p.file.tolerated(err) {
+					return err
+				}
 			case "textAlignment":
 				p.TextAlignment = &TextAlignment{Val: getAtt(tt.Attr, "val")}
 			case "adjustRightInd":
@@ -187,11 +256,11 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				p.OverflowPunct = &value
 
 			default:
-				err = d.Skip() // skip unsupported tags
+				value, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				p.items = append(p.items, value)
 			}
 		}
 	}
@@ -210,6 +279,11 @@ type Paragraph struct {
 	Properties *ParagraphProperties
 	Children   []interface{}
 
+	attrs []xml.Attr // attrs are the attributes kept as read (rsid, w14:paraId...)
+
+	label       string // label is the list label computed by EvaluateNumbering
+	labelSuffix string // labelSuffix separates the label from the text
+
 	file *Docx
 }
 
@@ -221,6 +295,10 @@ func (p *Paragraph) String() string {
 			sb.WriteString(strings.Repeat(" ", indent*2))
 		}
 	}
+	if p.label != "" {
+		sb.WriteString(p.label)
+		sb.WriteString(p.labelSuffix)
+	}
 	for _, c := range p.Children {
 		switch o := c.(type) {
 		case *Hyperlink:
@@ -236,6 +314,10 @@ func (p *Paragraph) String() string {
 				sb.WriteString(link)
 			}
 			sb.WriteByte(')')
+		case *Revision:
+			if k := o.Kind(); k == REVISION_INS || k == REVISION_MOVE_TO {
+				sb.WriteString(o.String())
+			}
 		case *Run:
 			for _, c := range o.Children {
 				switch x := c.(type) {
@@ -264,7 +346,8 @@ func (p *Paragraph) String() string {
 }
 
 // UnmarshalXML ...
-func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (p *Paragraph) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	p.attrs = extraAttrs(start.Attr)
 	/*for _, attr := range start.Attr {
 		switch attr.Name.Local {
 		case "rsidR":
@@ -279,7 +362,7 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			// ignore other attributes
 		}
 	}*/
-	children := make([]interface{}, 0, 64)
+	children := make([]interface{}, 0, 16)
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -288,17 +371,28 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 		if tt, ok := t.(xml.StartElement); ok {
 			var elem interface{}
 			switch tt.Name.Local {
 			case "hyperlink":
 				var value Hyperlink
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				value.Run.file = p.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
-				id := getAtt(tt.Attr, "id")
-				anchor := getAtt(tt.Attr, "anchor")
+				var id, anchor string
+				for _, at := range tt.Attr {
+					switch at.Name.Local {
+					case "id":
+						id = at.Value
+					case "anchor":
+						anchor = at.Value
+					}
+				}
 				if id != "" {
 					value.ID = id
 				}
@@ -309,32 +403,69 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "r":
 				var value Run
 				value.file = p.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				elem = &value
 			case "rPr":
 				var value RunProperties
+				value.file = p.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !p.file.tolerated(err) {
+					return err
+				}
+				elem = &value
+			case "ins", "del", "moveFrom", "moveTo":
+				var value Revision
+				value.file = p.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !p.file.tolerated(err) {
+					return err
+				}
+				elem = &value
+			case "moveFromRangeStart", "moveToRangeStart":
+				var value MoveRangeStart
+				err = d.DecodeElement(&value, &tt)
+				if err != nil {
+					return err
+				}
+				elem = &value
+			case "moveFromRangeEnd", "moveToRangeEnd":
+				var value MoveRangeEnd
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil {
+					return err
+				}
+				elem = &value
+			case "commentRangeStart":
+				var value CommentRangeStart
+				err = d.DecodeElement(&value, &tt)
+				if err != nil {
+					return err
+				}
+				elem = &value
+			case "commentRangeEnd":
+				var value CommentRangeEnd
+				err = d.DecodeElement(&value, &tt)
+				if err != nil {
 					return err
 				}
 				elem = &value
 			case "pPr":
 				var value ParagraphProperties
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				value.file = p.file
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Properties = &value
 				continue
 			default:
-				err = d.Skip() // skip unsupported tags
+				elem, err = readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
 			}
 			children = append(children, elem)
 		}
@@ -343,6 +474,14 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	return nil
 }
 
+// MarshalXML ...
+func (p *Paragraph) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _p Paragraph
+	start.Name = xml.Name{Local: "w:p"}
+	start.Attr = mergeAttrs(start.Attr, p.attrs)
+	return e.EncodeElement((*_p)(p), start)
+}
+
 // KeepElements keep named elems amd removes others
 //
 // names: *docx.Hyperlink *docx.Run *docx.RunProperties
@@ -353,7 +492,7 @@ func (p *Paragraph) KeepElements(name ...string) {
 		namemap[n] = struct{}{}
 	}
 	for _, item := range p.Children {
-		_, ok := namemap[reflect.ValueOf(item).Type().String()]
+		_, ok := namemap[typeName(item)]
 		if ok {
 			items = append(items, item)
 		}
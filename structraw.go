@@ -0,0 +1,496 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const XMLNS_XML = `http://www.w3.org/XML/1998/namespace`
+
+// rawElement keeps an element that is not modelled as its token stream,
+// names are rewritten to their prefixed form so that it can be encoded back
+type rawElement struct {
+	tokens []xml.Token
+}
+
+// nsPrefixes collects the prefixes declared by the attributes of an element
+// into ns (namespace URL -> prefix)
+func nsPrefixes(ns map[string]string, attrs []xml.Attr) map[string]string {
+	if ns == nil {
+		ns = make(map[string]string, 16)
+	}
+	for _, a := range attrs {
+		switch {
+		case a.Name.Space == "xmlns":
+			ns[a.Value] = a.Name.Local
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			ns[a.Value] = ""
+		}
+	}
+	return ns
+}
+
+// prefixName rewrites a namespace resolved name to "prefix:local"
+func prefixName(n xml.Name, ns map[string]string) xml.Name {
+	switch n.Space {
+	case "":
+		return n
+	case "xmlns":
+		return xml.Name{Local: "xmlns:" + n.Local}
+	case XMLNS_XML, "xml":
+		return xml.Name{Local: "xml:" + n.Local}
+	}
+	p, ok := ns[n.Space]
+	if !ok {
+		if !strings.Contains(n.Space, ":") {
+			// the prefix is not declared, keep it
+			return xml.Name{Local: n.Space + ":" + n.Local}
+		}
+		return n
+	}
+	if p == "" {
+		return xml.Name{Local: n.Local}
+	}
+	return xml.Name{Local: p + ":" + n.Local}
+}
+
+// prefixAttrs rewrites all attribute names to their prefixed form
+func prefixAttrs(attrs []xml.Attr, ns map[string]string) []xml.Attr {
+	na := make([]xml.Attr, len(attrs))
+	for i, a := range attrs {
+		na[i] = xml.Attr{Name: prefixName(a.Name, ns), Value: a.Value}
+	}
+	return na
+}
+
+// readRawElement consumes start and its content from d
+func readRawElement(d *xml.Decoder, start xml.StartElement, ns map[string]string) (*rawElement, error) {
+	tokens, err := readTokens(d, start)
+	if err != nil {
+		return nil, err
+	}
+	return newRawElement(tokens, ns), nil
+}
+
+// readTokens consumes start and its content from d and returns
+// a copy of their tokens, names are namespace resolved
+func readTokens(d *xml.Decoder, start xml.StartElement) ([]xml.Token, error) {
+	tokens := make([]xml.Token, 0, 8)
+	depth := 0
+	var t xml.Token = start
+	for {
+		switch tt := t.(type) {
+		case xml.StartElement:
+			depth++
+			tokens = append(tokens, tt.Copy())
+		case xml.EndElement:
+			depth--
+			tokens = append(tokens, tt)
+		case xml.CharData:
+			tokens = append(tokens, tt.Copy())
+		}
+		if depth == 0 {
+			return tokens, nil
+		}
+		var err error
+		t, err = d.Token()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// newRawElement returns the raw element of the resolved tokens
+func newRawElement(tokens []xml.Token, ns map[string]string) *rawElement {
+	r := &rawElement{tokens: make([]xml.Token, len(tokens))}
+	for i, t := range tokens {
+		switch tt := t.(type) {
+		case xml.StartElement:
+			r.tokens[i] = xml.StartElement{
+				Name: prefixName(tt.Name, ns),
+				Attr: prefixAttrs(tt.Attr, ns),
+			}
+		case xml.EndElement:
+			r.tokens[i] = xml.EndElement{Name: prefixName(tt.Name, ns)}
+		default:
+			r.tokens[i] = t
+		}
+	}
+	return r
+}
+
+// name is the prefixed name of the element
+func (r *rawElement) name() string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	return r.tokens[0].(xml.StartElement).Name.Local
+}
+
+// MarshalXML ...
+func (r *rawElement) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	for _, t := range r.tokens {
+		err := e.EncodeToken(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// related reports whether the element refers to the relationships of its part
+func (r *rawElement) related() bool {
+	for _, t := range r.tokens {
+		if tt, ok := t.(xml.StartElement); ok {
+			for _, a := range tt.Attr {
+				if strings.HasPrefix(a.Name.Local, "r:") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// copyBookmark returns the element, a bookmark start or end of from
+// is renumbered for to
+func (r *rawElement) copyBookmark(from, to *Docx) *rawElement {
+	if n := r.name(); n != "w:bookmarkStart" && n != "w:bookmarkEnd" {
+		return r
+	}
+	start := r.tokens[0].(xml.StartElement)
+	for i, a := range start.Attr {
+		if a.Name.Local != "w:id" {
+			continue
+		}
+		id, err := strconv.Atoi(a.Value)
+		if err != nil {
+			return r
+		}
+		nid := to.copyID(from, ID_BOOKMARK, id)
+		if nid == id {
+			return r
+		}
+		start = start.Copy()
+		start.Attr[i].Value = strconv.Itoa(nid)
+		tokens := make([]xml.Token, len(r.tokens))
+		copy(tokens, r.tokens)
+		tokens[0] = start
+		return &rawElement{tokens: tokens}
+	}
+	return r
+}
+
+// copyable reports whether item can be copied as is to another document,
+// the kept elements referring to relationships cannot
+func copyable(item interface{}) bool {
+	raw, ok := item.(*rawElement)
+	return !ok || !raw.related()
+}
+
+// knownPrefixes are the usual prefixes of the namespaces found in the parts
+// (namespace URL -> prefix), they are used to keep the unsupported elements
+var knownPrefixes = map[string]string{
+	XMLNS_W:              "w",
+	XMLNS_R:              "r",
+	XMLNS_WP:             "wp",
+	XMLNS_WPS:            "wps",
+	XMLNS_WPC:            "wpc",
+	XMLNS_WPG:            "wpg",
+	XMLNS_MC:             "mc",
+	XMLNS_O:              "o",
+	XMLNS_V:              "v",
+	XMLNS_W14:            "w14",
+	XMLNS_W15:            "w15",
+	XMLNS_DRAWINGML_MAIN: "a",
+	XMLNS_PICTURE:        "pic",
+	`http://schemas.microsoft.com/office/word/2010/wordprocessingDrawing`:  "wp14",
+	`http://schemas.microsoft.com/office/word/2010/wordprocessingInk`:      "wpi",
+	`http://schemas.microsoft.com/office/word/2006/wordml`:                 "wne",
+	`http://schemas.microsoft.com/office/word/2015/wordml/symex`:           "w16se",
+	`http://schemas.microsoft.com/office/word/2016/wordml/cid`:             "w16cid",
+	`http://schemas.microsoft.com/office/word/2018/wordml`:                 "w16",
+	`http://schemas.microsoft.com/office/word/2018/wordml/cex`:             "w16cex",
+	`http://schemas.microsoft.com/office/word/2020/wordml/sdtdatahash`:     "w16sdtdh",
+	`http://schemas.microsoft.com/office/word/2023/wordml/word16du`:        "w16du",
+	`http://schemas.openxmlformats.org/officeDocument/2006/math`:           "m",
+	`urn:schemas-microsoft-com:office:word`:                                "w10",
+	`http://schemas.microsoft.com/office/drawing/2010/main`:                "a14",
+	`http://schemas.openxmlformats.org/drawingml/2006/chart`:               "c",
+	`http://schemas.openxmlformats.org/drawingml/2006/diagram`:             "dgm",
+	`http://schemas.microsoft.com/office/drawing/2014/chartex`:             "cx",
+	`http://schemas.microsoft.com/office/drawing/2016/ink`:                 "aink",
+	`http://schemas.microsoft.com/office/drawing/2017/model3d`:             "am3d",
+	`http://schemas.openxmlformats.org/officeDocument/2006/customXml`:      "ds",
+	`http://schemas.openxmlformats.org/officeDocument/2006/sharedTypes`:    "st",
+	`http://schemas.microsoft.com/office/drawing/2010/slicer`:              "sle15",
+	`http://schemas.microsoft.com/office/word/2010/wordprocessingGroup/sp`: "wpgsp",
+	`http://schemas.openxmlformats.org/drawingml/2006/lockedCanvas`:        "lc",
+	`http://schemas.openxmlformats.org/schemaLibrary/2006/main`:            "sl",
+}
+
+// knownNamespaces are the namespaces of knownPrefixes (prefix -> namespace URL)
+var knownNamespaces = func() map[string]string {
+	m := make(map[string]string, len(knownPrefixes))
+	for ns, p := range knownPrefixes {
+		m[p] = ns
+	}
+	return m
+}()
+
+// isEnd tells whether the token t ends the element start.
+// The decoding methods stopping there can be called directly, without
+// the reflection of DecodeElement, and still be used through it.
+func isEnd(t xml.Token, start xml.StartElement) bool {
+	end, ok := t.(xml.EndElement)
+	return ok && end.Name == start.Name
+}
+
+// readUnknown keeps start, which is not supported by the model, and its content.
+// The namespaces used inside are declared on the element itself so that
+// it can be written in any part.
+func readUnknown(d *xml.Decoder, start xml.StartElement) (*rawElement, error) {
+	r, err := readRawElement(d, start, knownPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	r.declarePrefixes()
+	return r, nil
+}
+
+// rootPrefixes are declared by the root of all the story parts
+var rootPrefixes = map[string]bool{
+	"w": true, "r": true, "wp": true, "wps": true, "wpc": true, "wpg": true,
+	"xml": true, "xmlns": true,
+}
+
+// declarePrefixes declares on the element the known prefixes it uses
+// which are neither declared by the part root nor in their own scope
+func (r *rawElement) declarePrefixes() {
+	if len(r.tokens) == 0 {
+		return
+	}
+	declared := make(map[string]bool, 4)
+	scopes := make([][]string, 0, 8)
+	inScope := func(p string) bool {
+		for _, s := range scopes {
+			for _, q := range s {
+				if q == p {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	use := func(n string) {
+		i := strings.IndexByte(n, ':')
+		if i <= 0 {
+			return
+		}
+		p := n[:i]
+		if rootPrefixes[p] || declared[p] || inScope(p) {
+			return
+		}
+		if _, ok := knownNamespaces[p]; ok {
+			declared[p] = true
+		}
+	}
+	for _, t := range r.tokens {
+		switch tt := t.(type) {
+		case xml.StartElement:
+			var scope []string
+			for _, a := range tt.Attr {
+				if strings.HasPrefix(a.Name.Local, "xmlns:") {
+					scope = append(scope, a.Name.Local[len("xmlns:"):])
+				}
+			}
+			scopes = append(scopes, scope)
+			use(tt.Name.Local)
+			for _, a := range tt.Attr {
+				use(a.Name.Local)
+			}
+		case xml.EndElement:
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+		}
+	}
+	if len(declared) == 0 {
+		return
+	}
+	start := r.tokens[0].(xml.StartElement)
+	prefixes := make([]string, 0, len(declared))
+	for p := range declared {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	attrs := make([]xml.Attr, 0, len(start.Attr)+len(prefixes))
+	for _, p := range prefixes {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: knownNamespaces[p]})
+	}
+	start.Attr = append(attrs, start.Attr...)
+	r.tokens[0] = start
+}
+
+// extraAttrs returns the attributes, in their prefixed form,
+// whose local name is not one of known
+func extraAttrs(attrs []xml.Attr, known ...string) []xml.Attr {
+	var extra []xml.Attr
+next:
+	for _, a := range attrs {
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			continue
+		}
+		for _, k := range known {
+			if a.Name.Local == k {
+				continue next
+			}
+		}
+		extra = append(extra, xml.Attr{Name: prefixName(a.Name, knownPrefixes), Value: a.Value})
+	}
+	return extra
+}
+
+// mergeAttrs appends the kept attributes which are not already in attrs
+func mergeAttrs(attrs, kept []xml.Attr) []xml.Attr {
+	if len(kept) == 0 {
+		return attrs
+	}
+	merged := make([]xml.Attr, len(attrs), len(attrs)+len(kept))
+	copy(merged, attrs)
+next:
+	for _, k := range kept {
+		for _, a := range attrs {
+			if a.Name.Local == k.Name.Local {
+				continue next
+			}
+		}
+		merged = append(merged, k)
+	}
+	return merged
+}
+
+// keptItem is an element kept as read among modelled siblings
+type keptItem struct {
+	at  int // at is the index of the modelled sibling it precedes
+	raw *rawElement
+}
+
+// copyKept returns the kept items which can be copied to another document
+func copyKept(items []keptItem) []keptItem {
+	var kept []keptItem
+	for _, item := range items {
+		if !item.raw.related() {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// encodeKept writes the kept items preceding the sibling at, from next,
+// and returns the index of the first item not written
+func encodeKept(e *xml.Encoder, items []keptItem, next, at int) (int, error) {
+	for next < len(items) && items[next].at <= at {
+		err := e.Encode(items[next].raw)
+		if err != nil {
+			return next, err
+		}
+		next++
+	}
+	return next, nil
+}
+
+// orderedChild is a modelled child element written by encodeOrdered
+type orderedChild struct {
+	name string      // name is the prefixed name of the element
+	v    interface{} // v is encoded with the name, nothing is written when nil
+}
+
+// orderIndex returns the position of each name of a schema sequence,
+// "*" is the position of the elements which are not in the sequence
+func orderIndex(names ...string) map[string]int {
+	m := make(map[string]int, len(names))
+	for i, n := range names {
+		m[n] = i
+	}
+	return m
+}
+
+// encodeOrdered writes the children and the kept items, the items are
+// written before the first child which follows them in the order
+func encodeOrdered(e *xml.Encoder, order map[string]int, children []orderedChild, items []*rawElement) error {
+	unknown, ok := order["*"]
+	if !ok {
+		unknown = len(order)
+	}
+	pos := func(name string) int {
+		if i, ok := order[name]; ok {
+			return i
+		}
+		return unknown
+	}
+	next := 0
+	for _, c := range children {
+		i := pos(c.name)
+		for next < len(items) && pos(items[next].name()) < i {
+			err := e.Encode(items[next])
+			if err != nil {
+				return err
+			}
+			next++
+		}
+		if c.v == nil {
+			continue
+		}
+		err := e.EncodeElement(c.v, xml.StartElement{Name: xml.Name{Local: c.name}})
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range items[next:] {
+		err := e.Encode(item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tokenReader reads the tokens of a slice
+type tokenReader struct {
+	tokens []xml.Token
+}
+
+// Token ...
+func (t *tokenReader) Token() (xml.Token, error) {
+	if len(t.tokens) == 0 {
+		return nil, io.EOF
+	}
+	tok := t.tokens[0]
+	t.tokens = t.tokens[1:]
+	return tok, nil
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const decoded_unknown = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" ` +
+	`xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"><w:body>` +
+	`<w:sdt><w:sdtPr><w:alias w:val="TOC"/></w:sdtPr><w:sdtContent><w:p><w:r><w:t>toc</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+	`<w:p w14:paraId="1A2B3C4D" w:rsidR="00AB12CD"><w:pPr><w:widowControl w:val="0"/><w:spacing w:before="120" w:after="240"/></w:pPr>` +
+	`<w:bookmarkStart w:id="0" w:name="here"/>` +
+	`<w:r><w:rPr><w:b/><w:vertAlign w:val="superscript"/><w:rtl/><w:lang w:val="fr-FR" w:eastAsia="zh-CN"/></w:rPr><w:t>a</w:t></w:r>` +
+	`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>` +
+	`<w:bookmarkEnd w:id="0"/>` +
+	`<m:oMath><m:r><m:t>x</m:t></m:r></m:oMath>` +
+	`<w:hyperlink w:anchor="here" w:history="1"><w:r><w:t>go</w:t></w:r><w:r><w:t>to</w:t></w:r></w:hyperlink></w:p>` +
+	`<w:tbl><w:tblPr><w:tblW w:w="0" w:type="auto"/><w:tblInd w:w="108" w:type="dxa"/></w:tblPr>` +
+	`<w:tblGrid><w:gridCol w:w="100"/></w:tblGrid><w:tr><w:trPr><w:cantSplit/></w:trPr><w:tc><w:tcPr><w:tcW w:w="100" w:type="dxa"/><w:noWrap/></w:tcPr>` +
+	`<w:p><w:r><w:t>cell</w:t></w:r></w:p><w:bookmarkStart w:id="1" w:name="cell"/><w:bookmarkEnd w:id="1"/><w:p/></w:tc></w:tr></w:tbl>` +
+	`<w:sectPr><w:pgSz w:w="11906" w:h="16838" w:orient="portrait"/><w:pgMar w:top="1440" w:right="1800" w:bottom="1440" w:left="1800" w:header="851" w:footer="992" w:gutter="0"/>` +
+	`<w:pgNumType w:start="3"/><w:cols w:num="2" w:space="425"/><w:docGrid w:type="lines" w:linePitch="312"/></w:sectPr>` +
+	`</w:body></w:document>`
+
+func TestKeepUnknown(t *testing.T) {
+	doc := New()
+	doc.Document.Body.file = doc
+	err := xml.Unmarshal(StringToBytes(decoded_unknown), &doc.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(&doc.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	for _, frag := range []string{
+		`<w:sdt><w:sdtPr><w:alias w:val="TOC"></w:alias></w:sdtPr>`,
+		`<w:p w14:paraId="1A2B3C4D" w:rsidR="00AB12CD"><w:pPr><w:widowControl w:val="0"></w:widowControl><w:spacing w:before="120" w:after="240"></w:spacing></w:pPr>`,
+		`<w:bookmarkStart w:id="0" w:name="here"></w:bookmarkStart><w:r><w:rPr><w:b></w:b><w:vertAlign w:val="superscript"></w:vertAlign><w:rtl></w:rtl><w:lang w:val="fr-FR" w:eastAsia="zh-CN"></w:lang></w:rPr>`,
+		`<w:r><w:fldChar w:fldCharType="begin"></w:fldChar></w:r>`,
+		`<w:bookmarkEnd w:id="0"></w:bookmarkEnd><m:oMath xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"><m:r><m:t>x</m:t></m:r></m:oMath>`,
+		`<w:hyperlink w:anchor="here" w:history="1"><w:r><w:t>go</w:t></w:r><w:r><w:t>to</w:t></w:r></w:hyperlink>`,
+		`<w:tblW w:w="0" w:type="auto"></w:tblW><w:tblInd w:w="108" w:type="dxa"></w:tblInd>`,
+		`<w:trPr><w:cantSplit></w:cantSplit></w:trPr>`,
+		`<w:noWrap></w:noWrap></w:tcPr>`,
+		`<w:t>cell</w:t></w:r></w:p><w:bookmarkStart w:id="1" w:name="cell"></w:bookmarkStart><w:bookmarkEnd w:id="1"></w:bookmarkEnd><w:p>`,
+		`<w:pgSz w:w="11906" w:h="16838" w:orient="portrait"></w:pgSz>`,
+		`</w:pgMar><w:pgNumType w:start="3"></w:pgNumType><w:cols w:space="425" w:num="2"></w:cols><w:docGrid w:type="lines" w:linePitch="312"></w:docGrid></w:sectPr>`,
+	} {
+		if !strings.Contains(s, frag) {
+			t.Errorf("%s not found in\n%s", frag, s)
+		}
+	}
+
+	// the output is stable
+	doc2 := New()
+	doc2.Document.Body.file = doc2
+	err = xml.Unmarshal(out, &doc2.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out2, err := xml.Marshal(&doc2.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out2) != s {
+		t.Fatalf("unstable output\n%s\n%s", s, out2)
+	}
+}
@@ -20,11 +20,28 @@
 
 package docx
 
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
 //nolint:revive,stylecheck
 const (
 	XMLNS_REL     = `http://schemas.openxmlformats.org/package/2006/relationships`
 	REL_HYPERLINK = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink`
 	REL_IMAGE     = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/image`
+	REL_HEADER    = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/header`
+	REL_FOOTER    = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer`
+	REL_SETTINGS  = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings`
+	REL_FOOTNOTES = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes`
+	REL_ENDNOTES  = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/endnotes`
+	REL_COMMENTS  = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments`
+	REL_NUMBERING = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering`
+	REL_STYLES    = `http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles`
+
+	REL_COMMENTS_EXTENDED = `http://schemas.microsoft.com/office/2011/relationships/commentsExtended`
 
 	REL_TARGETMODE = "External"
 )
@@ -42,3 +59,71 @@ type Relationship struct {
 	Target     string `xml:"Target,attr"`
 	TargetMode string `xml:"TargetMode,attr,omitempty"`
 }
+
+// UnmarshalXML reads the attributes of the relationships without reflection,
+// a document has one relationship for each of its links and images
+func (rels *Relationships) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			rels.Xmlns = attr.Value
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if isEnd(t, start) {
+			break
+		}
+		tt, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if tt.Name.Local == "Relationship" {
+			var r Relationship
+			for _, attr := range tt.Attr {
+				switch attr.Name.Local {
+				case "Id":
+					r.ID = attr.Value
+				case "Type":
+					r.Type = attr.Value
+				case "Target":
+					r.Target = attr.Value
+				case "TargetMode":
+					r.TargetMode = attr.Value
+				}
+			}
+			rels.Relationship = append(rels.Relationship, r)
+		}
+		err = d.Skip()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newID returns a new relationship id of the form rIdN not used in rels,
+// n is the last N used
+func (rels *Relationships) newID(n *uintptr) string {
+	for {
+		id := "rId" + strconv.Itoa(int(atomic.AddUintptr(n, 1)))
+		if !rels.hasID(id) {
+			return id
+		}
+	}
+}
+
+// hasID tells whether the id is used in rels
+func (rels *Relationships) hasID(id string) bool {
+	for _, r := range rels.Relationship {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
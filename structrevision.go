@@ -0,0 +1,337 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type _revision string
+
+const (
+	REVISION_INS       _revision = "ins"      // Inserted content <w:ins>
+	REVISION_DEL       _revision = "del"      // Deleted content <w:del>
+	REVISION_MOVE_FROM _revision = "moveFrom" // Content moved away <w:moveFrom>
+	REVISION_MOVE_TO   _revision = "moveTo"   // Content moved here <w:moveTo>
+)
+
+// Revision is a tracked change of the runs of a paragraph:
+// <w:ins>, <w:del>, <w:moveFrom> or <w:moveTo>
+//
+// Its children are the same as the ones of a paragraph
+type Revision struct {
+	XMLName xml.Name
+	ID      int    `xml:"w:id,attr"`
+	Author  string `xml:"w:author,attr,omitempty"`
+	Date    string `xml:"w:date,attr,omitempty"`
+
+	Children []interface{}
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (r *Revision) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	r.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			r.ID, err = GetInt(attr.Value)
+			if err != nil {
+				return err
+			}
+		case "author":
+			r.Author = attr.Value
+		case "date":
+			r.Date = attr.Value
+		}
+	}
+	p := Paragraph{file: r.file}
+	err = p.UnmarshalXML(d, start)
+	r.Children = p.Children
+	return err
+}
+
+// Kind is the kind of change
+func (r *Revision) Kind() _revision {
+	return _revision(strings.TrimPrefix(r.XMLName.Local, "w:"))
+}
+
+// String returns the text of the revision
+func (r *Revision) String() string {
+	p := Paragraph{Children: r.Children, file: r.file}
+	return p.String()
+}
+
+// RevisionMark <w:ins> or <w:del> in the run properties of a paragraph mark
+// tells that the paragraph mark has been inserted or deleted
+type RevisionMark struct {
+	ID     int    `xml:"w:id,attr"`
+	Author string `xml:"w:author,attr,omitempty"`
+	Date   string `xml:"w:date,attr,omitempty"`
+}
+
+// UnmarshalXML ...
+func (r *RevisionMark) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, r.Author, r.Date, err = revisionAttrs(start.Attr)
+	if err != nil {
+		return
+	}
+	return d.Skip()
+}
+
+// MoveRangeStart <w:moveFromRangeStart> or <w:moveToRangeStart>
+// marks the start of a move
+type MoveRangeStart struct {
+	XMLName xml.Name
+	ID      int    `xml:"w:id,attr"`
+	Author  string `xml:"w:author,attr,omitempty"`
+	Date    string `xml:"w:date,attr,omitempty"`
+	Name    string `xml:"w:name,attr,omitempty"`
+}
+
+// UnmarshalXML ...
+func (r *MoveRangeStart) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	r.ID, r.Author, r.Date, err = revisionAttrs(start.Attr)
+	if err != nil {
+		return
+	}
+	r.Name = getAtt(start.Attr, "name")
+	return d.Skip()
+}
+
+// MoveRangeEnd <w:moveFromRangeEnd> or <w:moveToRangeEnd>
+// marks the end of a move
+type MoveRangeEnd struct {
+	XMLName xml.Name
+	ID      int `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (r *MoveRangeEnd) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	r.ID, _, _, err = revisionAttrs(start.Attr)
+	if err != nil {
+		return
+	}
+	return d.Skip()
+}
+
+// RPrChange <w:rPrChange> keeps the run properties before a tracked formatting
+type RPrChange struct {
+	XMLName xml.Name `xml:"w:rPrChange,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+	Author  string   `xml:"w:author,attr,omitempty"`
+	Date    string   `xml:"w:date,attr,omitempty"`
+
+	RunProperties *RunProperties
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (r *RPrChange) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, r.Author, r.Date, err = revisionAttrs(start.Attr)
+	if err != nil {
+		return
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "rPr" {
+				var value RunProperties
+				value.file = r.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !r.file.tolerated(err) {
+					return err
+				}
+				r.RunProperties = &value
+				continue
+			}
+			err = d.Skip() // skip unsupported tags
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML always writes the inner <w:rPr> which is required
+func (r *RPrChange) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:rPrChange"
+	start.Attr = revisionAttrsOf(r.ID, r.Author, r.Date)
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if r.RunProperties == nil || r.RunProperties.isEmpty() {
+		err = e.EncodeElement(struct{}{}, xml.StartElement{Name: xml.Name{Local: "w:rPr"}})
+	} else {
+		err = e.Encode(r.RunProperties)
+	}
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// PPrChange <w:pPrChange> keeps the paragraph properties before a tracked formatting
+type PPrChange struct {
+	XMLName xml.Name `xml:"w:pPrChange,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+	Author  string   `xml:"w:author,attr,omitempty"`
+	Date    string   `xml:"w:date,attr,omitempty"`
+
+	ParagraphProperties *ParagraphProperties
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (r *PPrChange) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.ID, r.Author, r.Date, err = revisionAttrs(start.Attr)
+	if err != nil {
+		return
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "pPr" {
+				var value ParagraphProperties
+				value.file = r.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !r.file.tolerated(err) {
+					return err
+				}
+				r.ParagraphProperties = &value
+				continue
+			}
+			err = d.Skip() // skip unsupported tags
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML always writes the inner <w:pPr> which is required
+func (r *PPrChange) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:pPrChange"
+	start.Attr = revisionAttrsOf(r.ID, r.Author, r.Date)
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if r.ParagraphProperties == nil {
+		err = e.EncodeElement(struct{}{}, xml.StartElement{Name: xml.Name{Local: "w:pPr"}})
+	} else {
+		err = e.Encode(r.ParagraphProperties)
+	}
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// DelText <w:delText> is the text of a deleted run
+type DelText struct {
+	XMLName xml.Name `xml:"w:delText,omitempty"`
+
+	XMLSpace string `xml:"xml:space,attr,omitempty"`
+
+	Text string `xml:",chardata"`
+}
+
+// UnmarshalXML ...
+func (r *DelText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var t Text
+	err := t.UnmarshalXML(d, start)
+	r.XMLSpace, r.Text = t.XMLSpace, t.Text
+	return err
+}
+
+func revisionAttrs(attrs []xml.Attr) (id int, author, date string, err error) {
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "id":
+			id, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+		case "author":
+			author = attr.Value
+		case "date":
+			date = attr.Value
+		}
+	}
+	return
+}
+
+func revisionAttrsOf(id int, author, date string) []xml.Attr {
+	attrs := make([]xml.Attr, 1, 3)
+	attrs[0] = xml.Attr{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(id)}
+	if author != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "w:author"}, Value: author})
+	}
+	if date != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "w:date"}, Value: date})
+	}
+	return attrs
+}
+
+// copyRevisions copies the properties with the revision ids of from renumbered for to
+func (r *RunProperties) copyRevisions(from, to *Docx) *RunProperties {
+	nr := *r
+	if r.Ins != nil {
+		m := *r.Ins
+		m.ID = to.copyID(from, ID_REVISION, m.ID)
+		nr.Ins = &m
+	}
+	if r.Del != nil {
+		m := *r.Del
+		m.ID = to.copyID(from, ID_REVISION, m.ID)
+		nr.Del = &m
+	}
+	if r.Change != nil {
+		c := *r.Change
+		c.ID = to.copyID(from, ID_REVISION, c.ID)
+		nr.Change = &c
+	}
+	return &nr
+}
@@ -0,0 +1,187 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+const decoded_revisions = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	`<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r>` +
+	`<w:ins w:id="1" w:author="Alice" w:date="2024-01-01T00:00:00Z"><w:r><w:t xml:space="preserve">quick </w:t></w:r></w:ins>` +
+	`<w:del w:id="2" w:author="Bob" w:date="2024-01-01T00:00:00Z"><w:r><w:delText xml:space="preserve">slow </w:delText></w:r></w:del>` +
+	`<w:r><w:rPr><w:b/><w:rPrChange w:id="3" w:author="Alice"><w:rPr/></w:rPrChange></w:rPr><w:t>fox</w:t></w:r></w:p>` +
+	`<w:p><w:pPr><w:jc w:val="center"/><w:rPr><w:del w:id="4" w:author="Bob"/></w:rPr><w:pPrChange w:id="5" w:author="Bob"><w:pPr/></w:pPrChange></w:pPr>` +
+	`<w:moveFromRangeStart w:id="6" w:author="Alice" w:name="move1"/><w:moveFrom w:id="7" w:author="Alice"><w:r><w:t>jumps</w:t></w:r></w:moveFrom><w:moveFromRangeEnd w:id="6"/></w:p>` +
+	`<w:p><w:moveToRangeStart w:id="8" w:author="Alice" w:name="move1"/><w:moveTo w:id="9" w:author="Alice"><w:r><w:t>jumps</w:t></w:r></w:moveTo><w:moveToRangeEnd w:id="8"/><w:r><w:t xml:space="preserve"> over</w:t></w:r></w:p>` +
+	`</w:body></w:document>`
+
+func parseRevisions(t *testing.T) *Docx {
+	doc := New()
+	doc.Document.Body.file = doc
+	err := xml.Unmarshal(StringToBytes(decoded_revisions), &doc.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func bodyText(doc *Docx) string {
+	texts := make([]string, 0, len(doc.Document.Body.Items))
+	for _, item := range doc.Document.Body.Items {
+		if p, ok := item.(*Paragraph); ok {
+			texts = append(texts, p.String())
+		}
+	}
+	return strings.Join(texts, "|")
+}
+
+func TestUnmarshalRevisions(t *testing.T) {
+	doc := parseRevisions(t)
+	if txt := bodyText(doc); txt != "The quick fox||jumps over" {
+		t.Fatalf("unexpected text %q", txt)
+	}
+	p := doc.Document.Body.Items[0].(*Paragraph)
+	ins, ok := p.Children[1].(*Revision)
+	if !ok || ins.Kind() != REVISION_INS || ins.Author != "Alice" || ins.ID != 1 || ins.Date != "2024-01-01T00:00:00Z" {
+		t.Fatal("insertion was not parsed")
+	}
+	if p.Properties != nil {
+		t.Fatal("unexpected paragraph properties")
+	}
+	if c := p.Children[3].(*Run).RunProperties.Change; c == nil || c.Author != "Alice" {
+		t.Fatal("rPrChange was not parsed")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := xml.NewEncoder(buf).Encode(&doc.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		`<w:ins w:id="1" w:author="Alice" w:date="2024-01-01T00:00:00Z">`,
+		`<w:delText xml:space="preserve">slow </w:delText>`,
+		`<w:rPrChange w:id="3" w:author="Alice"><w:rPr></w:rPr></w:rPrChange>`,
+		`<w:del w:id="4" w:author="Bob"></w:del>`,
+		`<w:pPrChange w:id="5" w:author="Bob"><w:pPr></w:pPr></w:pPrChange>`,
+		`<w:moveFromRangeStart w:id="6" w:author="Alice" w:name="move1"></w:moveFromRangeStart>`,
+		`<w:moveTo w:id="9" w:author="Alice">`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("%s was not marshalled in %s", s, out)
+		}
+	}
+}
+
+func TestResolveRevisions(t *testing.T) {
+	doc := parseRevisions(t)
+	doc.AcceptAllRevisions()
+	if txt := bodyText(doc); txt != "The quick fox|jumps over" {
+		t.Fatalf("unexpected accepted text %q", txt)
+	}
+	p := doc.Document.Body.Items[1].(*Paragraph)
+	if p.Properties != nil {
+		t.Fatal("the merged paragraph must keep the properties of the next paragraph")
+	}
+	if doc.Document.Body.Items[0].(*Paragraph).Children[2].(*Run).RunProperties.Change != nil {
+		t.Fatal("rPrChange was not accepted")
+	}
+
+	doc = parseRevisions(t)
+	doc.RejectAllRevisions()
+	if txt := bodyText(doc); txt != "The slow fox|jumps| over" {
+		t.Fatalf("unexpected rejected text %q", txt)
+	}
+	rp := doc.Document.Body.Items[0].(*Paragraph).Children[2].(*Run).RunProperties
+	if rp.Bold != nil || rp.Change != nil {
+		t.Fatal("rPrChange was not rejected")
+	}
+	if pp := doc.Document.Body.Items[1].(*Paragraph).Properties; pp.Justification != nil || pp.Change != nil {
+		t.Fatal("pPrChange was not rejected")
+	}
+
+	doc = parseRevisions(t)
+	doc.AcceptRevisionsOf("Bob")
+	if txt := bodyText(doc); txt != "The quick fox|jumps over" {
+		t.Fatalf("unexpected text %q", txt)
+	}
+	p = doc.Document.Body.Items[0].(*Paragraph)
+	if _, ok := p.Children[1].(*Revision); !ok {
+		t.Fatal("the insertion of Alice must be kept")
+	}
+	doc.RejectRevisionsOf("Alice")
+	if txt := bodyText(doc); txt != "The fox|jumps over" {
+		t.Fatalf("unexpected text %q", txt)
+	}
+}
+
+func TestTrackChanges(t *testing.T) {
+	doc := parseRevisions(t)
+	date := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)
+	doc.TrackChanges("Carol", date)
+
+	p := doc.Document.Body.Items[2].(*Paragraph)
+	p.AddText(" the lazy")
+	p.AddText(" dog").Bold()
+	ins, ok := p.Children[len(p.Children)-1].(*Revision)
+	if !ok || ins.Kind() != REVISION_INS || ins.Author != "Carol" || ins.Date != "2024-02-03T04:05:06Z" {
+		t.Fatal("the added text is not an insertion")
+	}
+	if ins.ID != 10 || len(ins.Children) != 2 {
+		t.Fatalf("unexpected insertion %d with %d runs", ins.ID, len(ins.Children))
+	}
+	if ins.Children[1].(*Run).RunProperties.Change != nil {
+		t.Fatal("the formatting of inserted text must not be tracked")
+	}
+
+	r := doc.Document.Body.Items[0].(*Paragraph).Children[0].(*Run)
+	r.Italic().Color("FF0000")
+	c := r.RunProperties.Change
+	if c == nil || c.ID != 11 || c.Author != "Carol" || c.RunProperties.Italic != nil || c.RunProperties.Color != nil {
+		t.Fatal("the formatting change was not tracked")
+	}
+
+	if !doc.RemoveParagraph(doc.Document.Body.Items[0].(*Paragraph)) {
+		t.Fatal("paragraph not found")
+	}
+	if txt := bodyText(doc); txt != "||jumps over the lazy dog" {
+		t.Fatalf("unexpected text %q", txt)
+	}
+	if len(doc.Document.Body.Items) != 3 {
+		t.Fatal("a tracked removal must keep the paragraph")
+	}
+
+	doc.AcceptRevisionsOf("Carol")
+	if txt := bodyText(doc); txt != "|jumps over the lazy dog" {
+		t.Fatalf("unexpected accepted text %q", txt)
+	}
+	doc.RejectAllRevisions()
+	if txt := bodyText(doc); txt != "slow jumps| over the lazy dog" {
+		t.Fatalf("unexpected rejected text %q", txt)
+	}
+
+	doc.StopTrackingChanges()
+	if !doc.RemoveParagraph(doc.Document.Body.Items[0].(*Paragraph)) || len(doc.Document.Body.Items) != 1 {
+		t.Fatal("paragraph was not removed")
+	}
+}
@@ -23,8 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"reflect"
-	"strings"
 )
 
 // Run is part of a paragraph that has its own style. It could be
@@ -41,7 +39,9 @@ type Run struct {
 
 	Children []interface{}
 
-	file *Docx
+	attrs    []xml.Attr // attrs are the attributes kept as read (rsid...)
+	inserted bool       // inserted is set on the runs added while tracking changes
+	file     *Docx
 }
 
 // UnmarshalXML ...
@@ -58,6 +58,7 @@ func (r *Run) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			// ignore other attributes
 		}
 	}
+	r.attrs = extraAttrs(start.Attr, "space")
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -66,6 +67,9 @@ func (r *Run) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.StartElement); ok {
 			child, err := r.parse(d, tt)
@@ -81,41 +85,99 @@ func (r *Run) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// MarshalXML ...
+func (r *Run) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _r Run
+	start.Name = xml.Name{Local: "w:r"}
+	start.Attr = mergeAttrs(start.Attr, r.attrs)
+	return e.EncodeElement((*_r)(r), start)
+}
+
 func (r *Run) parse(d *xml.Decoder, tt xml.StartElement) (child interface{}, err error) {
 	switch tt.Name.Local {
 	case "rPr":
 		var value RunProperties
-		err = d.DecodeElement(&value, &tt)
-		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		value.file = r.file
+		err = value.UnmarshalXML(d, tt)
+		if err != nil && !r.file.tolerated(err) {
 			return nil, err
 		}
 		r.RunProperties = &value
 		return nil, nil
 	case "instrText":
-		var value string
-		err = d.DecodeElement(&value, &tt)
-		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		var value Text
+		err = value.UnmarshalXML(d, tt)
+		if err != nil && !r.file.tolerated(err) {
 			return nil, err
 		}
-		r.InstrText = value
+		r.InstrText = value.Text
 		return nil, nil
 	case "t":
 		var value Text
-		err = d.DecodeElement(&value, &tt)
-		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		err = value.UnmarshalXML(d, tt)
+		if err != nil && !r.file.tolerated(err) {
 			return nil, err
 		}
 		child = &value
 	case "drawing":
+		var tokens []xml.Token
+		tokens, err = readTokens(d, tt)
+		if err != nil {
+			return nil, err
+		}
 		var value Drawing
 		value.file = r.file
-		err = d.DecodeElement(&value, &tt)
-		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		err = xml.NewTokenDecoder(&tokenReader{tokens: tokens}).Decode(&value)
+		if err != nil && !r.file.tolerated(err) {
+			return nil, err
+		}
+		raw := newRawElement(tokens, knownPrefixes)
+		raw.declarePrefixes()
+		err = value.keep(raw)
+		if err != nil {
 			return nil, err
 		}
 		child = &value
 	case "tab":
 		child = &Tab{}
+	case "delText":
+		var value DelText
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !r.file.tolerated(err) {
+			return nil, err
+		}
+		child = &value
+	case "footnoteReference":
+		var value FootnoteReference
+		err = d.DecodeElement(&value, &tt)
+		if err != nil {
+			return nil, err
+		}
+		child = &value
+	case "endnoteReference":
+		var value EndnoteReference
+		err = d.DecodeElement(&value, &tt)
+		if err != nil {
+			return nil, err
+		}
+		child = &value
+	case "commentReference":
+		var value CommentReference
+		err = d.DecodeElement(&value, &tt)
+		if err != nil {
+			return nil, err
+		}
+		child = &value
+	case "annotationRef":
+		child = &AnnotationRef{}
+	case "footnoteRef":
+		child = &FootnoteRef{}
+	case "endnoteRef":
+		child = &EndnoteRef{}
+	case "separator":
+		child = &Separator{}
+	case "continuationSeparator":
+		child = &ContinuationSeparator{}
 	case "br":
 		var value BarterRabbet
 		err = d.DecodeElement(&value, &tt)
@@ -124,62 +186,72 @@ func (r *Run) parse(d *xml.Decoder, tt xml.StartElement) (child interface{}, err
 		}
 		child = &value
 	case "AlternateContent":
-		/*var value AlternateContent
-		value.file = r.file
-		err = d.DecodeElement(&value, &tt)
-		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		// the drawings of the supported choice (wps, wpc or wpg) are modelled,
+		// the element is written back as read while they are not changed
+		var tokens []xml.Token
+		tokens, err = readTokens(d, tt)
+		if err != nil {
 			return nil, err
 		}
-		if value.Choice == nil {
-			return nil, nil
-		}
-		if value.Choice.Requires != "wps" && value.Choice.Requires != "wpc" && value.Choice.Requires != "wpg" {
-			return nil, nil
+		raw := newRawElement(tokens, knownPrefixes)
+		raw.declarePrefixes()
+		child, err = r.parseAlternateContent(tokens)
+		if err != nil {
+			return nil, err
 		}
-		child = &value*/
-	altcont:
-		for {
-			tok, err1 := d.Token()
-			if err1 == io.EOF {
-				break
-			}
-			if err1 != nil {
-				return nil, err1
-			}
-
-			if ttt, ok := tok.(xml.StartElement); ok && ttt.Name.Local == "Choice" {
-				for _, attr := range ttt.Attr {
-					if attr.Name.Local == "Requires" {
-						if attr.Value == "wps" || attr.Value == "wpc" || attr.Value == "wpg" {
-							tok, err = d.Token() // go into choice
-							if err != nil {
-								return nil, err
-							}
-							if ttt, ok := tok.(xml.StartElement); ok {
-								child, err = r.parse(d, ttt)
-							}
-							break altcont
-						}
-						break
-					}
-				}
-			}
-			if et, ok := tok.(xml.EndElement); ok {
-				if et.Name.Local == "AlternateContent" {
-					break
-				}
-			}
-			err = d.Skip() // skip unsupported tags
+		if dr, ok := child.(*Drawing); ok {
+			err = dr.keep(raw)
 			if err != nil {
 				return nil, err
 			}
+			return dr, nil
+		}
+		if child == nil {
+			child = raw
 		}
 	default:
-		err = d.Skip() // skip unsupported tags
+		child, err = readUnknown(d, tt)
 	}
 	return
 }
 
+// parseAlternateContent parses the first element of the supported choice
+// of the tokens of <mc:AlternateContent>, child is nil when there is none
+func (r *Run) parseAlternateContent(tokens []xml.Token) (child interface{}, err error) {
+	d := xml.NewTokenDecoder(&tokenReader{tokens: tokens[1 : len(tokens)-1]})
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ttt, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if ttt.Name.Local == "Choice" {
+			switch getAtt(ttt.Attr, "Requires") {
+			case "wps", "wpc", "wpg":
+				for {
+					tok, err = d.Token() // go into choice
+					if err != nil {
+						return nil, err
+					}
+					if ttt, ok := tok.(xml.StartElement); ok {
+						return r.parse(d, ttt)
+					}
+				}
+			}
+		}
+		err = d.Skip() // skip the other choices and the fallback
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
 // KeepElements keep named elems amd removes others
 //
 // names: *docx.Text *docx.Drawing *docx.Tab *docx.BarterRabbet
@@ -190,7 +262,7 @@ func (r *Run) KeepElements(name ...string) {
 		namemap[n] = struct{}{}
 	}
 	for _, item := range r.Children {
-		_, ok := namemap[reflect.ValueOf(item).Type().String()]
+		_, ok := namemap[typeName(item)]
 		if ok {
 			items = append(items, item)
 		}
@@ -200,7 +272,9 @@ func (r *Run) KeepElements(name ...string) {
 
 // RunProperties encapsulates visual properties of a run
 type RunProperties struct {
-	XMLName   xml.Name `xml:"w:rPr,omitempty"`
+	XMLName   xml.Name      `xml:"w:rPr,omitempty"`
+	Ins       *RevisionMark `xml:"w:ins,omitempty"` // only in the properties of a paragraph mark
+	Del       *RevisionMark `xml:"w:del,omitempty"` // only in the properties of a paragraph mark
 	Fonts     *RunFonts
 	Bold      *Bold
 	ICs       *struct{} `xml:"w:iCs,omitempty"`
@@ -219,10 +293,26 @@ type RunProperties struct {
 	Strike    *Strike
 	Lang      *Lang
 	NoProof   *NoProof
+
+	Change *RPrChange
+
+	items []*rawElement // items are the unsupported properties kept as read
+
+	file *Docx
 }
 
+// rPrOrder is the sequence of the children of <w:rPr> (CT_RPr)
+var rPrOrder = orderIndex(
+	"w:ins", "w:del", "w:moveFrom", "w:moveTo", "w:rStyle", "w:pStyle", "w:rFonts", "w:b", "w:bCs",
+	"w:i", "w:iCs", "w:caps", "w:smallCaps", "w:strike", "w:dstrike", "w:outline", "w:shadow", "w:emboss",
+	"w:imprint", "w:noProof", "w:snapToGrid", "w:vanish", "w:webHidden", "w:color", "w:spacing", "w:w",
+	"w:kern", "w:position", "w:sz", "w:szCs", "w:highlight", "w:u", "w:effect", "w:bdr", "w:shd",
+	"w:fitText", "w:vertAlign", "w:rtl", "w:cs", "w:em", "w:lang", "w:eastAsianLayout", "w:specVanish",
+	"w:oMath", "*", "w:rPrChange",
+)
+
 // UnmarshalXML ...
-func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (r *RunProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -231,25 +321,48 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
+			case "ins", "del":
+				var value RevisionMark
+				err = d.DecodeElement(&value, &tt)
+				if err != nil {
+					return err
+				}
+				if tt.Name.Local == "ins" {
+					r.Ins = &value
+				} else {
+					r.Del = &value
+				}
+			case "rPrChange":
+				var value RPrChange
+				value.file = r.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !r.file.tolerated(err) {
+					return err
+				}
+				r.Change = &value
 			case "rFonts":
 				var value RunFonts
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Fonts = &value
 			case "b":
-				r.Bold = &Bold{}
+				r.Bold = &Bold{Val: getAtt(tt.Attr, "val")}
 			case "iCs":
 				r.ICs = &struct{}{}
 			case "i":
-				r.Italic = &Italic{}
+				r.Italic = &Italic{Val: getAtt(tt.Attr, "val")}
 			case "u":
 				var value Underline
 				value.Val = getAtt(tt.Attr, "val")
+				value.attrs = extraAttrs(tt.Attr, "val")
 				r.Underline = &value
 			case "highlight":
 				var value Highlight
@@ -258,6 +371,7 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "color":
 				var value Color
 				value.Val = getAtt(tt.Attr, "val")
+				value.attrs = extraAttrs(tt.Attr, "val")
 				r.Color = &value
 			case "sz":
 				var value Size
@@ -266,7 +380,7 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "spacing":
 				var value Spacing
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Spacing = &value
@@ -285,7 +399,7 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "shd":
 				var value Shade
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Shade = &value
@@ -311,17 +425,16 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "lang":
 				var value Lang
 				value.Val = getAtt(tt.Attr, "val")
+				value.attrs = extraAttrs(tt.Attr, "val")
 				r.Lang = &value
 			case "noProof":
-				var value Lang
-				value.Val = getAtt(tt.Attr, "val")
-				r.Lang = &value
+				r.NoProof = &NoProof{}
 			default:
-				err = d.Skip() // skip unsupported tags
+				value, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				r.items = append(r.items, value)
 			}
 		}
 	}
@@ -329,15 +442,51 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	return nil
 }
 
+// MarshalXML writes the properties in the order of the schema, nothing when empty
 func (t *RunProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if t.Fonts == nil && t.Bold == nil && t.ICs == nil && t.Italic == nil && t.Highlight == nil &&
-		t.Color == nil && t.Size == nil && t.SizeCs == nil && t.Spacing == nil && t.RunStyle == nil &&
-		t.Style == nil && t.Shade == nil && t.Kern == nil && t.Underline == nil && t.VertAlign == nil &&
-		t.Strike == nil && t.Lang == nil && t.NoProof == nil {
+	if t.isEmpty() {
 		return nil
 	}
-	type _t RunProperties
-	return e.Encode((*_t)(t))
+	start = xml.StartElement{Name: xml.Name{Local: "w:rPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = encodeOrdered(e, rPrOrder, []orderedChild{
+		{"w:ins", t.Ins},
+		{"w:del", t.Del},
+		{"w:rStyle", t.RunStyle},
+		{"w:pStyle", t.Style},
+		{"w:rFonts", t.Fonts},
+		{"w:b", t.Bold},
+		{"w:i", t.Italic},
+		{"w:iCs", t.ICs},
+		{"w:strike", t.Strike},
+		{"w:noProof", t.NoProof},
+		{"w:color", t.Color},
+		{"w:spacing", t.Spacing},
+		{"w:kern", t.Kern},
+		{"w:sz", t.Size},
+		{"w:szCs", t.SizeCs},
+		{"w:highlight", t.Highlight},
+		{"w:u", t.Underline},
+		{"w:shd", t.Shade},
+		{"w:vertAlign", t.VertAlign},
+		{"w:lang", t.Lang},
+		{"w:rPrChange", t.Change},
+	}, t.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (t *RunProperties) isEmpty() bool {
+	return t.Ins == nil && t.Del == nil && t.Fonts == nil && t.Bold == nil && t.ICs == nil && t.Italic == nil &&
+		t.Highlight == nil && t.Color == nil && t.Size == nil && t.SizeCs == nil && t.Spacing == nil &&
+		t.RunStyle == nil && t.Style == nil && t.Shade == nil && t.Kern == nil && t.Underline == nil &&
+		t.VertAlign == nil && t.Strike == nil && t.Lang == nil && t.NoProof == nil && t.Change == nil &&
+		len(t.items) == 0
 }
 
 // RunFonts specifies the fonts used in the text of a run.
@@ -347,6 +496,8 @@ type RunFonts struct {
 	EastAsia string   `xml:"w:eastAsia,attr,omitempty"`
 	HAnsi    string   `xml:"w:hAnsi,attr,omitempty"`
 	Hint     string   `xml:"w:hint,attr,omitempty"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (cs, asciiTheme...)
 }
 
 // UnmarshalXML ...
@@ -363,7 +514,17 @@ func (f *RunFonts) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			f.Hint = attr.Value
 		}
 	}
+	f.attrs = extraAttrs(start.Attr, "ascii", "eastAsia", "hAnsi", "hint")
 	// Consume the end element
 	_, err := d.Token()
 	return err
 }
+
+// MarshalXML writes the kept attributes
+func (f *RunFonts) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _runFonts RunFonts
+	return e.EncodeElement(struct {
+		*_runFonts
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_runFonts)(f), f.attrs}, start)
+}
@@ -21,22 +21,37 @@ import (
 	"encoding/xml"
 	"io"
 	"strconv"
-	"strings"
 )
 
 // SectPr show the properties of the document, like paper size
 type SectPr struct {
-	XMLName xml.Name `xml:"w:sectPr,omitempty"` // properties of the document, including paper size
-	PgSz    *PgSz    `xml:"w:pgSz,omitempty"`
-	PgMar   *PgMar   `xml:"w:pgMar,omitempty"`
-	Cols    *Cols    `xml:"w:cols,omitempty"`
-	DocGrid *DocGrid `xml:"w:docGrid,omitempty"`
+	XMLName         xml.Name           `xml:"w:sectPr,omitempty"` // properties of the document, including paper size
+	HeaderReference []*HeaderReference `xml:"w:headerReference,omitempty"`
+	FooterReference []*FooterReference `xml:"w:footerReference,omitempty"`
+	PgSz            *PgSz              `xml:"w:pgSz,omitempty"`
+	PgMar           *PgMar             `xml:"w:pgMar,omitempty"`
+	Cols            *Cols              `xml:"w:cols,omitempty"`
+	TitlePg         *TitlePg           `xml:"w:titlePg,omitempty"`
+	DocGrid         *DocGrid           `xml:"w:docGrid,omitempty"`
+
+	items []*rawElement // items are the unsupported properties kept as read
+	file  *Docx
 }
 
+// sectPrOrder is the sequence of the children of <w:sectPr> (CT_SectPr)
+var sectPrOrder = orderIndex(
+	"w:headerReference", "w:footerReference", "w:footnotePr", "w:endnotePr", "w:type", "w:pgSz",
+	"w:pgMar", "w:paperSrc", "w:pgBorders", "w:lnNumType", "w:pgNumType", "w:cols", "w:formProt",
+	"w:vAlign", "w:noEndnote", "w:titlePg", "w:textDirection", "w:bidi", "w:rtlGutter", "w:docGrid",
+	"w:printerSettings", "*", "w:sectPrChange",
+)
+
 // PgSz show the paper size
 type PgSz struct {
 	W int `xml:"w:w,attr"` // width of paper
 	H int `xml:"w:h,attr"` // high of paper
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (orient...)
 }
 
 // PgMar show the page margin
@@ -53,16 +68,21 @@ type PgMar struct {
 // Cols show the number of columns
 type Cols struct {
 	Space int `xml:"w:space,attr"`
+
+	attrs []xml.Attr    // attrs are the other attributes kept as read (num, sep...)
+	items []*rawElement // items are the <w:col> kept as read
 }
 
 // DocGrid show the document grid
 type DocGrid struct {
 	Type      string `xml:"w:type,attr"`
 	LinePitch int    `xml:"w:linePitch,attr"`
+
+	attrs []xml.Attr // attrs are the other attributes kept as read (charSpace)
 }
 
 // UnmarshalXML ...
-func (sect *SectPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (sect *SectPr) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -71,47 +91,101 @@ func (sect *SectPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
+			case "headerReference":
+				var value HeaderReference
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !sect.file.tolerated(err) {
+					return err
+				}
+				sect.HeaderReference = append(sect.HeaderReference, &value)
+			case "footerReference":
+				var value FooterReference
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !sect.file.tolerated(err) {
+					return err
+				}
+				sect.FooterReference = append(sect.FooterReference, &value)
+			case "titlePg":
+				if v := getAtt(tt.Attr, "val"); v != "0" && v != "false" {
+					sect.TitlePg = &TitlePg{}
+				}
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
 			case "pgSz":
 				var value PgSz
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !sect.file.tolerated(err) {
 					return err
 				}
 				sect.PgSz = &value
 			case "pgMar":
 				var value PgMar
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !sect.file.tolerated(err) {
 					return err
 				}
 				sect.PgMar = &value
 			case "cols":
 				var value Cols
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !sect.file.tolerated(err) {
 					return err
 				}
 				sect.Cols = &value
 			case "docGrid":
 				var value DocGrid
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !sect.file.tolerated(err) {
 					return err
 				}
 				sect.DocGrid = &value
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
+				sect.items = append(sect.items, raw)
 			}
 		}
 	}
 	return nil
 }
 
+// MarshalXML writes the properties in the order of the schema
+func (sect *SectPr) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "w:sectPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	children := make([]orderedChild, 0, len(sect.HeaderReference)+len(sect.FooterReference)+5)
+	for _, r := range sect.HeaderReference {
+		children = append(children, orderedChild{"w:headerReference", r})
+	}
+	for _, r := range sect.FooterReference {
+		children = append(children, orderedChild{"w:footerReference", r})
+	}
+	children = append(children,
+		orderedChild{"w:pgSz", sect.PgSz},
+		orderedChild{"w:pgMar", sect.PgMar},
+		orderedChild{"w:cols", sect.Cols},
+		orderedChild{"w:titlePg", sect.TitlePg},
+		orderedChild{"w:docGrid", sect.DocGrid},
+	)
+	err = encodeOrdered(e, sectPrOrder, children, sect.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
 // UnmarshalXML ...
 func (pgsz *PgSz) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var err error
@@ -128,10 +202,9 @@ func (pgsz *PgSz) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			if err != nil {
 				return err
 			}
-		default:
-			// ignore other attributes now
 		}
 	}
+	pgsz.attrs = extraAttrs(start.Attr, "w", "h")
 	// Consume the end element
 	_, err = d.Token()
 	return err
@@ -202,9 +275,24 @@ func (cols *Cols) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			// ignore other attributes now
 		}
 	}
-	// Consume the end element
-	_, err = d.Token()
-	return err
+	cols.attrs = extraAttrs(start.Attr, "space")
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			raw, err := readUnknown(d, tt)
+			if err != nil {
+				return err
+			}
+			cols.items = append(cols.items, raw)
+		}
+	}
+	return nil
 }
 
 // UnmarshalXML ...
@@ -224,7 +312,36 @@ func (dg *DocGrid) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			// ignore other attributes now
 		}
 	}
+	dg.attrs = extraAttrs(start.Attr, "linePitch", "type")
 	// Consume the end element
 	_, err = d.Token()
 	return err
 }
+
+// MarshalXML writes the kept attributes
+func (pgsz *PgSz) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _pgSz PgSz
+	return e.EncodeElement(struct {
+		*_pgSz
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_pgSz)(pgsz), pgsz.attrs}, start)
+}
+
+// MarshalXML writes the kept attributes and columns
+func (cols *Cols) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _cols Cols
+	return e.EncodeElement(struct {
+		*_cols
+		Attrs []xml.Attr `xml:",any,attr"`
+		Items []*rawElement
+	}{(*_cols)(cols), cols.attrs, cols.items}, start)
+}
+
+// MarshalXML writes the kept attributes
+func (dg *DocGrid) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type _docGrid DocGrid
+	return e.EncodeElement(struct {
+		*_docGrid
+		Attrs []xml.Attr `xml:",any,attr"`
+	}{(*_docGrid)(dg), dg.attrs}, start)
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// settingsOrder is the sequence of the children of <w:settings> (CT_Settings)
+var settingsOrder = []string{
+	"writeProtection", "view", "zoom", "removePersonalInformation", "removeDateAndTime",
+	"doNotDisplayPageBoundaries", "displayBackgroundShape", "printPostScriptOverText",
+	"printFractionalCharacterWidth", "printFormsData", "embedTrueTypeFonts", "embedSystemFonts",
+	"saveSubsetFonts", "saveFormsData", "mirrorMargins", "alignBordersAndEdges",
+	"bordersDoNotSurroundHeader", "bordersDoNotSurroundFooter", "gutterAtTop",
+	"hideSpellingErrors", "hideGrammaticalErrors", "activeWritingStyle", "proofState",
+	"formsDesign", "attachedTemplate", "linkStyles", "stylePaneFormatFilter",
+	"stylePaneSortMethod", "documentType", "mailMerge", "revisionView", "trackRevisions",
+	"doNotTrackMoves", "doNotTrackFormatting", "documentProtection", "autoFormatOverride",
+	"styleLockTheme", "styleLockQFSet", "defaultTabStop", "autoHyphenation",
+	"consecutiveHyphenLimit", "hyphenationZone", "doNotHyphenateCaps", "showEnvelope",
+	"summaryLength", "clickAndTypeStyle", "defaultTableStyle", "evenAndOddHeaders",
+	"bookFoldRevPrinting", "bookFoldPrinting", "bookFoldPrintingSheets",
+	"drawingGridHorizontalSpacing", "drawingGridVerticalSpacing",
+	"displayHorizontalDrawingGridEvery", "displayVerticalDrawingGridEvery",
+	"doNotUseMarginsForDrawingGridOrigin", "drawingGridHorizontalOrigin",
+	"drawingGridVerticalOrigin", "doNotShadeFormData", "noPunctuationKerning",
+	"characterSpacingControl", "printTwoOnOne", "strictFirstAndLastChars", "noLineBreaksAfter",
+	"noLineBreaksBefore", "savePreviewPicture", "doNotValidateAgainstSchema",
+	"saveInvalidXml", "ignoreMixedContent", "alwaysShowPlaceholderText",
+	"doNotDemarcateInvalidXml", "saveXmlDataOnly", "useXSLTWhenSaving", "saveThroughXslt",
+	"showXMLTags", "alwaysMergeEmptyNamespace", "updateFields", "hdrShapeDefaults",
+	"footnotePr", "endnotePr", "compat", "docVars", "rsids", "mathPr", "attachedSchema",
+	"themeFontLang", "clrSchemeMapping", "doNotIncludeSubdocsInStats",
+	"doNotAutoCompressPictures", "forceUpgrade", "captions", "readModeInkLockDown",
+	"smartTagType", "schemaLibrary", "shapeDefaults", "doNotEmbedSmartTags",
+	"decimalSymbol", "listSeparator",
+}
+
+var settingsOrderIdx = func() map[string]int {
+	m := make(map[string]int, len(settingsOrder))
+	for i, n := range settingsOrder {
+		m["w:"+n] = i
+	}
+	return m
+}()
+
+// Settings is word/settings.xml
+//
+// Only the flags managed by this library are modelled, all other
+// elements are kept as they are and written back in their original order.
+type Settings struct {
+	// EvenAndOddHeaders enables the even page headers and footers
+	EvenAndOddHeaders bool
+
+	attrs []xml.Attr
+	items []*rawElement
+}
+
+// UnmarshalXML ...
+func (s *Settings) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	ns := nsPrefixes(nil, start.Attr)
+	ns[XMLNS_W] = "w"
+	s.attrs = prefixAttrs(start.Attr, ns)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Space == XMLNS_W && tt.Name.Local == "evenAndOddHeaders" {
+				v := getAtt(tt.Attr, "val")
+				s.EvenAndOddHeaders = v != "false" && v != "0"
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			r, err := readRawElement(d, tt, ns)
+			if err != nil {
+				return err
+			}
+			s.items = append(s.items, r)
+		}
+	}
+	return nil
+}
+
+// MarshalXML ...
+func (s *Settings) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: xml.Name{Local: "w:settings"}, Attr: s.attrs}
+	if len(start.Attr) == 0 {
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "xmlns:w"}, Value: XMLNS_W}}
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	flags := make([]*rawElement, 0, 4)
+	if s.EvenAndOddHeaders {
+		flags = append(flags, newRawFlag("w:evenAndOddHeaders"))
+	}
+	for _, item := range mergeByOrder(s.items, flags, settingsOrderIdx) {
+		err = e.Encode(item)
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// newRawFlag returns an empty element named name
+func newRawFlag(name string) *rawElement {
+	n := xml.Name{Local: name}
+	return &rawElement{tokens: []xml.Token{xml.StartElement{Name: n}, xml.EndElement{Name: n}}}
+}
+
+// mergeByOrder inserts each of add into items before the first item
+// that comes after it in the schema sequence order
+func mergeByOrder(items, add []*rawElement, order map[string]int) []*rawElement {
+	if len(add) == 0 {
+		return items
+	}
+	pos := func(r *rawElement) int {
+		i, ok := order[r.name()]
+		if !ok {
+			return len(order)
+		}
+		return i
+	}
+	merged := make([]*rawElement, 0, len(items)+len(add))
+	merged = append(merged, items...)
+	for _, a := range add {
+		pa := pos(a)
+		i := 0
+		for i < len(merged) && pos(merged[i]) <= pa {
+			i++
+		}
+		merged = append(merged, nil)
+		copy(merged[i+1:], merged[i:])
+		merged[i] = a
+	}
+	return merged
+}
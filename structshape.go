@@ -23,7 +23,6 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"strings"
 )
 
 // WordprocessingShape is a container for a WordprocessingML DrawingML shape.
@@ -55,39 +54,41 @@ func (w *WordprocessingShape) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 			case "cNvPr":
 				w.CNvPr = new(NonVisualProperties)
 				err = d.DecodeElement(w.CNvPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "cNvCnPr":
 				w.CNvCnPr = new(WPSCNvCnPr)
 				err = d.DecodeElement(w.CNvCnPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "cNvSpPr":
 				w.CNvSpPr = new(WPSCNvSpPr)
+				w.CNvSpPr.file = w.file
 				err = d.DecodeElement(w.CNvSpPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "spPr":
 				w.SpPr = new(ShapeProperties)
+				w.SpPr.file = w.file
 				err = d.DecodeElement(w.SpPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "txbx":
 				var value WPSTextBox
 				value.file = w.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.TextBox = &value
 			case "bodyPr":
 				w.BodyPr = new(WPSBodyPr)
 				err = d.DecodeElement(w.BodyPr, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -140,6 +141,8 @@ type WPSCNvSpPr struct {
 	TxBox   int      `xml:"txBox,attr,omitempty"`
 
 	SPLocks *ASPLocks
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -169,7 +172,7 @@ func (w *WPSCNvSpPr) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err e
 			case "spLocks":
 				var value ASPLocks
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.SPLocks = &value
@@ -218,6 +221,8 @@ type ABlipFill struct {
 	Blip    *ABlip
 	SrcRect *ASrcRect
 	Tile    *ATile
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -253,7 +258,7 @@ func (r *ABlipFill) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err er
 			case "blip":
 				var value ABlip
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Blip = &value
@@ -262,7 +267,7 @@ func (r *ABlipFill) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err er
 			case "tile":
 				var value ATile
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !r.file.tolerated(err) {
 					return err
 				}
 				r.Tile = &value
@@ -337,6 +342,8 @@ type ALine struct {
 	Round     *struct{} `xml:"a:round,omitempty"`
 	HeadEnd   *AHeadEnd
 	TailEnd   *ATailEnd
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -374,7 +381,7 @@ func (l *ALine) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error)
 			case "solidFill":
 				l.SolidFill = new(ASolidFill)
 				err = d.DecodeElement(l.SolidFill, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !l.file.tolerated(err) {
 					return err
 				}
 			case "prstDash":
@@ -390,13 +397,13 @@ func (l *ALine) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error)
 			case "headEnd":
 				l.HeadEnd = new(AHeadEnd)
 				err = d.DecodeElement(l.HeadEnd, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !l.file.tolerated(err) {
 					return err
 				}
 			case "tailEnd":
 				l.TailEnd = new(ATailEnd)
 				err = d.DecodeElement(l.TailEnd, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !l.file.tolerated(err) {
 					return err
 				}
 			default:
@@ -551,7 +558,7 @@ func (b *WPSTextBox) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				var value WTextBoxContent
 				value.file = b.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !b.file.tolerated(err) {
 					return err
 				}
 				b.Content = &value
@@ -592,7 +599,7 @@ func (c *WTextBoxContent) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error
 				var value Paragraph
 				value.file = c.file
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				c.Paragraphs = append(c.Paragraphs, value)
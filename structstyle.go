@@ -0,0 +1,620 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type _styleType string
+
+const (
+	STYLE_PARAGRAPH _styleType = "paragraph" // Style of paragraphs <w:pStyle>
+	STYLE_CHARACTER _styleType = "character" // Style of runs <w:rStyle>
+	STYLE_TABLE     _styleType = "table"     // Style of tables <w:tblStyle>
+	STYLE_NUMBERING _styleType = "numbering" // Style of lists
+)
+
+// styleOrder is the sequence of the children of <w:style> (CT_Style)
+var styleOrder = []string{
+	"name", "aliases", "basedOn", "next", "link", "autoRedefine", "hidden", "uiPriority",
+	"semiHidden", "unhideWhenUsed", "qFormat", "locked", "personal", "personalCompose",
+	"personalReply", "rsid", "pPr", "rPr", "tblPr", "trPr", "tcPr", "tblStylePr",
+}
+
+var styleOrderIdx = func() map[string]int {
+	m := make(map[string]int, len(styleOrder))
+	for i, n := range styleOrder {
+		m["w:"+n] = i
+	}
+	return m
+}()
+
+// Styles is the styles part (word/styles.xml) <w:styles>
+type Styles struct {
+	DocDefaults  *DocDefaults
+	LatentStyles *LatentStyles
+	Styles       []*StyleDefinition
+
+	attrs []xml.Attr
+	items []*rawElement
+
+	name string // name is the part name, e.g. word/styles.xml
+
+	file *Docx
+}
+
+// DocDefaults <w:docDefaults> are the properties of all the paragraphs and runs
+type DocDefaults struct {
+	XMLName             xml.Name             `xml:"w:docDefaults"`
+	RunProperties       *RunProperties       `xml:"w:rPrDefault>w:rPr"`
+	ParagraphProperties *ParagraphProperties `xml:"w:pPrDefault>w:pPr"`
+
+	file *Docx
+}
+
+// LatentStyles <w:latentStyles> are the default behaviours of the
+// styles known by the application but not defined in the document
+type LatentStyles struct {
+	XMLName           xml.Name `xml:"w:latentStyles"`
+	DefLockedState    string   `xml:"w:defLockedState,attr,omitempty"`
+	DefUIPriority     string   `xml:"w:defUIPriority,attr,omitempty"`
+	DefSemiHidden     string   `xml:"w:defSemiHidden,attr,omitempty"`
+	DefUnhideWhenUsed string   `xml:"w:defUnhideWhenUsed,attr,omitempty"`
+	DefQFormat        string   `xml:"w:defQFormat,attr,omitempty"`
+	Count             string   `xml:"w:count,attr,omitempty"`
+	Exceptions        []*LsdException
+}
+
+// LsdException <w:lsdException> is the behaviour of one latent style
+type LsdException struct {
+	XMLName        xml.Name `xml:"w:lsdException"`
+	Name           string   `xml:"w:name,attr"`
+	Locked         string   `xml:"w:locked,attr,omitempty"`
+	UIPriority     string   `xml:"w:uiPriority,attr,omitempty"`
+	SemiHidden     string   `xml:"w:semiHidden,attr,omitempty"`
+	UnhideWhenUsed string   `xml:"w:unhideWhenUsed,attr,omitempty"`
+	QFormat        string   `xml:"w:qFormat,attr,omitempty"`
+}
+
+// StyleDefinition <w:style> is the definition of a style
+type StyleDefinition struct {
+	Type        _styleType
+	StyleID     string
+	Default     bool // Default tells whether it is the default style of its type
+	CustomStyle bool // CustomStyle tells whether it is a user defined style
+
+	Name           string
+	Aliases        string
+	BasedOn        string // BasedOn is the id of the parent style
+	Next           string // Next is the id of the style of the following paragraph
+	Link           string // Link is the id of the linked paragraph or character style
+	AutoRedefine   bool
+	Hidden         bool
+	UIPriority     string
+	SemiHidden     bool
+	UnhideWhenUsed bool
+	QFormat        bool
+	Locked         bool
+
+	ParagraphProperties *ParagraphProperties
+	RunProperties       *RunProperties
+	TableProperties     *WTableProperties
+	RowProperties       *WTableRowProperties
+	CellProperties      *WTableCellProperties
+	TableStyles         []*TableStyleProperties
+
+	items []*rawElement // items are the other children kept as they are
+
+	file *Docx
+}
+
+// TableStyleProperties <w:tblStylePr> is the conditional formatting
+// of a part of the tables (first row, last column, odd bands...)
+type TableStyleProperties struct {
+	XMLName             xml.Name `xml:"w:tblStylePr"`
+	Type                string   `xml:"w:type,attr"`
+	ParagraphProperties *ParagraphProperties
+	RunProperties       *RunProperties
+	TableProperties     *WTableProperties
+	RowProperties       *WTableRowProperties
+	CellProperties      *WTableCellProperties
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (s *Styles) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	ns := nsPrefixes(nil, start.Attr)
+	ns[XMLNS_W] = "w"
+	s.attrs = prefixAttrs(start.Attr, ns)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Space != XMLNS_W {
+				r, err := readRawElement(d, tt, ns)
+				if err != nil {
+					return err
+				}
+				s.items = append(s.items, r)
+				continue
+			}
+			switch tt.Name.Local {
+			case "docDefaults":
+				var value DocDefaults
+				value.file = s.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !s.file.tolerated(err) {
+					return err
+				}
+				s.DocDefaults = &value
+			case "latentStyles":
+				var value LatentStyles
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !s.file.tolerated(err) {
+					return err
+				}
+				s.LatentStyles = &value
+			case "style":
+				value := StyleDefinition{}
+				value.file = s.file
+				err = value.unmarshal(d, tt, ns)
+				if err != nil && !s.file.tolerated(err) {
+					return err
+				}
+				s.Styles = append(s.Styles, &value)
+			default:
+				r, err := readRawElement(d, tt, ns)
+				if err != nil {
+					return err
+				}
+				s.items = append(s.items, r)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML ...
+func (s *Styles) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: xml.Name{Local: "w:styles"}, Attr: s.attrs}
+	if len(start.Attr) == 0 {
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "xmlns:w"}, Value: XMLNS_W}}
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if s.DocDefaults != nil {
+		err = e.Encode(s.DocDefaults)
+		if err != nil {
+			return err
+		}
+	}
+	if s.LatentStyles != nil {
+		err = e.Encode(s.LatentStyles)
+		if err != nil {
+			return err
+		}
+	}
+	for _, st := range s.Styles {
+		err = e.Encode(st)
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range s.items {
+		err = e.Encode(item)
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML ...
+func (dd *DocDefaults) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "rPrDefault", "pPrDefault":
+				// the properties are inside
+			case "rPr":
+				var value RunProperties
+				value.file = dd.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !dd.file.tolerated(err) {
+					return err
+				}
+				dd.RunProperties = &value
+			case "pPr":
+				var value ParagraphProperties
+				value.file = dd.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !dd.file.tolerated(err) {
+					return err
+				}
+				dd.ParagraphProperties = &value
+			default:
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML ...
+func (l *LatentStyles) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, at := range start.Attr {
+		switch at.Name.Local {
+		case "defLockedState":
+			l.DefLockedState = at.Value
+		case "defUIPriority":
+			l.DefUIPriority = at.Value
+		case "defSemiHidden":
+			l.DefSemiHidden = at.Value
+		case "defUnhideWhenUsed":
+			l.DefUnhideWhenUsed = at.Value
+		case "defQFormat":
+			l.DefQFormat = at.Value
+		case "count":
+			l.Count = at.Value
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local == "lsdException" {
+				// the attributes are read in one pass, there are hundreds of exceptions
+				e := new(LsdException)
+				for _, at := range tt.Attr {
+					switch at.Name.Local {
+					case "name":
+						e.Name = at.Value
+					case "locked":
+						e.Locked = at.Value
+					case "uiPriority":
+						e.UIPriority = at.Value
+					case "semiHidden":
+						e.SemiHidden = at.Value
+					case "unhideWhenUsed":
+						e.UnhideWhenUsed = at.Value
+					case "qFormat":
+						e.QFormat = at.Value
+					}
+				}
+				l.Exceptions = append(l.Exceptions, e)
+			}
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshal reads the style, ns are the namespaces of the part
+func (st *StyleDefinition) unmarshal(d *xml.Decoder, start xml.StartElement, ns map[string]string) error {
+	for _, at := range start.Attr {
+		switch at.Name.Local {
+		case "type":
+			st.Type = _styleType(at.Value)
+		case "styleId":
+			st.StyleID = at.Value
+		case "default":
+			st.Default = isOn(at.Value, false)
+		case "customStyle":
+			st.CustomStyle = isOn(at.Value, false)
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := t.(xml.EndElement); ok {
+			break // the children are consumed, it is </w:style>
+		}
+		tt, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		val := getAtt(tt.Attr, "val")
+		switch tt.Name.Local {
+		case "name":
+			st.Name = val
+		case "aliases":
+			st.Aliases = val
+		case "basedOn":
+			st.BasedOn = val
+		case "next":
+			st.Next = val
+		case "link":
+			st.Link = val
+		case "autoRedefine":
+			st.AutoRedefine = isOn(val, true)
+		case "hidden":
+			st.Hidden = isOn(val, true)
+		case "uiPriority":
+			st.UIPriority = val
+		case "semiHidden":
+			st.SemiHidden = isOn(val, true)
+		case "unhideWhenUsed":
+			st.UnhideWhenUsed = isOn(val, true)
+		case "qFormat":
+			st.QFormat = isOn(val, true)
+		case "locked":
+			st.Locked = isOn(val, true)
+		case "pPr":
+			var value ParagraphProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.ParagraphProperties = &value
+			continue
+		case "rPr":
+			var value RunProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.RunProperties = &value
+			continue
+		case "tblPr":
+			var value WTableProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.TableProperties = &value
+			continue
+		case "trPr":
+			var value WTableRowProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.RowProperties = &value
+			continue
+		case "tcPr":
+			var value WTableCellProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.CellProperties = &value
+			continue
+		case "tblStylePr":
+			var value TableStyleProperties
+			value.file = st.file
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !st.file.tolerated(err) {
+				return err
+			}
+			st.TableStyles = append(st.TableStyles, &value)
+			continue
+		default:
+			r, err := readRawElement(d, tt, ns)
+			if err != nil {
+				return err
+			}
+			st.items = append(st.items, r)
+			continue
+		}
+		err = d.Skip()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalXML ...
+func (st *StyleDefinition) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: xml.Name{Local: "w:style"}}
+	if st.Type != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:type"}, Value: string(st.Type)})
+	}
+	if st.Default {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:default"}, Value: "1"})
+	}
+	if st.CustomStyle {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:customStyle"}, Value: "1"})
+	}
+	if st.StyleID != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:styleId"}, Value: st.StyleID})
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	vals := make([]*rawElement, 0, 16)
+	for _, v := range []struct{ name, val string }{
+		{"w:name", st.Name}, {"w:aliases", st.Aliases}, {"w:basedOn", st.BasedOn},
+		{"w:next", st.Next}, {"w:link", st.Link}, {"w:uiPriority", st.UIPriority},
+	} {
+		if v.val != "" {
+			vals = append(vals, newRawVal(v.name, v.val))
+		}
+	}
+	for _, v := range []struct {
+		name string
+		on   bool
+	}{
+		{"w:autoRedefine", st.AutoRedefine}, {"w:hidden", st.Hidden}, {"w:semiHidden", st.SemiHidden},
+		{"w:unhideWhenUsed", st.UnhideWhenUsed}, {"w:qFormat", st.QFormat}, {"w:locked", st.Locked},
+	} {
+		if v.on {
+			vals = append(vals, newRawFlag(v.name))
+		}
+	}
+	items := mergeByOrder(st.items, vals, styleOrderIdx)
+	// the elements before the properties, the unknown ones are written last
+	props := styleOrderIdx["w:pPr"]
+	for _, item := range items {
+		if i, ok := styleOrderIdx[item.name()]; ok && i < props {
+			err = e.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if st.ParagraphProperties != nil {
+		err = e.Encode(st.ParagraphProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if st.RunProperties != nil {
+		err = e.Encode(st.RunProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if st.TableProperties != nil {
+		err = e.Encode(st.TableProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if st.RowProperties != nil {
+		err = e.Encode(st.RowProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if st.CellProperties != nil {
+		err = e.Encode(st.CellProperties)
+		if err != nil {
+			return err
+		}
+	}
+	for _, p := range st.TableStyles {
+		err = e.Encode(p)
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if i, ok := styleOrderIdx[item.name()]; !ok || i >= props {
+			err = e.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML ...
+func (t *TableStyleProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	t.Type = getAtt(start.Attr, "type")
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := tok.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "pPr":
+				t.ParagraphProperties = new(ParagraphProperties)
+				t.ParagraphProperties.file = t.file
+				err = d.DecodeElement(t.ParagraphProperties, &tt)
+			case "rPr":
+				t.RunProperties = new(RunProperties)
+				t.RunProperties.file = t.file
+				err = d.DecodeElement(t.RunProperties, &tt)
+			case "tblPr":
+				t.TableProperties = new(WTableProperties)
+				t.TableProperties.file = t.file
+				err = d.DecodeElement(t.TableProperties, &tt)
+			case "trPr":
+				t.RowProperties = new(WTableRowProperties)
+				t.RowProperties.file = t.file
+				err = d.DecodeElement(t.RowProperties, &tt)
+			case "tcPr":
+				t.CellProperties = new(WTableCellProperties)
+				t.CellProperties.file = t.file
+				err = d.DecodeElement(t.CellProperties, &tt)
+			default:
+				err = d.Skip() // skip unsupported tags
+			}
+			if err != nil && !t.file.tolerated(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newRawVal returns an element named name with a w:val attribute
+func newRawVal(name, val string) *rawElement {
+	n := xml.Name{Local: name}
+	return &rawElement{tokens: []xml.Token{
+		xml.StartElement{Name: n, Attr: []xml.Attr{{Name: xml.Name{Local: "w:val"}, Value: val}}},
+		xml.EndElement{Name: n},
+	}}
+}
+
+// isOn tells whether the on/off value is on, def is the value when it is missing
+func isOn(val string, def bool) bool {
+	switch val {
+	case "":
+		return def
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
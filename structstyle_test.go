@@ -0,0 +1,85 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStylesRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	s := w.Styles()
+	if s.LatentStyles == nil || s.LatentStyles.Count != "376" || len(s.LatentStyles.Exceptions) == 0 {
+		t.Fatalf("unexpected latent styles %+v", s.LatentStyles)
+	}
+	if s.DocDefaults == nil || s.DocDefaults.RunProperties == nil {
+		t.Fatal("document defaults not found")
+	}
+	normal := s.Default(STYLE_PARAGRAPH)
+	if normal == nil || normal.StyleID != "a" || s.StyleByName("Normal") != normal {
+		t.Fatalf("unexpected default paragraph style %+v", normal)
+	}
+
+	heading := w.AddParagraphStyle("MyHeading", "My Heading")
+	heading.RunProperties.Bold = &Bold{}
+	heading.Next = normal.StyleID
+	quote := w.AddParagraphStyle("MyQuote", "My Quote")
+	quote.BasedOn = heading.StyleID
+	if w.AddParagraphStyle("MyHeading", "Other") != heading {
+		t.Fatal("style added twice")
+	}
+	w.AddCharacterStyle("MyEmphasis", "My Emphasis").RunProperties.Italic = &Italic{}
+	w.AddTableStyle("MyGrid", "My Grid").TableStyles = []*TableStyleProperties{
+		{Type: "firstRow", RunProperties: &RunProperties{Bold: &Bold{}}},
+	}
+	w.AddParagraph().Style("MyQuote").AddText("quoted")
+
+	buf := bytes.NewBuffer(nil)
+	_, err := w.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = doc.Styles()
+	chain := s.Inheritance("MyQuote")
+	if len(chain) != 3 || chain[1].StyleID != "MyHeading" || chain[2] != s.Default(STYLE_PARAGRAPH) {
+		t.Fatalf("unexpected inheritance %v", chain)
+	}
+	if !chain[0].CustomStyle || !chain[0].QFormat || chain[1].RunProperties.Bold == nil || s.NextStyle(chain[1]).StyleID != "a" {
+		t.Fatalf("unexpected style %+v", chain[1])
+	}
+	if st := s.Style("MyEmphasis"); st == nil || st.Type != STYLE_CHARACTER || st.RunProperties.Italic == nil || st.BasedOn != "a0" {
+		t.Fatalf("unexpected character style %+v", st)
+	}
+	if st := s.Style("MyGrid"); st == nil || len(st.TableStyles) != 1 || st.TableStyles[0].Type != "firstRow" || st.TableStyles[0].RunProperties.Bold == nil {
+		t.Fatalf("unexpected table style %+v", st)
+	}
+	if s.LatentStyles == nil || len(s.LatentStyles.Exceptions) != len(w.Styles().LatentStyles.Exceptions) {
+		t.Fatal("latent styles not kept")
+	}
+
+	// a loop in basedOn does not hang
+	s.Style("MyHeading").BasedOn = "MyQuote"
+	if len(s.Inheritance("MyQuote")) != 2 {
+		t.Fatal("unexpected inheritance with a loop")
+	}
+}
@@ -34,16 +34,8 @@ type Table struct {
 	Grid       *WTableGrid
 	Rows       []*WTableRow
 
-	confStyle struct {
-		firstRow *WTableConfStyle
-		firstCol *WTableConfStyle
-		lastRow  *WTableConfStyle
-		lastCol  *WTableConfStyle
-		oddHBand *WTableConfStyle
-		oddVBand *WTableConfStyle
-		none     *WTableConfStyle
-	}
-	file *Docx
+	items []keptItem // items are the unsupported elements kept between the rows
+	file  *Docx
 }
 
 func (t *Table) String() string {
@@ -76,107 +68,155 @@ func (t *Table) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		return nil
 	}
 
+	// the conditional styles are computed from the current rows and look
+	cs := t.confStyles()
+	err := t.encodeHead(e)
+	if err != nil {
+		return err
+	}
+	next := 0
+	for i, r := range t.Rows {
+		next, err = encodeKept(e, t.items, next, i)
+		if err != nil {
+			return err
+		}
+		err = e.Encode(cs.styledRow(i, r, i == len(t.Rows)-1))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = encodeKept(e, t.items, next, len(t.Rows))
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "w:tbl"}})
+}
+
+// encodeHead writes the start of the table before its rows
+func (t *Table) encodeHead(e *xml.Encoder) error {
+	err := e.EncodeToken(xml.StartElement{Name: xml.Name{Local: "w:tbl"}})
+	if err != nil {
+		return err
+	}
+	if t.Properties != nil {
+		err = e.Encode(t.Properties)
+		if err != nil {
+			return err
+		}
+	}
+	if t.Grid != nil {
+		return e.Encode(t.Grid)
+	}
+	return nil
+}
+
+// styledRow returns the row r at index i as it is written: a copy with the
+// conditional styles and a paragraph in the empty cells, r is not changed
+func (cs *tableConfStyles) styledRow(i int, r *WTableRow, last bool) *WTableRow {
+	row := *r
+	row.Cells = make([]*WTableCell, len(r.Cells))
+	for j, c := range r.Cells {
+		cell := *c
+		// a cell must have a paragraph
+		if len(cell.Paragraphs) == 0 {
+			cell.Paragraphs = []*Paragraph{{file: c.file}}
+		}
+		row.Cells[j] = &cell
+	}
+	if cs == nil {
+		return &row
+	}
+
 	oddH := 0
-	if t.confStyle.firstRow != nil {
+	if cs.firstRow != nil {
 		oddH = 1
 	}
 	oddV := 0
-	if t.confStyle.firstCol != nil {
+	if cs.firstCol != nil {
 		oddV = 1
 	}
 
-	for i, r := range t.Rows {
-		if t.confStyle.oddHBand != nil {
-			if i%2 == oddH {
-				if r.Properties == nil {
-					r.Properties = &WTableRowProperties{}
-				}
-				r.Properties.ConfStyle = t.confStyle.oddHBand
-			}
+	var rs *WTableConfStyle
+	if cs.oddHBand != nil && i%2 == oddH {
+		rs = cs.oddHBand
+	}
+	if last && cs.lastRow != nil {
+		rs = cs.lastRow
+	}
+	if i == 0 && cs.firstRow != nil {
+		rs = cs.firstRow
+	}
+	if r.Properties != nil || rs != nil {
+		props := WTableRowProperties{}
+		if r.Properties != nil {
+			props = *r.Properties
 		}
+		props.ConfStyle = rs
+		row.Properties = &props
+	}
 
-		if r.Cells != nil && len(r.Cells) > 0 {
-			ov := 0
-
-			for j, c := range r.Cells {
-				// add paragraph if forbidden
-				if c.Paragraphs == nil {
-					c.AddParagraph()
-				}
-
-				var cs *WTableConfStyle = nil
-
-				// vertical band management first in order to be overwritten later
-				// ov is the odd vertical position with span applied
-				if t.confStyle.oddVBand != nil && ov%2 == oddV {
-					cs = t.confStyle.oddVBand
-				}
-				if c.Properties.GridSpan != nil {
-					ov += c.Properties.GridSpan.Val
-				} else {
-					ov++
-				}
-
-				fc_lc := false
-
-				lc := len(r.Cells)
-				if t.confStyle.lastCol != nil {
-					lc--
-					fc_lc = true
-				}
+	lc := len(row.Cells)
+	fc_lc := false
+	if cs.lastCol != nil {
+		lc--
+		fc_lc = true
+	}
+	fc := -1
+	if cs.firstCol != nil {
+		fc = 0
+		fc_lc = true
+	}
 
-				fc := -1
-				if t.confStyle.firstCol != nil {
-					fc = 0
-					fc_lc = true
-				}
+	// ov is the odd vertical position with span applied
+	ov := 0
+	for j, c := range row.Cells {
+		var ccs *WTableConfStyle
 
-				if j == fc {
-					cs = t.confStyle.firstCol
-				} else {
-					if j < lc {
-						if fc_lc {
-							for _, p := range c.Paragraphs {
-								if p.Properties == nil {
-									p.Properties = &ParagraphProperties{}
-								}
-								if i%2 == oddH {
-									p.Properties.ConfStyle = t.confStyle.oddHBand
-								} else {
-									p.Properties.ConfStyle = t.confStyle.none
-								}
-							}
-						}
-					} else {
-						cs = t.confStyle.lastCol
-					}
-				}
+		// vertical band management first in order to be overwritten later
+		if cs.oddVBand != nil && ov%2 == oddV {
+			ccs = cs.oddVBand
+		}
+		if c.Properties != nil && c.Properties.GridSpan != nil {
+			ov += c.Properties.GridSpan.Val
+		} else {
+			ov++
+		}
 
-				c.Properties.ConfStyle = cs
+		if j == fc {
+			ccs = cs.firstCol
+		} else if j >= lc {
+			ccs = cs.lastCol
+		} else if fc_lc {
+			ps := cs.none
+			if i%2 == oddH {
+				ps = cs.oddHBand
 			}
-		}
-		if t.confStyle.lastRow != nil {
-			lr := len(t.Rows) - 1
-			if t.Rows[lr].Properties == nil {
-				t.Rows[lr].Properties = &WTableRowProperties{}
+			paragraphs := make([]*Paragraph, len(c.Paragraphs))
+			for k, p := range c.Paragraphs {
+				np := *p
+				props := ParagraphProperties{}
+				if p.Properties != nil {
+					props = *p.Properties
+				}
+				props.ConfStyle = ps
+				np.Properties = &props
+				paragraphs[k] = &np
 			}
-			t.Rows[lr].Properties.ConfStyle = t.confStyle.lastRow
+			c.Paragraphs = paragraphs
 		}
-		if t.confStyle.firstRow != nil {
-			if t.Rows[0].Properties == nil {
-				t.Rows[0].Properties = &WTableRowProperties{}
-			}
-			t.Rows[0].Properties.ConfStyle = t.confStyle.firstRow
+
+		props := WTableCellProperties{}
+		if c.Properties != nil {
+			props = *c.Properties
 		}
+		props.ConfStyle = ccs
+		c.Properties = &props
 	}
-
-	type _t Table
-
-	return e.Encode((*_t)(t))
+	return &row
 }
 
 // UnmarshalXML implements the xml.Unmarshaler interface.
-func (t *Table) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (t *Table) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		token, err := d.Token()
 		if err == io.EOF {
@@ -185,13 +225,16 @@ func (t *Table) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(token, start) {
+			break
+		}
 		if tt, ok := token.(xml.StartElement); ok {
 			switch tt.Name.Local {
 			case "tr":
 				var value WTableRow
 				value.file = t.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 				t.Rows = append(t.Rows, &value)
@@ -200,27 +243,28 @@ func (t *Table) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				}
 			case "tblPr":
 				t.Properties = new(WTableProperties)
+				t.Properties.file = t.file
 				err = d.DecodeElement(t.Properties, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			case "tblGrid":
 				t.Grid = new(WTableGrid)
+				t.Grid.file = t.file
 				err = d.DecodeElement(t.Grid, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				t.items = append(t.items, keptItem{at: len(t.Rows), raw: raw})
 			}
 		}
 	}
 
-	t.setConfStyle()
 	return nil
 }
 
@@ -233,14 +277,42 @@ type WTableProperties struct {
 	Justification *Justification `xml:"w:jc,omitempty"`
 	Borders       *WTableBorders `xml:"w:tblBorders"`
 	Look          *WTableLook
+
+	items []*rawElement // items are the unsupported properties kept as read
+
+	file *Docx
 }
 
+// tblPrOrder is the sequence of the children of <w:tblPr> (CT_TblPr)
+var tblPrOrder = orderIndex(
+	"w:tblStyle", "w:tblpPr", "w:tblOverlap", "w:bidiVisual", "w:tblStyleRowBandSize",
+	"w:tblStyleColBandSize", "w:tblW", "w:jc", "w:tblCellSpacing", "w:tblInd", "w:tblBorders",
+	"w:shd", "w:tblLayout", "w:tblCellMar", "w:tblLook", "w:tblCaption", "w:tblDescription",
+	"*", "w:tblPrChange",
+)
+
 func (t *WTableProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if t.Position == nil && t.Style == nil && t.Width == nil && t.Justification == nil && t.Borders == nil && t.Look == nil {
+	if t.Position == nil && t.Style == nil && t.Width == nil && t.Justification == nil && t.Borders == nil && t.Look == nil &&
+		len(t.items) == 0 {
 		return nil
 	}
-	type _t WTableProperties
-	return e.Encode((*_t)(t))
+	start = xml.StartElement{Name: xml.Name{Local: "w:tblPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = encodeOrdered(e, tblPrOrder, []orderedChild{
+		{"w:tblStyle", t.Style},
+		{"w:tblpPr", t.Position},
+		{"w:tblW", t.Width},
+		{"w:jc", t.Justification},
+		{"w:tblBorders", t.Borders},
+		{"w:tblLook", t.Look},
+	}, t.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
 }
 
 // UnmarshalXML implements the xml.Unmarshaler interface.
@@ -258,19 +330,19 @@ func (t *WTableProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) erro
 			case "tblpPr":
 				t.Position = new(WTablePositioningProperties)
 				err = d.DecodeElement(t.Position, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			case "tblStyle":
 				t.Style = new(WTableStyle)
 				err = d.DecodeElement(t.Style, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			case "tblW":
 				t.Width = new(WTableWidth)
 				err = d.DecodeElement(t.Width, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			case "jc":
@@ -289,21 +361,21 @@ func (t *WTableProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) erro
 			case "tblLook":
 				t.Look = new(WTableLook)
 				err = d.DecodeElement(t.Look, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			case "tblBorders":
 				t.Borders = new(WTableBorders)
 				err = d.DecodeElement(t.Borders, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				t.items = append(t.items, raw)
 			}
 		}
 	}
@@ -469,6 +541,8 @@ func (t *WTableLook) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 type WTableGrid struct {
 	XMLName  xml.Name    `xml:"w:tblGrid,omitempty"`
 	GridCols []*WGridCol `xml:"w:gridCol,omitempty"`
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -487,7 +561,7 @@ func (t *WTableGrid) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "gridCol":
 				var gc WGridCol
 				err := d.DecodeElement(&gc, &el)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
 					return err
 				}
 				t.GridCols = append(t.GridCols, &gc)
@@ -536,12 +610,15 @@ type WTableRow struct {
 	Properties *WTableRowProperties
 	Cells      []*WTableCell
 
+	attrs []xml.Attr // attrs are the attributes kept as read (rsid, w14:paraId...)
+	items []keptItem // items are the unsupported elements kept between the cells
 	file  *Docx
 	table *Table
 }
 
 // UnmarshalXML ...
-func (w *WTableRow) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (w *WTableRow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	w.attrs = extraAttrs(start.Attr)
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -553,20 +630,24 @@ func (w *WTableRow) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 
 		if w.Properties == nil {
 			w.Properties = new(WTableRowProperties)
+			w.Properties.file = w.file
+		}
+		if isEnd(t, start) {
+			break
 		}
 
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
 			case "trPr":
 				err = d.DecodeElement(w.Properties, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 			case "tc":
 				var value WTableCell
 				value.file = w.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !w.file.tolerated(err) {
 					return err
 				}
 				w.Cells = append(w.Cells, &value)
@@ -574,32 +655,90 @@ func (w *WTableRow) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 					c.row = w
 				}
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				w.items = append(w.items, keptItem{at: len(w.Cells), raw: raw})
 			}
 		}
 	}
 	return nil
 }
 
+// MarshalXML ...
+func (w *WTableRow) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "w:tr"}, Attr: w.attrs}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if w.Properties != nil {
+		err = e.Encode(w.Properties)
+		if err != nil {
+			return err
+		}
+	}
+	next := 0
+	for i, c := range w.Cells {
+		next, err = encodeKept(e, w.items, next, i)
+		if err != nil {
+			return err
+		}
+		err = e.Encode(c)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = encodeKept(e, w.items, next, len(w.Cells))
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
 // WTableRowProperties represents the properties of a row within a table.
 type WTableRowProperties struct {
 	XMLName       xml.Name `xml:"w:trPr,omitempty"`
 	Height        *WTableRowHeight
 	Justification *Justification
 	ConfStyle     *WTableConfStyle
+	Ins           *RevisionMark // Ins tracks the insertion of the row
+	Del           *RevisionMark // Del tracks the deletion of the row
+
+	items []*rawElement // items are the unsupported properties kept as read
+
+	file *Docx
 }
 
+// trPrOrder is the order of the children of <w:trPr> (CT_TrPr)
+var trPrOrder = orderIndex(
+	"w:cnfStyle", "w:divId", "w:gridBefore", "w:gridAfter", "w:wBefore", "w:wAfter", "w:cantSplit",
+	"w:trHeight", "w:tblHeader", "w:tblCellSpacing", "w:jc", "w:hidden", "w:ins", "w:del",
+	"*", "w:trPrChange",
+)
+
 // MarshalXML ...
 func (t *WTableRowProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if t.Height == nil && t.Justification == nil && t.ConfStyle == nil {
+	if t.Height == nil && t.Justification == nil && t.ConfStyle == nil && t.Ins == nil && t.Del == nil && len(t.items) == 0 {
 		return nil
 	}
-	type _t WTableRowProperties
-	return e.Encode((*_t)(t))
+	start = xml.StartElement{Name: xml.Name{Local: "w:trPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = encodeOrdered(e, trPrOrder, []orderedChild{
+		{"w:cnfStyle", t.ConfStyle},
+		{"w:trHeight", t.Height},
+		{"w:jc", t.Justification},
+		{"w:ins", t.Ins},
+		{"w:del", t.Del},
+	}, t.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
 }
 
 // UnmarshalXML ...
@@ -646,17 +785,29 @@ func (t *WTableRowProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				if err != nil {
 					return err
 				}
-			case "confStyle":
+			case "cnfStyle", "confStyle":
 				t.ConfStyle = new(WTableConfStyle)
 				err = d.DecodeElement(t.ConfStyle, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !t.file.tolerated(err) {
+					return err
+				}
+			case "ins", "del":
+				var value RevisionMark
+				err = d.DecodeElement(&value, &tt)
+				if err != nil {
 					return err
 				}
+				if tt.Name.Local == "ins" {
+					t.Ins = &value
+				} else {
+					t.Del = &value
+				}
 			default:
-				err = d.Skip()
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
+				t.items = append(t.items, raw)
 			}
 		}
 	}
@@ -736,12 +887,13 @@ type WTableCell struct {
 	Paragraphs []*Paragraph `xml:"w:p,omitempty"`
 	Tables     []*Table     `xml:"w:tbl,omitempty"`
 
-	row  *WTableRow
-	file *Docx
+	items []keptItem // items are the unsupported elements kept between the paragraphs
+	row   *WTableRow
+	file  *Docx
 }
 
 // UnmarshalXML ...
-func (c *WTableCell) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (c *WTableCell) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -752,7 +904,10 @@ func (c *WTableCell) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		}
 
 		if c.Properties == nil {
-			c.Properties = &WTableCellProperties{}
+			c.Properties = &WTableCellProperties{file: c.file}
+		}
+		if isEnd(t, start) {
+			break
 		}
 
 		if tt, ok := t.(xml.StartElement); ok {
@@ -760,35 +915,72 @@ func (c *WTableCell) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			case "p":
 				var value Paragraph
 				value.file = c.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = value.UnmarshalXML(d, tt)
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				c.Paragraphs = append(c.Paragraphs, &value)
 			case "tcPr":
-				err = d.DecodeElement(c.Properties, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				err = c.Properties.UnmarshalXML(d, tt)
+				if err != nil && !c.file.tolerated(err) {
 					return err
 				}
 			case "tbl":
 				var table Table
 				table.file = c.file
-				if err = d.DecodeElement(&table, &tt); err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err = table.UnmarshalXML(d, tt); err != nil && !c.file.tolerated(err) {
 					return err
 				}
 				c.Tables = append(c.Tables, &table)
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				c.items = append(c.items, keptItem{at: len(c.Paragraphs), raw: raw})
 			}
 		}
 	}
 	return nil
 }
 
+// MarshalXML ...
+func (c *WTableCell) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "w:tc"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	if c.Properties != nil {
+		err = e.Encode(c.Properties)
+		if err != nil {
+			return err
+		}
+	}
+	next := 0
+	for i, p := range c.Paragraphs {
+		next, err = encodeKept(e, c.items, next, i)
+		if err != nil {
+			return err
+		}
+		err = e.Encode(p)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = encodeKept(e, c.items, next, len(c.Paragraphs))
+	if err != nil {
+		return err
+	}
+	for _, t := range c.Tables {
+		err = e.Encode(t)
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
 // WTableCellProperties represents the properties of a table cell.
 type WTableCellProperties struct {
 	XMLName   xml.Name `xml:"w:tcPr,omitempty"`
@@ -799,20 +991,47 @@ type WTableCellProperties struct {
 	Borders   *WTableCellBorders `xml:"w:tcBorders"`
 	Shade     *Shade
 	VAlign    *WVerticalAlignment
+
+	items []*rawElement // items are the unsupported properties kept as read
+
+	file *Docx
 }
 
+// tcPrOrder is the sequence of the children of <w:tcPr> (CT_TcPr)
+var tcPrOrder = orderIndex(
+	"w:cnfStyle", "w:tcW", "w:gridSpan", "w:hMerge", "w:vMerge", "w:tcBorders", "w:shd", "w:noWrap",
+	"w:tcMar", "w:textDirection", "w:tcFitText", "w:vAlign", "w:hideMark", "w:headers", "w:cellIns",
+	"w:cellDel", "w:cellMerge", "*", "w:tcPrChange",
+)
+
 // MarshalXML ...
 func (p *WTableCellProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if p.ConfStyle == nil && p.Width == nil && p.VMerge == nil && p.GridSpan == nil &&
-		p.Borders == nil && p.Shade == nil && p.VAlign == nil {
+		p.Borders == nil && p.Shade == nil && p.VAlign == nil && len(p.items) == 0 {
 		return nil
 	}
-	type _t WTableCellProperties
-	return e.Encode((*_t)(p))
+	start = xml.StartElement{Name: xml.Name{Local: "w:tcPr"}}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = encodeOrdered(e, tcPrOrder, []orderedChild{
+		{"w:cnfStyle", p.ConfStyle},
+		{"w:tcW", p.Width},
+		{"w:gridSpan", p.GridSpan},
+		{"w:vMerge", p.VMerge},
+		{"w:tcBorders", p.Borders},
+		{"w:shd", p.Shade},
+		{"w:vAlign", p.VAlign},
+	}, p.items)
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
 }
 
 // UnmarshalXML ...
-func (p *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (p *WTableCellProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -821,20 +1040,28 @@ func (p *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.StartElement); ok {
 			switch tt.Name.Local {
 			case "tcW":
 				p.Width = new(WTableCellWidth)
-				v := getAtt(tt.Attr, "w")
-				if v == "" {
-					continue
-				}
-				p.Width.W, err = GetInt(v)
-				if err != nil {
-					return err
+				for _, attr := range tt.Attr {
+					switch attr.Name.Local {
+					case "w":
+						if attr.Value == "" {
+							continue
+						}
+						p.Width.W, err = GetInt(attr.Value)
+						if err != nil {
+							return err
+						}
+					case "type":
+						p.Width.Type = attr.Value
+					}
 				}
-				p.Width.Type = getAtt(tt.Attr, "type")
 			case "vMerge":
 				p.VMerge = &WvMerge{Val: getAtt(tt.Attr, "val")}
 			case "gridSpan":
@@ -853,28 +1080,28 @@ func (p *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 			case "tcBorders":
 				p.Borders = new(WTableCellBorders)
 				err = d.DecodeElement(p.Borders, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			case "shd":
 				var value Shade
 				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 				p.Shade = &value
-			case "confStyle":
+			case "cnfStyle", "confStyle":
 				p.ConfStyle = new(WTableConfStyle)
 				err = d.DecodeElement(p.ConfStyle, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !p.file.tolerated(err) {
 					return err
 				}
 			default:
-				err = d.Skip() // skip unsupported tags
+				raw, err := readUnknown(d, tt)
 				if err != nil {
 					return err
 				}
-				continue
+				p.items = append(p.items, raw)
 			}
 		}
 	}
@@ -950,10 +1177,10 @@ type WTableCellBorders struct {
 func UnmarhalXMLBorder(d *xml.Decoder, tt xml.StartElement) (*WTableBorder, error) {
 	value := &WTableBorder{}
 	err := d.DecodeElement(value, &tt)
-	if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+	if err != nil {
 		return nil, err
 	}
-	return value, err
+	return value, nil
 }
 
 // UnmarshalXML ...
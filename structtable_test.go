@@ -21,43 +21,35 @@
 package docx
 
 import (
+	"bytes"
 	"encoding/xml"
 	"hash/crc64"
 	"io"
 	"os"
+	"strings"
 	"testing"
 )
 
 func TestTableStructure(t *testing.T) {
-
-	borderColors := &APITableBorderColors{
-		"#ff0000",
-		"#ff0000",
-		"#ff0000",
-		"#ff0000",
-		"#ff0000",
-		"",
-	}
-
 	w := New().WithDefaultTheme()
 	// add new paragraph
 	para1 := w.AddParagraph()
 	// add text
 	para1.AddText("table")
-	tab1 := w.AddTable(4, 3, 1000, borderColors).Justification("center")
-	tab1.TableProperties.Position = &WTablePositioningProperties{LeftFromText: 2333}
-	para2 := tab1.TableRows[3].Justification("center").TableCells[2].Shade("clear", "auto", "E7E6E6").AddParagraph()
+	tab1 := w.AddTable(4, 3, 1000).Borders(TABLE_BORDER_EXTERN|TABLE_BORDER_INSIDEH, "single", "#ff0000", 4, 0).Justification("center")
+	tab1.Properties.Position = &WTablePositioningProperties{LeftFromText: 2333}
+	para2 := tab1.Rows[3].Justification("center").Cells[2].Shade("clear", "auto", "E7E6E6").AddParagraph()
 	r, err := para2.AddAnchorDrawingFrom("testdata/fumiama.JPG")
 	if err != nil {
 		t.Fatal(err)
 	}
-	tab1.TableRows[0].TableCells[0].TableCellProperties.VMerge = &WvMerge{Val: "restart"}
-	tab1.TableRows[1].TableCells[0].TableCellProperties.VMerge = &WvMerge{}
-	tab1.TableRows[2].TableCells[0].TableCellProperties.VMerge = &WvMerge{}
+	tab1.Rows[0].Cells[0].Properties.VMerge = &WvMerge{Val: "restart"}
+	tab1.Rows[1].Cells[0].Properties.VMerge = &WvMerge{}
+	tab1.Rows[2].Cells[0].Properties.VMerge = &WvMerge{}
 	r.Children[0].(*Drawing).Anchor.Graphic.GraphicData.Pic.BlipFill.Blip.AlphaModFix = &AAlphaModFix{Amount: 50000}
 	r.Children[0].(*Drawing).Anchor.Graphic.GraphicData.Pic.NonVisualPicProperties.CNvPicPr.Locks = &APicLocks{NoChangeAspect: 1}
 	r.Children[0].(*Drawing).Anchor.Graphic.GraphicData.Pic.SpPr.Xfrm.Rot = 50000
-	para3 := tab1.TableRows[0].TableCells[0].AddParagraph()
+	para3 := tab1.Rows[0].Cells[0].AddParagraph()
 	para3.AddText("first cell")
 
 	f, err := os.Create("TestMarshalTableStructure.xml")
@@ -111,3 +103,48 @@ func TestTableStructure(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestTableMarshalNoSideEffect(t *testing.T) {
+	w := New().WithDefaultTheme()
+	tbl := w.AddTable(3, 3, 9000).Style("TableGrid",
+		TABLE_STYLE_OPTION_FIRST_ROW|TABLE_STYLE_OPTION_LAST_ROW|TABLE_STYLE_OPTION_HORIZONTAL_BAND)
+	save := func() []byte {
+		data, err := xml.Marshal(tbl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	first := save()
+	if second := save(); !bytes.Equal(first, second) {
+		t.Fatal("saving twice gives different tables")
+	}
+	for _, r := range tbl.Rows {
+		if r.Properties.ConfStyle != nil {
+			t.Fatal("the row style is set by the marshalling")
+		}
+		for _, c := range r.Cells {
+			if c.Paragraphs != nil || c.Properties.ConfStyle != nil {
+				t.Fatal("the cell is changed by the marshalling")
+			}
+		}
+	}
+
+	// the styles follow the rows added after a save
+	tbl.AddRow().AddCell()
+	var got Table
+	err := xml.Unmarshal(save(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles := make([]string, len(got.Rows))
+	for i, r := range got.Rows {
+		if r.Properties != nil && r.Properties.ConfStyle != nil {
+			styles[i] = r.Properties.ConfStyle.Val
+		}
+	}
+	want := []string{"100000000000", "000000100000", "", "010000000000"}
+	if strings.Join(styles, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected row styles %q", styles)
+	}
+}
@@ -23,14 +23,14 @@ package docx
 import (
 	"encoding/xml"
 	"io"
-	"reflect"
-	"strings"
 )
 
 // Tabs ...
 type Tabs struct {
 	XMLName xml.Name `xml:"w:tabs,omitempty"`
 	Tabs    []*Tab
+
+	file *Docx
 }
 
 // UnmarshalXML ...
@@ -48,7 +48,7 @@ func (tb *Tabs) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			if tt.Name.Local == "tab" {
 				var value Tab
 				err := d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				if err != nil && !tb.file.tolerated(err) {
 					return err
 				}
 				tb.Tabs = append(tb.Tabs, &value)
@@ -134,6 +134,9 @@ func (r *Text) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		if err != nil {
 			return err
 		}
+		if isEnd(t, start) {
+			break
+		}
 
 		if tt, ok := t.(xml.CharData); ok {
 			r.Text = string(tt) // implicitly copy
@@ -151,87 +154,51 @@ func MergeAllRuns(_, _ *Run) bool {
 	return true
 }
 
+//go:generate go run gen_equal.go -fields RunProperties RunProperties RevisionMark RPrChange RunFonts Bold Italic Highlight Color Size SizeCs Spacing RunStyle Style Shade Kern Underline VertAlign Strike Lang NoProof
+
 // MergeSamePropRuns merges runs with the same properties
 func MergeSamePropRuns(r1, r2 *Run) bool {
 	if r1 == nil || r2 == nil {
 		return false
 	}
-	if r1.RunProperties == r2.RunProperties {
-		return true
-	}
-	if r1.RunProperties == nil && r2.RunProperties != nil {
-		return false
-	}
-	if r1.RunProperties != nil && r2.RunProperties == nil {
+	return r1.RunProperties.equal(r2.RunProperties)
+}
+
+// equalSlice tells whether two slices of lengths n1 and n2
+// are equal, eq compares their items at i
+func equalSlice(n1, n2 int, eq func(i int) bool) bool {
+	if n1 != n2 {
 		return false
 	}
-	rr1 := reflect.ValueOf(r1.RunProperties).Elem()
-	rr2 := reflect.ValueOf(r2.RunProperties).Elem()
-	for i := 1; i < rr1.NumField(); i++ {
-		x1 := rr1.Field(i)
-		x2 := rr2.Field(i)
-		if x1.IsZero() && x2.IsZero() {
-			continue
-		}
-		if x1.IsZero() && !x2.IsZero() {
-			return false
-		}
-		if !x1.IsZero() && x2.IsZero() {
+	for i := 0; i < n1; i++ {
+		if !eq(i) {
 			return false
 		}
-		xx1 := x1.Elem()
-		if xx1.NumField() <= 1 {
-			continue
-		}
-		xx2 := x2.Elem()
-		for j := 1; j < xx1.NumField(); j++ {
-			if !xx1.Field(j).Equal(xx2.Field(j)) {
-				return false
-			}
-		}
 	}
 	return true
 }
 
-// MergeSamePropRunsOf merges runs with the same properties of names
+// MergeSamePropRunsOf merges runs with the same properties of names,
+// the names which are not fields of RunProperties are ignored
 func MergeSamePropRunsOf(name ...string) RunMergeRule {
+	fields := make([]func(a, b *RunProperties) bool, 0, len(name))
+	for _, n := range name {
+		if eq, ok := runPropertiesFields[n]; ok {
+			fields = append(fields, eq)
+		}
+	}
 	return func(r1, r2 *Run) bool {
 		if r1 == nil || r2 == nil {
 			return false
 		}
-		if r1.RunProperties == r2.RunProperties {
-			return true
-		}
-		if r1.RunProperties == nil && r2.RunProperties != nil {
-			return false
-		}
-		if r1.RunProperties != nil && r2.RunProperties == nil {
-			return false
+		p1, p2 := r1.RunProperties, r2.RunProperties
+		if p1 == nil || p2 == nil {
+			return p1 == p2
 		}
-		rr1 := reflect.ValueOf(r1.RunProperties).Elem()
-		rr2 := reflect.ValueOf(r2.RunProperties).Elem()
-		for _, n := range name {
-			x1 := rr1.FieldByName(n)
-			x2 := rr2.FieldByName(n)
-			if x1.IsZero() && x2.IsZero() {
-				continue
-			}
-			if x1.IsZero() && !x2.IsZero() {
-				return false
-			}
-			if !x1.IsZero() && x2.IsZero() {
+		for _, eq := range fields {
+			if !eq(p1, p2) {
 				return false
 			}
-			xx1 := x1.Elem()
-			if xx1.NumField() <= 1 {
-				continue
-			}
-			xx2 := x2.Elem()
-			for j := 1; j < xx1.NumField(); j++ {
-				if !xx1.Field(j).Equal(xx2.Field(j)) {
-					return false
-				}
-			}
 		}
 		return true
 	}
@@ -306,6 +273,11 @@ func (p *Paragraph) MergeText(canmerge RunMergeRule) (np Paragraph) {
 				prevrun = &r
 				np.Children = append(np.Children, &r)
 			}
+		case *rawElement:
+			// the proofing marks do not match the merged runs
+			if o.name() != "w:proofErr" {
+				np.Children = append(np.Children, o)
+			}
 		default:
 			np.Children = append(np.Children, o)
 		}
@@ -44,6 +44,7 @@ func (f *Docx) WithA3Page() *Docx {
 			W: 16838,
 			H: 23811,
 		},
+		file: f,
 	}
 	f.Document.Body.Items = append(f.Document.Body.Items, sectpr)
 	return f
@@ -56,6 +57,7 @@ func (f *Docx) WithA4Page() *Docx {
 			W: 11906,
 			H: 16838,
 		},
+		file: f,
 	}
 	f.Document.Body.Items = append(f.Document.Body.Items, sectpr)
 	return f
@@ -22,11 +22,13 @@ package docx
 
 import (
 	"archive/zip"
+	"bufio"
 	"encoding/xml"
 	"errors"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // unpack receives a zip file (word documents are a zip with multiple xml inside)
@@ -37,13 +39,23 @@ import (
 //  3. Media
 //
 // Then it stores all other files into tmpfslist for packing.
-func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
+func unpack(zipReader *zip.Reader, opts *ParseOptions) (docx *Docx, err error) {
 	docx = new(Docx)
+	docx.parseOpts = opts
 	docx.mediaNameIdx = make(map[string]int, 64)
 	docx.slowIDs = make(map[string]uintptr, 64)
 	docx.tmplfs = zipReader
 	docx.tmpfslst = make([]string, 0, 64)
+	files := make(map[string]*zip.File, len(zipReader.File))
 	for _, f := range zipReader.File {
+		files[f.Name] = f
+		if f.Name == CONTENT_TYPES {
+			docx.contentTypes = new(ContentTypes)
+			err = docx.parseXMLFile(f, docx.contentTypes)
+			if err != nil {
+				return
+			}
+		}
 		if f.Name == "word/_rels/document.xml.rels" {
 			err = docx.parseDocRelation(f)
 			if err != nil {
@@ -52,6 +64,10 @@ func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
 			continue
 		}
 		if f.Name == "word/document.xml" {
+			// the body of a stream is read by its StreamReader
+			if opts.stream {
+				continue
+			}
 			err = docx.parseDocument(f)
 			if err != nil {
 				return
@@ -68,19 +84,370 @@ func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
 		// fill remaining files into tmpfslst
 		docx.tmpfslst = append(docx.tmpfslst, f.Name)
 	}
-	//TODO: find last imageID
-	docx.imageID = 100000
+	err = docx.parseParts(files)
+	if err != nil {
+		return
+	}
+	err = docx.loadRelationships()
+	if err != nil {
+		return
+	}
+	docx.EvaluateNumbering()
 	return
 }
 
-// parseDocument processes one of the relevant files, the one with the actual document
-func (f *Docx) parseDocument(file *zip.File) error {
+// parseParts processes the parts referred by the document relationships
+// (settings, numbering, headers, footers, notes and comments)
+func (f *Docx) parseParts(files map[string]*zip.File) error {
+	var ex *CommentsEx
+	var exName string
+	for _, r := range f.docRelation.Relationship {
+		if r.TargetMode == REL_TARGETMODE {
+			continue
+		}
+		name := relTargetPart(DOCUMENT_PART, r.Target)
+		file, ok := files[name]
+		if !ok {
+			continue
+		}
+		var err error
+		switch r.Type {
+		case REL_SETTINGS:
+			s := new(Settings)
+			err = f.parseXMLFile(file, s)
+			if err == nil {
+				f.settings = s
+			}
+		case REL_NUMBERING:
+			n := &Numbering{name: name, file: f}
+			err = f.parseXMLFile(file, n)
+			if err == nil {
+				f.numbering = n
+				f.removeTemplateFile(name)
+			}
+		case REL_HEADER:
+			h := &Header{name: name, file: f.newPart()}
+			err = h.file.parsePartRelation(files, name)
+			if err == nil {
+				h.XMLW, h.XMLR, h.XMLWP, h.XMLWPS, h.XMLWPC, h.XMLWPG = XMLNS_W, XMLNS_R, XMLNS_WP, XMLNS_WPS, XMLNS_WPC, XMLNS_WPG
+				err = f.parseXMLFile(file, h)
+			}
+			if err == nil {
+				f.headers = append(f.headers, h)
+				f.removeTemplateFile(name)
+			}
+		case REL_FOOTER:
+			h := &Footer{name: name, file: f.newPart()}
+			err = h.file.parsePartRelation(files, name)
+			if err == nil {
+				h.XMLW, h.XMLR, h.XMLWP, h.XMLWPS, h.XMLWPC, h.XMLWPG = XMLNS_W, XMLNS_R, XMLNS_WP, XMLNS_WPS, XMLNS_WPC, XMLNS_WPG
+				err = f.parseXMLFile(file, h)
+			}
+			if err == nil {
+				f.footers = append(f.footers, h)
+				f.removeTemplateFile(name)
+			}
+		case REL_FOOTNOTES, REL_ENDNOTES:
+			n := &Notes{name: name, file: f.newPart()}
+			err = n.file.parsePartRelation(files, name)
+			if err == nil {
+				n.XMLW, n.XMLR, n.XMLWP, n.XMLWPS, n.XMLWPC, n.XMLWPG = XMLNS_W, XMLNS_R, XMLNS_WP, XMLNS_WPS, XMLNS_WPC, XMLNS_WPG
+				err = f.parseXMLFile(file, n)
+			}
+			if err != nil {
+				break
+			}
+			if r.Type == REL_FOOTNOTES {
+				f.footnotes = n
+			} else {
+				f.endnotes = n
+			}
+			f.removeTemplateFile(name)
+		case REL_COMMENTS:
+			c := &Comments{name: name, file: f.newPart()}
+			err = c.file.parsePartRelation(files, name)
+			if err == nil {
+				c.XMLW, c.XMLR, c.XMLWP, c.XMLW14 = XMLNS_W, XMLNS_R, XMLNS_WP, XMLNS_W14
+				err = f.parseXMLFile(file, c)
+			}
+			if err == nil {
+				f.comments = c
+				f.removeTemplateFile(name)
+			}
+		case REL_COMMENTS_EXTENDED:
+			e := new(CommentsEx)
+			err = f.parseXMLFile(file, e)
+			if err == nil {
+				ex, exName = e, name
+			}
+		}
+		if err != nil {
+			// the part is kept as it is when it cannot be parsed in lenient mode
+			if !f.parseOpts.Lenient {
+				return err
+			}
+			f.warn(err)
+			f.keepTemplateFile(files, name)
+			f.keepTemplateFile(files, relsPartName(name))
+		}
+	}
+	if ex != nil && f.comments != nil {
+		f.comments.applyExtended(ex)
+		f.comments.exName = exName
+		f.removeTemplateFile(exName)
+	}
+	return nil
+}
+
+// parsePartRelation loads the relationships of the part name, if any
+func (f *Docx) parsePartRelation(files map[string]*zip.File, name string) error {
+	rels := relsPartName(name)
+	file, ok := files[rels]
+	if !ok {
+		return nil
+	}
+	f.root().removeTemplateFile(rels)
+	err := f.parseDocRelation(file)
+	f.docRelation.Xmlns = XMLNS_REL
+	return err
+}
+
+// keepTemplateFile puts back name, if it is in files, into the files
+// copied from the template
+func (f *Docx) keepTemplateFile(files map[string]*zip.File, name string) {
+	if _, ok := files[name]; ok && !f.hasTemplateFile(name) {
+		f.tmpfslst = append(f.tmpfslst, name)
+	}
+}
+
+// removeTemplateFile removes name from the files copied from the template
+// and tells whether it was one, the list is copied as it may be shared
+// with other documents
+func (f *Docx) removeTemplateFile(name string) bool {
+	for i, n := range f.tmpfslst {
+		if n == name {
+			lst := make([]string, 0, len(f.tmpfslst)-1)
+			lst = append(lst, f.tmpfslst[:i]...)
+			f.tmpfslst = append(lst, f.tmpfslst[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ErrTooManyParts, ErrPartTooLarge and ErrPackageTooLarge are returned
+// when a limit of the ParseOptions is exceeded
+var (
+	ErrTooManyParts    = errors.New("too many parts")
+	ErrPartTooLarge    = errors.New("part too large")
+	ErrPackageTooLarge = errors.New("package too large")
+)
+
+// checkLimits checks the sizes declared in the zip file against the limits of opts
+func checkLimits(zipReader *zip.Reader, opts *ParseOptions) error {
+	if opts.MaxParts > 0 && len(zipReader.File) > opts.MaxParts {
+		return ErrTooManyParts
+	}
+	var total uint64
+	for _, f := range zipReader.File {
+		if opts.MaxPartSize > 0 && f.UncompressedSize64 > uint64(opts.MaxPartSize) {
+			return &ParseError{Part: f.Name, Err: ErrPartTooLarge}
+		}
+		total += f.UncompressedSize64
+		if opts.MaxTotalSize > 0 && total > uint64(opts.MaxTotalSize) {
+			return ErrPackageTooLarge
+		}
+	}
+	return nil
+}
+
+// ParseError is an error found in a part of a parsed document
+type ParseError struct {
+	Part   string // Part is the name of the part
+	Path   string // Path is the path of the element in the part, e.g. body/tbl[3]/tr[2]/tc[1]/p[0]
+	Offset int64  // Offset is the offset in the part where the error is found
+	Err    error  // Err is the cause of the error
+}
+
+// Error ...
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Part)
+	if e.Path != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(e.Path)
+	}
+	if e.Offset > 0 {
+		sb.WriteString(" at offset ")
+		sb.WriteString(strconv.FormatInt(e.Offset, 10))
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+// Unwrap returns the cause of the error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Warnings returns the problems skipped while parsing
+// the document in lenient mode
+func (f *Docx) Warnings() []*ParseError {
+	return f.root().warnings
+}
+
+// warn records the problem err
+func (f *Docx) warn(err error) {
+	f = f.root()
+	pe, ok := err.(*ParseError)
+	if !ok {
+		pe = &ParseError{Err: err}
+	}
+	f.warnings = append(f.warnings, pe)
+}
+
+// parsing is a part being parsed
+type parsing struct {
+	f    *Docx
+	file *zip.File
+	d    *xml.Decoder // d is the decoder of the part
+}
+
+// tolerated tells whether the error err found while parsing the document f
+// can be skipped: the values which cannot be read are skipped unless the document
+// is parsed in strict mode and they are recorded as warnings in lenient mode,
+// the malformed xml and the errors already located (*ParseError) are never skipped
+func (f *Docx) tolerated(err error) bool {
+	switch err.(type) {
+	case *strconv.NumError, xml.UnmarshalError:
+	default:
+		return false
+	}
+	if f == nil {
+		return true
+	}
+	f = f.root()
+	if f.parsing == nil || f.parseOpts == nil {
+		return true
+	}
+	if f.parseOpts.Strict {
+		return false
+	}
+	if f.parseOpts.Lenient {
+		f.warn(f.parsing.parseError(f.parsing.d.InputOffset(), err))
+	}
+	return true
+}
+
+// parseError returns the error err found at offset in the part
+func (p *parsing) parseError(offset int64, err error) *ParseError {
+	return &ParseError{
+		Part:   p.file.Name,
+		Path:   elementPath(p.file, offset),
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+// singleElements are the elements found once in their parent,
+// they are written without index in the paths
+var singleElements = map[string]bool{
+	"body": true, "pPr": true, "rPr": true, "sectPr": true,
+	"tblPr": true, "tblGrid": true, "trPr": true, "tcPr": true,
+}
+
+// elementPath returns the path of the element found at offset in the file,
+// each element is followed by its index among its siblings of the same name
+func elementPath(file *zip.File, offset int64) string {
+	r, err := file.Open()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+	type level struct {
+		name  string
+		names map[string]int
+	}
+	stack := []level{{names: make(map[string]int, 1)}}
+	d := xml.NewDecoder(r)
+	for d.InputOffset() < offset {
+		t, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			names := stack[len(stack)-1].names
+			name := tt.Name.Local
+			i := names[name]
+			names[name] = i + 1
+			if !singleElements[name] {
+				name += "[" + strconv.Itoa(i) + "]"
+			}
+			stack = append(stack, level{name: name, names: make(map[string]int, 8)})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	// the root element is left out
+	if len(stack) < 2 {
+		return ""
+	}
+	names := make([]string, 0, len(stack)-2)
+	for _, l := range stack[2:] {
+		names = append(names, l.name)
+	}
+	return strings.Join(names, "/")
+}
+
+// readers are the buffers of the decoders, they are reused from part to part
+var readers = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewReaderSize(nil, 32<<10)
+	},
+}
+
+// newDecoder returns a decoder of r reading through a reused buffer,
+// release gives the buffer back once the decoder is not used anymore
+func newDecoder(r io.Reader) (*xml.Decoder, func()) {
+	br := readers.Get().(*bufio.Reader)
+	br.Reset(r)
+	return xml.NewDecoder(br), func() {
+		br.Reset(nil)
+		readers.Put(br)
+	}
+}
+
+// parseXMLFile unmarshals the xml file into v,
+// the errors are returned as *ParseError
+func (f *Docx) parseXMLFile(file *zip.File, v interface{}) error {
 	zf, err := file.Open()
 	if err != nil {
-		return err
+		return &ParseError{Part: file.Name, Err: err}
 	}
 	defer zf.Close()
+	d, release := newDecoder(zf)
+	defer release()
+	p := &parsing{f: f.root(), file: file, d: d}
+	prev := p.f.parsing
+	p.f.parsing = p
+	defer func() { p.f.parsing = prev }()
+	err = d.Decode(v)
+	if err != nil {
+		return p.parseError(d.InputOffset(), err)
+	}
+	return nil
+}
+
+// parseDocument processes one of the relevant files, the one with the actual document
+func (f *Docx) parseDocument(file *zip.File) error {
+	f.initDocument()
+	return f.parseXMLFile(file, &f.Document)
+}
 
+// initDocument sets the namespaces of the document before it is read
+func (f *Docx) initDocument() {
 	f.Document.XMLW = XMLNS_W
 	f.Document.XMLR = XMLNS_R
 	f.Document.XMLWP = XMLNS_WP
@@ -95,32 +462,23 @@ func (f *Docx) parseDocument(file *zip.File) error {
 	f.Document.XMLName.Local = "document"
 
 	f.Document.Body.file = f
-	//TODO: find last docID
-	f.docID = 100000
-	err = xml.NewDecoder(zf).Decode(&f.Document)
-	return err
 }
 
 // parseDocRelation processes one of the relevant files, the one with the relationships
 func (f *Docx) parseDocRelation(file *zip.File) error {
-	zf, err := file.Open()
-	if err != nil {
-		return err
-	}
-	defer zf.Close()
-
 	f.docRelation.Xmlns = XMLNS_R
-	err = xml.NewDecoder(zf).Decode(&f.docRelation)
+	err := f.parseXMLFile(file, &f.docRelation)
 	if err != nil {
 		return err
 	}
+	// any id is accepted, the new ones follow the last rIdN
 	for _, r := range f.docRelation.Relationship {
 		if !strings.HasPrefix(r.ID, "rId") {
-			return errors.New("invalid rel ID: " + r.ID)
+			continue
 		}
 		id, err := strconv.ParseUint(r.ID[3:], 10, 64)
 		if err != nil {
-			return err
+			continue
 		}
 		if f.rID < uintptr(id) {
 			f.rID = uintptr(id)
@@ -132,6 +490,11 @@ func (f *Docx) parseDocRelation(file *zip.File) error {
 // parseMedia add the media into Docx struct
 func (f *Docx) parseMedia(file *zip.File) error {
 	name := file.Name[len(MEDIA_FOLDER):]
+	if f.parseOpts != nil && f.parseOpts.LazyMedia {
+		f.mediaNameIdx[name] = len(f.media)
+		f.media = append(f.media, Media{Name: name, file: file})
+		return nil
+	}
 	zf, err := file.Open()
 	if err != nil {
 		return err
@@ -141,6 +504,6 @@ func (f *Docx) parseMedia(file *zip.File) error {
 		return err
 	}
 	f.mediaNameIdx[name] = len(f.media)
-	f.media = append(f.media, Media{Name: name, Data: data})
+	f.media = append(f.media, Media{Name: name, Data: data, file: file})
 	return zf.Close()
 }
@@ -0,0 +1,255 @@
+/*
+   Copyright (c) 2025 Philippe Duveau
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/gif"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rewriteZip returns the zip file data with the content of each file replaced by fn
+func rewriteZip(t *testing.T, data []byte, fn func(name, content string) string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = io.WriteString(fw, fn(f.Name, string(content)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestParseWithOptions(t *testing.T) {
+	var pic bytes.Buffer
+	err := gif.Encode(&pic, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := New().WithDefaultTheme()
+	_, err = w.AddParagraph().AddInlineDrawing(pic.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().Justification(JUSTIFICATION_CENTER).AddText("centered")
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := append([]byte(nil), buf.Bytes()...)
+	parse := func(data []byte, opts *ParseOptions) (*Docx, error) {
+		return ParseWithOptions(bytes.NewReader(data), int64(len(data)), opts)
+	}
+
+	for _, c := range []struct {
+		opts *ParseOptions
+		err  error
+	}{
+		{&ParseOptions{MaxParts: 3}, ErrTooManyParts},
+		{&ParseOptions{MaxPartSize: 64}, ErrPartTooLarge},
+		{&ParseOptions{MaxTotalSize: 1024}, ErrPackageTooLarge},
+	} {
+		if _, err = parse(data, c.opts); !errors.Is(err, c.err) {
+			t.Fatalf("%+v gives %v", *c.opts, err)
+		}
+	}
+
+	doc, err := parse(data, &ParseOptions{LazyMedia: true, MaxParts: 64, MaxPartSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.media[0].Data != nil {
+		t.Fatal("the media is loaded")
+	}
+	m, err := doc.media[0].Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, pic.Bytes()) {
+		t.Fatal("unexpected media data")
+	}
+	buf.Reset()
+	_, err = doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(doc.media[0].Data, pic.Bytes()) {
+		t.Fatal("the lazy media is not written")
+	}
+
+	// a malformed value is only skipped when not strict
+	bad := rewriteZip(t, data, func(name, content string) string {
+		if name != DOCUMENT_PART {
+			return content
+		}
+		return strings.Replace(content, "<w:jc ", `<w:ind w:left="abc"></w:ind><w:jc `, 1)
+	})
+	_, err = parse(bad, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parse(bad, &ParseOptions{Strict: true})
+	if err == nil {
+		t.Fatal("malformed document parsed in strict mode")
+	}
+	_, err = parse(data, &ParseOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddParagraph().AddText("first")
+	w.AddParagraph().Justification(JUSTIFICATION_CENTER).AddText("centered")
+	w.SectPr().AddHeader(HEADER_FOOTER_DEFAULT).AddParagraph().AddText("header")
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := rewriteZip(t, buf.Bytes(), func(name, content string) string {
+		switch name {
+		case DOCUMENT_PART:
+			return strings.Replace(content, "<w:jc ", `<w:ind w:left="abc"></w:ind><w:jc `, 1)
+		case "word/header1.xml":
+			return strings.Replace(content, "</w:p>", "</w:r>", 1)
+		}
+		return content
+	})
+	parse := func(opts *ParseOptions) (*Docx, error) {
+		return ParseWithOptions(bytes.NewReader(bad), int64(len(bad)), opts)
+	}
+
+	_, err = parse(nil)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Part != "word/header1.xml" || pe.Path != "p[0]" || pe.Offset == 0 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, err = parse(&ParseOptions{Strict: true})
+	if !errors.As(err, &pe) || pe.Part != DOCUMENT_PART || pe.Path != "body/p[1]/pPr/ind[0]" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	doc, err := parse(&ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := doc.Warnings()
+	if len(warnings) != 2 || warnings[0].Path != "body/p[1]/pPr/ind[0]" || warnings[1].Part != "word/header1.xml" {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	if len(doc.Headers()) != 0 {
+		t.Fatal("the malformed header is parsed")
+	}
+	data, err := doc.Part("word/header1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "</w:r>") {
+		t.Fatal("the malformed header is not kept")
+	}
+}
+
+func TestToleratedErrors(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		skip bool
+	}{
+		{&strconv.NumError{Func: "GetInt", Num: "abc", Err: strconv.ErrSyntax}, true},
+		{xml.UnmarshalError("expected element type <w:p> but have <w:r>"), true},
+		{&xml.SyntaxError{Msg: "unexpected EOF"}, false},
+		{&ParseError{Part: DOCUMENT_PART, Err: xml.UnmarshalError("expected element type <w:p> but have <w:r>")}, false},
+		{errors.New("expected integer"), false},
+	} {
+		if (*Docx)(nil).tolerated(c.err) != c.skip {
+			t.Fatalf("%#v is not classified", c.err)
+		}
+	}
+
+	w := New().WithDefaultTheme()
+	w.AddParagraph().Justification(JUSTIFICATION_CENTER).AddText("centered")
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := rewriteZip(t, buf.Bytes(), func(name, content string) string {
+		if name != DOCUMENT_PART {
+			return content
+		}
+		return strings.Replace(content, "<w:jc ", `<w:ind w:left="abc"></w:ind><w:jc `, 1)
+	})
+	next := func(opts *ParseOptions) (*StreamReader, error) {
+		s, err := OpenStreamWithOptions(bytes.NewReader(bad), int64(len(bad)), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = s.Next()
+		if s.doc.parsing != nil {
+			t.Fatal("the part is still being parsed")
+		}
+		return s, err
+	}
+	_, err = next(&ParseOptions{Strict: true})
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Path != "body/p[0]/pPr/ind[0]" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s, err := next(&ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := s.Docx().Warnings(); len(warnings) != 1 || warnings[0].Path != "body/p[0]/pPr/ind[0]" {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+}